@@ -105,12 +105,16 @@ func runAPI(cmd *cobra.Command, args []string) error {
 		SupabaseAnonKey:    supabaseAnonKey,
 		SupabaseJWTSecret:  os.Getenv("SUPABASE_JWT_SECRET"),
 		CronSyncSecret:     os.Getenv("GSC_SYNC_SECRET"),
+		CrawlStateDir:      os.Getenv("CRAWL_STATE_DIR"),
 		Logger:             logger,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to initialize API server: %w", err)
 	}
 
+	// Pick up web crawls left running by instances that stopped renewing their lease
+	go server.WatchInterruptedCrawls()
+
 	// Create HTTP server
 	// Increased WriteTimeout to 60s to accommodate AI operations which can take longer
 	httpServer := &http.Server{
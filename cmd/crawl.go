@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/dillonlara115/barracudaseo/internal/analyzer"
@@ -17,24 +19,49 @@ import (
 )
 
 var (
-	startURL         string
-	maxDepth         int
-	maxPages         int
-	workers          int
-	delay            time.Duration
-	timeout          time.Duration
-	userAgent        string
-	respectRobots    bool
-	parseSitemap     bool
-	crawlSitemapOnly bool
-	exportFormat     string
-	exportPath       string
-	domainFilter     string
-	graphExport      string
-	interactive      bool
-	openBrowser      bool
-	cloudUpload      bool
-	cloudProjectID   string
+	startURL            string
+	listPath            string
+	listColumn          string
+	maxDepth            int
+	maxPages            int
+	workers             int
+	delay               time.Duration
+	timeout             time.Duration
+	userAgent           string
+	parity              bool
+	mobileUserAgent     string
+	desktopUserAgent    string
+	respectRobots       bool
+	parseSitemap        bool
+	crawlSitemapOnly    bool
+	exportFormat        string
+	exportPath          string
+	domainFilter        string
+	subdomainScope      string
+	urlRules            []string
+	includePatterns     []string
+	excludePatterns     []string
+	stripParams         []string
+	headers             []string
+	cookieFile          string
+	basicAuth           string
+	loginURL            string
+	loginFields         []string
+	renderMode          string
+	crawlOrder          string
+	frontierMemory      int
+	pageWeightBudget    int
+	duplicateSimilarity float64
+	stateDir            string
+	resumeDir           string
+	checkpointEvery     time.Duration
+	previousResults     string
+	graphExport         string
+	linksExport         string
+	interactive         bool
+	openBrowser         bool
+	cloudUpload         bool
+	cloudProjectID      string
 )
 
 // crawlCmd represents the crawl command
@@ -53,11 +80,15 @@ func init() {
 	// URL flag (optional - can also be provided as positional argument)
 	crawlCmd.Flags().StringVarP(&startURL, "url", "u", "", "Starting URL to crawl")
 
+	// List mode: crawl exactly the given URLs instead of spidering from a start URL
+	crawlCmd.Flags().StringVar(&listPath, "list", "", "Crawl only the URLs in this file (one per line, or a CSV), or '-' for stdin; disables link discovery")
+	crawlCmd.Flags().StringVar(&listColumn, "list-column", "", "CSV column holding the URLs for --list, by header name or 1-based index (default: a url/address/page column)")
+
 	// Crawl options
 	crawlCmd.Flags().IntVarP(&maxDepth, "max-depth", "d", 3, "Maximum crawl depth")
 	crawlCmd.Flags().IntVarP(&maxPages, "max-pages", "p", 1000, "Maximum number of pages to crawl")
 	crawlCmd.Flags().IntVarP(&workers, "workers", "w", 10, "Number of concurrent workers")
-	crawlCmd.Flags().DurationVar(&delay, "delay", 0, "Delay between requests (e.g., 100ms)")
+	crawlCmd.Flags().DurationVar(&delay, "delay", 0, "Minimum delay between requests to the same host (e.g., 100ms)")
 	crawlCmd.Flags().DurationVar(&timeout, "timeout", 30*time.Second, "HTTP request timeout")
 	crawlCmd.Flags().StringVar(&userAgent, "user-agent", "barracuda/1.0.0", "User agent string")
 	crawlCmd.Flags().BoolVar(&respectRobots, "respect-robots", true, "Respect robots.txt")
@@ -65,10 +96,44 @@ func init() {
 	crawlCmd.Flags().BoolVar(&crawlSitemapOnly, "sitemap-only", false, "Crawl only sitemap URLs, no link discovery (requires --parse-sitemap)")
 	crawlCmd.Flags().StringVar(&domainFilter, "domain-filter", "same", "Domain filter: 'same' or 'all'")
 
+	// Parity options
+	crawlCmd.Flags().BoolVar(&parity, "parity", false, "Fetch every URL as both a smartphone and a desktop agent and report differences")
+	crawlCmd.Flags().StringVar(&mobileUserAgent, "mobile-user-agent", utils.DefaultMobileUserAgent, "Smartphone user agent for --parity")
+	crawlCmd.Flags().StringVar(&desktopUserAgent, "desktop-user-agent", utils.DefaultDesktopUserAgent, "Desktop user agent for --parity")
+
+	// Scope options
+	crawlCmd.Flags().StringVar(&subdomainScope, "subdomains", "none", "Subdomain scope: 'none' (start host only) or 'all' (every subdomain of the start domain)")
+	crawlCmd.Flags().StringArrayVar(&urlRules, "rule", nil, "Ordered URL rule on path and query, first match wins: include:<pattern> or exclude:<pattern> (glob with *, or re:<regex>; repeatable)")
+	crawlCmd.Flags().StringArrayVar(&includePatterns, "include", nil, "Only crawl URLs whose path and query match this pattern (glob with *, or re:<regex>; repeatable)")
+	crawlCmd.Flags().StringArrayVar(&excludePatterns, "exclude", nil, "Skip URLs whose path and query match this pattern (glob with *, or re:<regex>; repeatable)")
+	crawlCmd.Flags().StringSliceVar(&stripParams, "strip-param", nil, "Query parameters to strip before queueing URLs (globs like utm_*, or * for all; repeatable)")
+
+	crawlCmd.Flags().StringVar(&renderMode, "render", "static", "Render mode: 'static' (raw HTML) or 'js' (headless Chrome, set CHROME_PATH if not on PATH)")
+	crawlCmd.Flags().StringVar(&crawlOrder, "crawl-order", "bfs", "Crawl order: 'bfs' (shallow pages first), 'dfs' (deep pages first) or 'sitemap-first'")
+	crawlCmd.Flags().IntVar(&frontierMemory, "frontier-memory", crawler.DefaultFrontierMemoryLimit, "Queued URLs kept in memory before spilling to disk")
+	crawlCmd.Flags().IntVar(&pageWeightBudget, "page-weight-budget", analyzer.DefaultPageWeightBudgetKB, "Report pages whose HTML plus subresources exceed this many KB")
+	crawlCmd.Flags().Float64Var(&duplicateSimilarity, "duplicate-similarity", analyzer.DefaultDuplicateSimilarity, "Share of matching content fingerprint bits (0-1) at which pages are reported as near duplicates")
+
+	// Authentication options
+	crawlCmd.Flags().StringArrayVarP(&headers, "header", "H", nil, "Extra request header sent to the crawled site, as \"Name: value\" (repeatable)")
+	crawlCmd.Flags().StringVar(&cookieFile, "cookie-file", "", "Seed the cookie jar from a Netscape cookies.txt file")
+	crawlCmd.Flags().StringVar(&basicAuth, "basic-auth", "", "HTTP basic auth credentials as user:password (or set BARRACUDA_BASIC_AUTH)")
+	crawlCmd.Flags().StringVar(&loginURL, "login-url", "", "Page with a login form to submit before crawling")
+	crawlCmd.Flags().StringArrayVar(&loginFields, "login-field", nil, "Login form field as name=value, e.g. --login-field email=me@example.com (repeatable)")
+
+	// Checkpoint options
+	crawlCmd.Flags().StringVar(&stateDir, "state-dir", "", "Directory to write crawl checkpoints to (enables resuming with --resume)")
+	crawlCmd.Flags().StringVar(&resumeDir, "resume", "", "Resume an interrupted crawl from the checkpoint in this state directory (pass authentication flags again)")
+	crawlCmd.Flags().DurationVar(&checkpointEvery, "checkpoint-interval", crawler.DefaultCheckpointInterval, "How often to write checkpoints when --state-dir or --resume is set")
+
+	// Incremental options
+	crawlCmd.Flags().StringVar(&previousResults, "previous", "", "JSON results of a previous crawl; unchanged pages are detected with conditional requests and reused")
+
 	// Export options
 	crawlCmd.Flags().StringVarP(&exportFormat, "format", "f", "csv", "Export format: 'csv' or 'json'")
 	crawlCmd.Flags().StringVarP(&exportPath, "export", "e", "", "Export file path (default: stdout or results.csv/json)")
 	crawlCmd.Flags().StringVar(&graphExport, "graph-export", "", "Export link graph to JSON file")
+	crawlCmd.Flags().StringVar(&linksExport, "links-export", "", "Export every link with its anchor text, rel and position to a CSV file (or JSON when the path ends in .json)")
 
 	// Interactive mode
 	crawlCmd.Flags().BoolVarP(&interactive, "interactive", "i", false, "Run in interactive mode with prompts")
@@ -88,7 +153,8 @@ func runCrawl(cmd *cobra.Command, args []string) error {
 	if !shouldRunInteractive && startURL == "" && len(args) == 0 {
 		// Check if any flags were provided
 		hasFlags := maxDepth != 3 || maxPages != 1000 || workers != 10 || exportFormat != "csv" ||
-			exportPath != "" || graphExport != "" || respectRobots != true || parseSitemap != false
+			exportPath != "" || graphExport != "" || linksExport != "" || respectRobots != true || parseSitemap != false || resumeDir != "" || previousResults != "" || listPath != "" ||
+			len(urlRules) > 0 || len(includePatterns) > 0 || len(excludePatterns) > 0
 		if !hasFlags {
 			shouldRunInteractive = true
 		}
@@ -113,18 +179,23 @@ func runCrawl(cmd *cobra.Command, args []string) error {
 		respectRobots = config.RespectRobots
 		parseSitemap = config.ParseSitemap
 		crawlSitemapOnly = config.CrawlSitemapOnly
+		subdomainScope = config.SubdomainScope
+		stripParams = config.StripQueryParams
+		for _, rule := range config.URLRules {
+			urlRules = append(urlRules, rule.Action+":"+rule.Pattern)
+		}
 		graphExport = graphExportPath
 		crawlDir = dir
 		openBrowser = shouldOpen // Use interactive preference
-	} else {
+	} else if resumeDir == "" {
 		// Get URL from positional argument or flag
 		if len(args) > 0 {
 			startURL = args[0]
 		}
 
 		// Validate that URL is provided
-		if startURL == "" {
-			return fmt.Errorf("starting URL is required. Provide it as an argument, use --url flag, or run with --interactive")
+		if startURL == "" && listPath == "" {
+			return fmt.Errorf("starting URL is required. Provide it as an argument, use --url flag, use --list, or run with --interactive")
 		}
 	}
 
@@ -134,21 +205,86 @@ func runCrawl(cmd *cobra.Command, args []string) error {
 	}
 	defer utils.Sync()
 
+	rules, err := utils.BuildURLRules(urlRules, includePatterns, excludePatterns)
+	if err != nil {
+		return err
+	}
+
+	auth, err := buildAuthConfig()
+	if err != nil {
+		return err
+	}
+
+	// List mode: the listed URLs are the whole crawl
+	var urlList []string
+	if listPath != "" {
+		var skipped int
+		urlList, skipped, err = utils.LoadURLList(listPath, listColumn)
+		if err != nil {
+			return err
+		}
+		if len(urlList) == 0 {
+			return fmt.Errorf("no http(s) URLs found in %s", listPath)
+		}
+		if skipped > 0 {
+			utils.Warn("Skipped list entries that are not http(s) URLs", utils.NewField("count", skipped))
+		}
+		if startURL == "" {
+			startURL = urlList[0]
+		}
+		if !cmd.Flags().Changed("max-pages") {
+			maxPages = len(urlList)
+		}
+	}
+
 	// Create config
 	config := &utils.Config{
-		StartURL:         startURL,
-		MaxDepth:         maxDepth,
-		MaxPages:         maxPages,
-		Workers:          workers,
-		Delay:            delay,
-		Timeout:          timeout,
-		UserAgent:        userAgent,
-		RespectRobots:    respectRobots,
-		ParseSitemap:     parseSitemap,
-		CrawlSitemapOnly: crawlSitemapOnly,
-		ExportFormat:     exportFormat,
-		ExportPath:       exportPath,
-		DomainFilter:     domainFilter,
+		StartURL:            startURL,
+		URLList:             urlList,
+		MaxDepth:            maxDepth,
+		MaxPages:            maxPages,
+		Workers:             workers,
+		Delay:               delay,
+		Timeout:             timeout,
+		UserAgent:           userAgent,
+		Parity:              parity,
+		MobileUserAgent:     mobileUserAgent,
+		DesktopUserAgent:    desktopUserAgent,
+		RespectRobots:       respectRobots,
+		ParseSitemap:        parseSitemap,
+		CrawlSitemapOnly:    crawlSitemapOnly,
+		ExportFormat:        exportFormat,
+		ExportPath:          exportPath,
+		DomainFilter:        domainFilter,
+		SubdomainScope:      subdomainScope,
+		URLRules:            rules,
+		StripQueryParams:    stripParams,
+		Auth:                auth,
+		RenderMode:          renderMode,
+		CrawlOrder:          crawlOrder,
+		FrontierMemoryLimit: frontierMemory,
+		PageWeightBudgetKB:  pageWeightBudget,
+		DuplicateSimilarity: duplicateSimilarity,
+		Incremental:         previousResults != "",
+		StateDir:            stateDir,
+		CheckpointInterval:  checkpointEvery,
+	}
+
+	// When resuming, the crawl configuration comes from the checkpoint
+	var checkpoint *crawler.Checkpoint
+	if resumeDir != "" {
+		cp, err := crawler.LoadCheckpoint(resumeDir)
+		if err != nil {
+			return fmt.Errorf("failed to resume crawl: %w", err)
+		}
+		if cp.Completed {
+			return fmt.Errorf("crawl in %s already completed; nothing to resume", resumeDir)
+		}
+		checkpoint = cp
+		config = cp.Config
+		config.Auth = auth // Checkpoints do not store credentials
+		config.StateDir = resumeDir
+		config.CheckpointInterval = checkpointEvery
 	}
 
 	// Validate config
@@ -169,6 +305,16 @@ func runCrawl(cmd *cobra.Command, args []string) error {
 
 	// Create crawler manager
 	manager := crawler.NewManager(config)
+	if checkpoint != nil {
+		manager.Resume(checkpoint)
+	}
+	if previousResults != "" {
+		baseline, err := crawler.LoadBaseline(previousResults)
+		if err != nil {
+			return err
+		}
+		manager.SetBaseline(baseline)
+	}
 
 	// Start crawling
 	results, err := manager.Crawl()
@@ -178,8 +324,18 @@ func runCrawl(cmd *cobra.Command, args []string) error {
 
 	utils.Info("Crawl completed", utils.NewField("pages_crawled", len(results)))
 
-	// Analyze results and print summary (including image size checking)
+	if manager.Interrupted() && config.StateDir != "" {
+		fmt.Fprintf(os.Stdout, "\n⏸  Crawl interrupted. Resume with: barracuda crawl --resume %s\n", config.StateDir)
+	}
+
+	// Analyze results and print summary (including image size and subresource checks)
 	summary := analyzer.AnalyzeWithImages(results, config.Timeout)
+	summary.AddIssues(analyzer.AnalyzeResources(results, manager.HTTPClient(), config.PageWeightBudgetKB))
+	summary.DuplicateContent = analyzer.FindDuplicateContent(results, config.DuplicateSimilarity)
+	summary.AddIssues(analyzer.DuplicateContentIssues(summary.DuplicateContent))
+	robotsReport := manager.RobotsReport()
+	summary.AddIssues(analyzer.AnalyzeRobots(robotsReport))
+	summary.AddIssues(analyzer.AnalyzeSitemap(results, manager.GetLinkGraph(), robotsReport))
 	analyzer.PrintSummary(summary)
 
 	// Export results
@@ -194,8 +350,17 @@ func runCrawl(cmd *cobra.Command, args []string) error {
 		}
 		fmt.Fprintf(os.Stdout, "✓ Link graph exported to %s\n", graphExport)
 	}
+	if linksExport != "" {
+		if err := exportLinks(manager.GetLinkGraph(), linksExport); err != nil {
+			return fmt.Errorf("links export failed: %w", err)
+		}
+		fmt.Fprintf(os.Stdout, "✓ Links exported to %s\n", linksExport)
+	}
 
 	fmt.Fprintf(os.Stdout, "\n✓ Crawled %d pages\n", len(results))
+	if config.Incremental {
+		fmt.Fprintf(os.Stdout, "✓ %d pages unchanged since previous crawl\n", manager.UnchangedPages())
+	}
 	fmt.Fprintf(os.Stdout, "✓ Results exported to %s\n", config.ExportPath)
 
 	if crawlDir != "" {
@@ -259,6 +424,15 @@ func exportLinkGraph(graph *graph.Graph, filePath string) error {
 	return nil
 }
 
+// exportLinks writes every link of the graph to CSV, or JSON when the path ends in .json
+func exportLinks(graph *graph.Graph, filePath string) error {
+	links := graph.GetLinkList()
+	if strings.EqualFold(filepath.Ext(filePath), ".json") {
+		return exporter.ExportLinksJSON(links, filePath, true)
+	}
+	return exporter.ExportLinksCSV(links, filePath)
+}
+
 func exportResults(results []*models.PageResult, config *utils.Config) error {
 	switch config.ExportFormat {
 	case "csv":
@@ -269,3 +443,51 @@ func exportResults(results []*models.PageResult, config *utils.Config) error {
 		return fmt.Errorf("unsupported export format: %s", config.ExportFormat)
 	}
 }
+
+// buildAuthConfig assembles crawl credentials from the authentication flags
+func buildAuthConfig() (utils.AuthConfig, error) {
+	auth := utils.AuthConfig{
+		CookieFile: cookieFile,
+		LoginURL:   loginURL,
+	}
+
+	for _, raw := range headers {
+		name, value, err := utils.ParseHeader(raw)
+		if err != nil {
+			return auth, err
+		}
+		if auth.Headers == nil {
+			auth.Headers = make(map[string]string)
+		}
+		auth.Headers[name] = value
+	}
+
+	credentials := basicAuth
+	if credentials == "" {
+		credentials = os.Getenv("BARRACUDA_BASIC_AUTH")
+	}
+	if credentials != "" {
+		username, password, ok := strings.Cut(credentials, ":")
+		if !ok {
+			return auth, fmt.Errorf("invalid basic auth credentials: expected user:password")
+		}
+		auth.BasicUsername = username
+		auth.BasicPassword = password
+	}
+
+	for _, raw := range loginFields {
+		name, value, err := utils.ParseKeyValue(raw)
+		if err != nil {
+			return auth, fmt.Errorf("invalid login field: %w", err)
+		}
+		if auth.LoginFields == nil {
+			auth.LoginFields = make(map[string]string)
+		}
+		auth.LoginFields[name] = value
+	}
+	if len(auth.LoginFields) > 0 && auth.LoginURL == "" {
+		return auth, fmt.Errorf("--login-field requires --login-url")
+	}
+
+	return auth, nil
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/dillonlara115/barracudaseo/internal/crawler"
+	"github.com/dillonlara115/barracudaseo/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+var (
+	robotsAgent string
+	robotsFile  string
+)
+
+var robotsCmd = &cobra.Command{
+	Use:   "robots",
+	Short: "Inspect robots.txt rules",
+}
+
+var robotsTestCmd = &cobra.Command{
+	Use:   "test <url>",
+	Short: "Explain whether robots.txt allows a URL for a user agent",
+	Long: `Test a URL against the site's live robots.txt (or a local file with --file) and show
+which user-agent group and which Allow/Disallow rule decided the result.`,
+	Example: `  barracuda robots test https://example.com/private/page --agent Googlebot
+  barracuda robots test https://example.com/search?q=x --file ./robots.txt`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRobotsTest,
+}
+
+func init() {
+	robotsTestCmd.Flags().StringVar(&robotsAgent, "agent", "Googlebot", "User agent to test as")
+	robotsTestCmd.Flags().StringVar(&robotsFile, "file", "", "Local robots.txt file to test against instead of the live one")
+
+	robotsCmd.AddCommand(robotsTestCmd)
+	rootCmd.AddCommand(robotsCmd)
+}
+
+func runRobotsTest(cmd *cobra.Command, args []string) error {
+	targetURL := args[0]
+
+	var decision *crawler.RobotsDecision
+	var host models.RobotsHost
+	if robotsFile != "" {
+		data, err := os.ReadFile(robotsFile)
+		if err != nil {
+			return fmt.Errorf("failed to read robots.txt: %w", err)
+		}
+		file := crawler.ParseRobots(data)
+		host = models.RobotsHost{URL: robotsFile, StatusCode: 200, Warnings: file.Warnings}
+		if decision, err = file.Decide(robotsAgent, targetURL); err != nil {
+			return err
+		}
+	} else {
+		checker := crawler.NewRobotsChecker(crawler.NewFetcher(30*time.Second, "barracuda/1.0.0"), robotsAgent, true)
+		var err error
+		if decision, err = checker.Check(targetURL); err != nil {
+			return err
+		}
+		if hosts := checker.Hosts(); len(hosts) > 0 {
+			host = hosts[0]
+		}
+	}
+
+	fmt.Fprintf(os.Stdout, "URL:         %s\n", targetURL)
+	switch {
+	case robotsFile != "":
+		fmt.Fprintf(os.Stdout, "robots.txt:  %s (local file)\n", host.URL)
+	case host.StatusCode == 0:
+		fmt.Fprintf(os.Stdout, "robots.txt:  %s (fetch failed: %s)\n", host.URL, host.Error)
+	default:
+		fmt.Fprintf(os.Stdout, "robots.txt:  %s (HTTP %d)\n", host.URL, host.StatusCode)
+	}
+	fmt.Fprintf(os.Stdout, "User agent:  %s\n", robotsAgent)
+
+	switch {
+	case decision.Unavailable:
+		fmt.Fprintf(os.Stdout, "Group:       -\n")
+		fmt.Fprintf(os.Stdout, "Rule:        - (robots.txt unavailable; server errors disallow everything, as with Google)\n")
+	case host.StatusCode == 0:
+		fmt.Fprintf(os.Stdout, "Group:       -\n")
+		fmt.Fprintf(os.Stdout, "Rule:        - (robots.txt could not be fetched; barracuda crawls anyway, Google treats this as disallow all)\n")
+	case host.StatusCode != 200:
+		fmt.Fprintf(os.Stdout, "Group:       -\n")
+		fmt.Fprintf(os.Stdout, "Rule:        - (no robots.txt; everything is allowed)\n")
+	case decision.Group == "":
+		fmt.Fprintf(os.Stdout, "Group:       - (no group applies to this agent)\n")
+		fmt.Fprintf(os.Stdout, "Rule:        - (no rule matched; allowed by default)\n")
+	case decision.Rule == nil:
+		fmt.Fprintf(os.Stdout, "Group:       User-agent: %s\n", decision.Group)
+		fmt.Fprintf(os.Stdout, "Rule:        - (no rule matched; allowed by default)\n")
+	default:
+		fmt.Fprintf(os.Stdout, "Group:       User-agent: %s\n", decision.Group)
+		fmt.Fprintf(os.Stdout, "Rule:        %s\n", decision.Rule)
+	}
+
+	if decision.Allowed {
+		fmt.Fprintf(os.Stdout, "Result:      ✓ ALLOWED\n")
+	} else {
+		fmt.Fprintf(os.Stdout, "Result:      ✗ BLOCKED\n")
+	}
+
+	if len(host.Warnings) > 0 {
+		fmt.Fprintf(os.Stdout, "\n⚠️  robots.txt syntax problems:\n")
+		for _, warning := range host.Warnings {
+			fmt.Fprintf(os.Stdout, "   %s\n", warning)
+		}
+	}
+	return nil
+}
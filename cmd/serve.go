@@ -12,6 +12,7 @@ import (
 
 	"github.com/dillonlara115/barracudaseo/internal/analyzer"
 	"github.com/dillonlara115/barracudaseo/internal/exporter"
+	"github.com/dillonlara115/barracudaseo/internal/graph"
 	"github.com/dillonlara115/barracudaseo/internal/gsc"
 	"github.com/dillonlara115/barracudaseo/pkg/models"
 	"github.com/spf13/cobra"
@@ -79,11 +80,6 @@ func runServe(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	if summary == nil {
-		// Generate summary from results
-		summary = analyzer.AnalyzeWithImages(results, 30*1000*1000*1000) // 30s timeout
-	}
-
 	// Load graph if provided
 	var graphData map[string][]string
 	if serveGraph != "" {
@@ -93,6 +89,38 @@ func runServe(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if summary == nil {
+		// Generate summary from results
+		summary = analyzer.AnalyzeWithImages(results, 30*1000*1000*1000) // 30s timeout
+		summary.AddIssues(analyzer.AnalyzeResources(results, &http.Client{Timeout: 30 * time.Second}, analyzer.DefaultPageWeightBudgetKB))
+		summary.DuplicateContent = analyzer.FindDuplicateContent(results, analyzer.DefaultDuplicateSimilarity)
+		summary.AddIssues(analyzer.DuplicateContentIssues(summary.DuplicateContent))
+
+		// Orphaned sitemap URLs can only be detected with the link graph
+		var linkGraph *graph.Graph
+		if graphData != nil {
+			linkGraph = graph.NewGraph()
+			for source, targets := range graphData {
+				linkGraph.AddEdges(source, targets)
+			}
+		}
+		summary.AddIssues(analyzer.AnalyzeSitemap(results, linkGraph, nil))
+	}
+
+	// Summaries saved before TLS details were collected have no security section
+	if summary.Security == nil {
+		summary.Security = analyzer.BuildSecuritySummary(results, time.Now())
+	}
+	if summary.Performance == nil {
+		summary.Performance = analyzer.BuildPerformanceSummary(results)
+	}
+	if summary.StructuredData == nil {
+		summary.StructuredData = analyzer.BuildStructuredDataSummary(results)
+	}
+	if summary.DuplicateContent == nil {
+		summary.DuplicateContent = analyzer.FindDuplicateContent(results, analyzer.DefaultDuplicateSimilarity)
+	}
+
 	// Setup API routes first (must be before catch-all handler)
 	apiMux := http.NewServeMux()
 	apiMux.HandleFunc("/api/results", func(w http.ResponseWriter, r *http.Request) {
@@ -107,6 +135,43 @@ func runServe(cmd *cobra.Command, args []string) error {
 		json.NewEncoder(w).Encode(summary)
 	})
 
+	apiMux.HandleFunc("/api/security", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		json.NewEncoder(w).Encode(summary.Security)
+	})
+
+	apiMux.HandleFunc("/api/performance", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		json.NewEncoder(w).Encode(summary.Performance)
+	})
+
+	apiMux.HandleFunc("/api/structured-data", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		json.NewEncoder(w).Encode(summary.StructuredData)
+	})
+
+	apiMux.HandleFunc("/api/duplicates", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		json.NewEncoder(w).Encode(summary.DuplicateContent)
+	})
+
+	apiMux.HandleFunc("/api/links", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		links := make([]models.Link, 0)
+		for _, result := range results {
+			for _, link := range result.Links {
+				link.Source = result.URL
+				links = append(links, link)
+			}
+		}
+		json.NewEncoder(w).Encode(links)
+	})
+
 	apiMux.HandleFunc("/api/graph", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		w.Header().Set("Access-Control-Allow-Origin", "*")
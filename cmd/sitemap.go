@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dillonlara115/barracudaseo/internal/crawler"
+	"github.com/dillonlara115/barracudaseo/internal/exporter"
+	"github.com/dillonlara115/barracudaseo/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+var (
+	sitemapResults  string
+	sitemapOutput   string
+	sitemapBaseURL  string
+	sitemapGzip     bool
+	sitemapLastMod  string
+	sitemapPrevious string
+	sitemapMaxURLs  int
+)
+
+var sitemapCmd = &cobra.Command{
+	Use:   "sitemap",
+	Short: "Work with XML sitemaps",
+}
+
+var sitemapGenerateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate sitemap.xml files from crawl results",
+	Long: `Generate sitemap.xml from a crawl results file. Only pages that returned 200 without
+redirects, are indexable and canonicalize to themselves are included. Sitemaps are split at
+50,000 URLs or 50MB, with a sitemap index (sitemap.xml) listing the parts.`,
+	Example: `  barracuda sitemap generate --results results.json --output ./public
+  barracuda sitemap generate --results results.csv --gzip --base-url https://example.com/sitemaps/
+  barracuda sitemap generate --results results.json --lastmod previous --previous-sitemap https://example.com/sitemap.xml`,
+	RunE: runSitemapGenerate,
+}
+
+func init() {
+	sitemapGenerateCmd.Flags().StringVar(&sitemapResults, "results", "results.json", "Path to JSON or CSV results file")
+	sitemapGenerateCmd.Flags().StringVarP(&sitemapOutput, "output", "o", ".", "Directory to write sitemap files to")
+	sitemapGenerateCmd.Flags().StringVar(&sitemapBaseURL, "base-url", "", "URL the sitemap files will be served from, used in the sitemap index (default: site root)")
+	sitemapGenerateCmd.Flags().BoolVar(&sitemapGzip, "gzip", false, "Write gzipped .xml.gz files")
+	sitemapGenerateCmd.Flags().StringVar(&sitemapLastMod, "lastmod", exporter.LastModCrawl, "Source of <lastmod>: crawl, previous or none")
+	sitemapGenerateCmd.Flags().StringVar(&sitemapPrevious, "previous-sitemap", "", "Previous sitemap (file or URL) to take <lastmod> from with --lastmod previous")
+	sitemapGenerateCmd.Flags().IntVar(&sitemapMaxURLs, "max-urls", exporter.SitemapMaxURLs, "Maximum URLs per sitemap file")
+
+	sitemapCmd.AddCommand(sitemapGenerateCmd)
+	rootCmd.AddCommand(sitemapCmd)
+}
+
+func runSitemapGenerate(cmd *cobra.Command, args []string) error {
+	switch sitemapLastMod {
+	case exporter.LastModCrawl, exporter.LastModPrevious, exporter.LastModNone:
+	default:
+		return fmt.Errorf("invalid --lastmod %q: must be crawl, previous or none", sitemapLastMod)
+	}
+
+	var results []*models.PageResult
+	if strings.HasSuffix(strings.ToLower(sitemapResults), ".csv") {
+		var err error
+		results, err = exporter.ImportCSV(sitemapResults)
+		if err != nil {
+			return fmt.Errorf("failed to import CSV: %w", err)
+		}
+	} else {
+		data, err := os.ReadFile(sitemapResults)
+		if err != nil {
+			return fmt.Errorf("failed to read results file: %w", err)
+		}
+		if err := json.Unmarshal(data, &results); err != nil {
+			return fmt.Errorf("failed to parse results JSON: %w", err)
+		}
+	}
+
+	previous := make(map[string]string)
+	if sitemapPrevious != "" {
+		parser := crawler.NewSitemapParser(crawler.NewFetcher(30*time.Second, "barracuda/1.0.0"))
+		var entries []*models.SitemapEntry
+		var err error
+		if strings.HasPrefix(sitemapPrevious, "http://") || strings.HasPrefix(sitemapPrevious, "https://") {
+			entries, err = parser.ParseSitemaps([]string{sitemapPrevious})
+		} else {
+			entries, err = parser.ParseSitemapFile(sitemapPrevious)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read previous sitemap: %w", err)
+		}
+		for _, entry := range entries {
+			previous[entry.Loc] = entry.LastMod
+		}
+	} else if sitemapLastMod == exporter.LastModPrevious {
+		fmt.Fprintf(os.Stdout, "No --previous-sitemap given; using lastmod from the crawled sitemaps, then the crawl time\n")
+	}
+
+	urls := exporter.SitemapURLs(results, sitemapLastMod, previous)
+	files, err := exporter.BuildSitemaps(urls, exporter.SitemapOptions{
+		BaseURL: sitemapBaseURL,
+		Gzip:    sitemapGzip,
+		MaxURLs: sitemapMaxURLs,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to build sitemap: %w", err)
+	}
+	if err := exporter.WriteSitemaps(files, sitemapOutput); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stdout, "Included %d of %d crawled URLs (%d excluded as non-200, redirected, non-indexable or canonicalized)\n",
+		len(urls), len(results), len(results)-len(urls))
+	for i, file := range files {
+		kind := "URLs"
+		if len(files) > 1 && i == 0 {
+			kind = "sitemaps"
+		}
+		fmt.Fprintf(os.Stdout, "  %s (%d %s)\n", filepath.Join(sitemapOutput, file.Name), file.URLs, kind)
+	}
+	return nil
+}
@@ -12,7 +12,6 @@ require (
 	github.com/spf13/cobra v1.8.0
 	github.com/stripe/stripe-go/v78 v78.1.0
 	github.com/supabase-community/supabase-go v0.0.4
-	github.com/temoto/robotstxt v1.1.2
 	go.uber.org/zap v1.26.0
 	golang.org/x/oauth2 v0.34.0
 	google.golang.org/api v0.258.0
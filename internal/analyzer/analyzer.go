@@ -30,6 +30,84 @@ const (
 	IssueBrokenLink      IssueType = "broken_link"
 	IssueMultipleH1      IssueType = "multiple_h1"
 	IssueEmptyH1         IssueType = "empty_h1"
+	IssueJSOnlyContent   IssueType = "js_only_content"
+
+	// Redirect hops
+	IssueRedirectLoop        IssueType = "redirect_loop"
+	IssueTooManyRedirects    IssueType = "too_many_redirects"
+	IssueRedirectToError     IssueType = "redirect_to_error"
+	IssueTemporaryRedirect   IssueType = "temporary_redirect"
+	IssueHTTPToHTTPSRedirect IssueType = "http_to_https_redirect"
+	IssueWWWRedirect         IssueType = "www_redirect"
+
+	// Mobile vs desktop parity (parity crawls only)
+	IssueMobileStatusMismatch  IssueType = "mobile_status_mismatch"
+	IssueMobileRobotsMismatch  IssueType = "mobile_robots_mismatch"
+	IssueMobileContentMismatch IssueType = "mobile_content_mismatch"
+
+	// robots.txt (see AnalyzeRobots)
+	IssueRobotsTxtUnreachable IssueType = "robots_txt_unreachable"
+	IssueRobotsTxtSyntax      IssueType = "robots_txt_syntax"
+	IssueBlockedByRobots      IssueType = "blocked_by_robots"
+
+	// Sitemap audit (see AnalyzeSitemap)
+	IssueSitemapNon200        IssueType = "sitemap_non_200"
+	IssueSitemapRedirect      IssueType = "sitemap_redirect"
+	IssueSitemapNoindex       IssueType = "sitemap_noindex"
+	IssueSitemapCanonicalized IssueType = "sitemap_canonicalized"
+	IssueSitemapBlocked       IssueType = "sitemap_blocked"
+	IssueSitemapOrphan        IssueType = "sitemap_orphan"
+	IssueMissingFromSitemap   IssueType = "missing_from_sitemap"
+
+	// Response headers
+	IssueMissingCompression      IssueType = "missing_compression"
+	IssueMissingCacheHeaders     IssueType = "missing_cache_headers"
+	IssueMissingSecurityHeaders  IssueType = "missing_security_headers"
+	IssueWeakSecurityHeaders     IssueType = "weak_security_headers"
+	IssueCanonicalHeaderConflict IssueType = "canonical_header_conflict"
+
+	// TLS and mixed content
+	IssueMixedContent        IssueType = "mixed_content"
+	IssueCertificateInvalid  IssueType = "certificate_invalid"
+	IssueCertificateExpiring IssueType = "certificate_expiring"
+	IssueOldTLSVersion       IssueType = "old_tls_version"
+
+	// Subresources
+	IssueBrokenResource       IssueType = "broken_resource"
+	IssueRenderBlockingScript IssueType = "render_blocking_script"
+	IssuePageWeight           IssueType = "page_weight_over_budget"
+
+	// Network timing
+	IssueSlowTTFB     IssueType = "slow_ttfb"
+	IssueSlowDownload IssueType = "slow_download"
+
+	// Structured data
+	IssueStructuredDataInvalid            IssueType = "structured_data_invalid"
+	IssueStructuredDataMissingRequired    IssueType = "structured_data_missing_required"
+	IssueStructuredDataMissingRecommended IssueType = "structured_data_missing_recommended"
+
+	// Hreflang
+	IssueHreflangInvalidCode        IssueType = "hreflang_invalid_code"
+	IssueHreflangMissingSelf        IssueType = "hreflang_missing_self_reference"
+	IssueHreflangMissingXDefault    IssueType = "hreflang_missing_x_default"
+	IssueHreflangMissingReturn      IssueType = "hreflang_missing_return_link"
+	IssueHreflangTargetNon200       IssueType = "hreflang_target_not_200"
+	IssueHreflangTargetNoindex      IssueType = "hreflang_target_noindex"
+	IssueHreflangTargetNonCanonical IssueType = "hreflang_target_not_canonical"
+	IssueHreflangLangMismatch       IssueType = "hreflang_html_lang_mismatch"
+
+	// Social metadata
+	IssueMissingOpenGraph     IssueType = "missing_open_graph"
+	IssueOGURLMismatch        IssueType = "og_url_mismatch"
+	IssueBrokenSocialImage    IssueType = "broken_social_image"
+	IssueSmallSocialImage     IssueType = "small_social_image"
+	IssueDuplicateSocialTitle IssueType = "duplicate_social_title"
+
+	// Content
+	IssueDuplicateContent  IssueType = "duplicate_content"
+	IssueDuplicateTitle    IssueType = "duplicate_title"
+	IssueDuplicateMetaDesc IssueType = "duplicate_meta_description"
+	IssueDuplicateH1       IssueType = "duplicate_h1"
 )
 
 // Issue represents a detected SEO issue
@@ -44,16 +122,21 @@ type Issue struct {
 
 // Summary contains analysis results and statistics
 type Summary struct {
-	TotalPages          int               `json:"total_pages"`
-	TotalIssues         int               `json:"total_issues"`
-	IssuesByType        map[IssueType]int `json:"issues_by_type"`
-	Issues              []Issue           `json:"issues"`
-	AverageResponseTime int64             `json:"average_response_time_ms"`
-	PagesWithErrors     int               `json:"pages_with_errors"`
-	PagesWithRedirects  int               `json:"pages_with_redirects"`
-	TotalInternalLinks  int               `json:"total_internal_links"`
-	TotalExternalLinks  int               `json:"total_external_links"`
-	SlowestPages        []PagePerformance `json:"slowest_pages,omitempty"`
+	TotalPages          int                      `json:"total_pages"`
+	TotalIssues         int                      `json:"total_issues"`
+	IssuesByType        map[IssueType]int        `json:"issues_by_type"`
+	Issues              []Issue                  `json:"issues"`
+	AverageResponseTime int64                    `json:"average_response_time_ms"`
+	PagesWithErrors     int                      `json:"pages_with_errors"`
+	PagesWithRedirects  int                      `json:"pages_with_redirects"`
+	TotalInternalLinks  int                      `json:"total_internal_links"`
+	TotalExternalLinks  int                      `json:"total_external_links"`
+	SlowestPages        []PagePerformance        `json:"slowest_pages,omitempty"`
+	Security            *SecuritySummary         `json:"security,omitempty"`
+	Performance         *PerformanceSummary      `json:"performance,omitempty"`
+	StructuredData      *StructuredDataSummary   `json:"structured_data,omitempty"`
+	DuplicateContent    *DuplicateContentSummary `json:"duplicate_content,omitempty"`
+	DuplicateGroups     []DuplicateGroup         `json:"duplicate_groups,omitempty"` // Titles, meta descriptions and H1s shared by several pages
 }
 
 // PagePerformance tracks page performance metrics
@@ -91,6 +174,18 @@ func Analyze(results []*models.PageResult) *Summary {
 			})
 		}
 
+		// Compare with the desktop version (parity crawls), before error pages are skipped
+		for _, issue := range parityIssues(result) {
+			summary.Issues = append(summary.Issues, issue)
+			summary.IssuesByType[issue.Type]++
+		}
+
+		// Audit redirect hops, including chains that end in errors or loop
+		for _, issue := range redirectIssues(result) {
+			summary.Issues = append(summary.Issues, issue)
+			summary.IssuesByType[issue.Type]++
+		}
+
 		// Track errors
 		if result.Error != "" || result.StatusCode >= 400 {
 			summary.PagesWithErrors++
@@ -122,13 +217,34 @@ func Analyze(results []*models.PageResult) *Summary {
 				Type:           IssueRedirectChain,
 				Severity:       "warning",
 				URL:            result.URL,
-				Message:        fmt.Sprintf("Redirect chain: %s", strings.Join(result.RedirectChain, " -> ")),
+				Message:        fmt.Sprintf("Redirect chain: %s", redirectPath(result)),
 				Value:          strings.Join(result.RedirectChain, " -> "),
 				Recommendation: "Consider using direct links instead of redirect chains",
 			})
 			summary.IssuesByType[IssueRedirectChain]++
 		}
 
+		// Audit response headers (compression, caching, security, Link canonical)
+		for _, issue := range headerIssues(result) {
+			summary.Issues = append(summary.Issues, issue)
+			summary.IssuesByType[issue.Type]++
+		}
+
+		for _, issue := range mixedContentIssues(result) {
+			summary.Issues = append(summary.Issues, issue)
+			summary.IssuesByType[issue.Type]++
+		}
+
+		for _, issue := range renderBlockingIssues(result) {
+			summary.Issues = append(summary.Issues, issue)
+			summary.IssuesByType[issue.Type]++
+		}
+
+		for _, issue := range timingIssues(result) {
+			summary.Issues = append(summary.Issues, issue)
+			summary.IssuesByType[issue.Type]++
+		}
+
 		// Count links
 		summary.TotalInternalLinks += len(result.InternalLinks)
 		summary.TotalExternalLinks += len(result.ExternalLinks)
@@ -240,8 +356,8 @@ func Analyze(results []*models.PageResult) *Summary {
 			summary.IssuesByType[IssueEmptyH1]++
 		}
 
-		// Check canonical
-		if result.Canonical == "" {
+		// Check canonical (a Link: rel="canonical" response header counts too)
+		if result.Canonical == "" && (result.Headers == nil || result.Headers.LinkCanonical == "") {
 			summary.Issues = append(summary.Issues, Issue{
 				Type:           IssueNoCanonical,
 				Severity:       "info",
@@ -251,7 +367,61 @@ func Analyze(results []*models.PageResult) *Summary {
 			})
 			summary.IssuesByType[IssueNoCanonical]++
 		}
+
+		// Check for SEO content that only exists after JavaScript rendering
+		if jsOnly := jsOnlyFields(result); len(jsOnly) > 0 {
+			summary.Issues = append(summary.Issues, Issue{
+				Type:           IssueJSOnlyContent,
+				Severity:       "warning",
+				URL:            result.URL,
+				Message:        fmt.Sprintf("Content depends on JavaScript: %s", strings.Join(jsOnly, ", ")),
+				Value:          strings.Join(jsOnly, ", "),
+				Recommendation: "Render critical SEO content (title, meta, canonical, H1, links) in the server HTML",
+			})
+			summary.IssuesByType[IssueJSOnlyContent]++
+		}
+
+		// Validate structured data for rich results
+		for _, issue := range structuredDataIssues(result) {
+			summary.Issues = append(summary.Issues, issue)
+			summary.IssuesByType[issue.Type]++
+		}
+
+		// Check how the page looks when shared
+		for _, issue := range socialIssues(result) {
+			summary.Issues = append(summary.Issues, issue)
+			summary.IssuesByType[issue.Type]++
+		}
+	}
+
+	// Titles, meta descriptions and H1s are compared across pages
+	duplicateGroups, duplicateIssues := duplicateMetadataIssues(results)
+	summary.DuplicateGroups = duplicateGroups
+	for _, issue := range duplicateIssues {
+		summary.Issues = append(summary.Issues, issue)
+		summary.IssuesByType[issue.Type]++
+	}
+
+	for _, issue := range duplicateSocialTitleIssues(results) {
+		summary.Issues = append(summary.Issues, issue)
+		summary.IssuesByType[issue.Type]++
+	}
+
+	// Hreflang annotations are validated across pages
+	for _, issue := range hreflangIssues(results) {
+		summary.Issues = append(summary.Issues, issue)
+		summary.IssuesByType[issue.Type]++
+	}
+
+	// Certificates and protocols are checked once per host
+	now := time.Now()
+	for _, issue := range tlsIssues(results, now) {
+		summary.Issues = append(summary.Issues, issue)
+		summary.IssuesByType[issue.Type]++
 	}
+	summary.Security = BuildSecuritySummary(results, now)
+	summary.Performance = BuildPerformanceSummary(results)
+	summary.StructuredData = BuildStructuredDataSummary(results)
 
 	// Calculate average response time
 	if len(results) > 0 {
@@ -273,12 +443,47 @@ func Analyze(results []*models.PageResult) *Summary {
 	return summary
 }
 
+// jsOnlyFields compares rendered values with the raw HTML snapshot and returns the
+// SEO fields that are missing or different before JavaScript runs
+func jsOnlyFields(result *models.PageResult) []string {
+	raw := result.Raw
+	if raw == nil || raw.RenderError != "" {
+		return nil
+	}
+
+	var fields []string
+	compare := func(name, rawValue, renderedValue string) {
+		if renderedValue == "" || rawValue == renderedValue {
+			return
+		}
+		if rawValue == "" {
+			fields = append(fields, name+" only after rendering")
+		} else {
+			fields = append(fields, name+" changed by JavaScript")
+		}
+	}
+
+	compare("title", raw.Title, result.Title)
+	compare("meta description", raw.MetaDesc, result.MetaDesc)
+	compare("canonical", raw.Canonical, result.Canonical)
+	compare("meta robots", raw.MetaRobots, result.MetaRobots)
+	compare("H1", strings.Join(raw.H1, " | "), strings.Join(result.H1, " | "))
+
+	// Flag when most internal links are injected by scripts
+	if rendered := len(result.InternalLinks); rendered > 0 && raw.InternalLinksCount*2 < rendered {
+		fields = append(fields, fmt.Sprintf("%d of %d internal links only after rendering", rendered-raw.InternalLinksCount, rendered))
+	}
+
+	return fields
+}
+
 // AnalyzeWithImages analyzes results including image size checking
 func AnalyzeWithImages(results []*models.PageResult, imageTimeout time.Duration) *Summary {
 	summary := Analyze(results)
 
-	// Add image analysis
+	// Add image analysis, including og:image previews
 	imageIssues := AnalyzeImages(results, imageTimeout)
+	imageIssues = append(imageIssues, AnalyzeSocialImages(results, imageTimeout)...)
 	summary.Issues = append(summary.Issues, imageIssues...)
 
 	// Update counts
@@ -290,6 +495,15 @@ func AnalyzeWithImages(results []*models.PageResult, imageTimeout time.Duration)
 	return summary
 }
 
+// AddIssues appends issues from a separate analysis pass and updates the counts
+func (s *Summary) AddIssues(issues []Issue) {
+	s.Issues = append(s.Issues, issues...)
+	for _, issue := range issues {
+		s.IssuesByType[issue.Type]++
+	}
+	s.TotalIssues = len(s.Issues)
+}
+
 // GetIssueCountBySeverity returns counts grouped by severity
 func (s *Summary) GetIssueCountBySeverity() map[string]int {
 	counts := make(map[string]int)
@@ -1,8 +1,17 @@
 package analyzer
 
 import (
+	"bytes"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/dillonlara115/barracudaseo/internal/graph"
 	"github.com/dillonlara115/barracudaseo/pkg/models"
 )
 
@@ -25,6 +34,11 @@ func TestAnalyze(t *testing.T) {
 					Canonical:          "https://example.com",
 					IndexabilityStatus: models.IndexabilityIndexable,
 					ResponseTime:       200,
+					Social: &models.SocialMeta{
+						OGTitle: "Perfect Title for SEO Optimization",
+						OGImage: "https://example.com/share.png",
+						OGURL:   "https://example.com",
+					},
 				},
 			},
 			expectedIssues: map[IssueType]int{}, // No issues expected
@@ -44,9 +58,10 @@ func TestAnalyze(t *testing.T) {
 				},
 			},
 			expectedIssues: map[IssueType]int{
-				IssueMissingTitle:  1,
-				IssueMissingH1:     1,
-				IssueShortMetaDesc: 1, // "Valid..." is < 120 chars
+				IssueMissingOpenGraph: 1,
+				IssueMissingTitle:     1,
+				IssueMissingH1:        1,
+				IssueShortMetaDesc:    1, // "Valid..." is < 120 chars
 			},
 			expectedPages: 1,
 		},
@@ -73,10 +88,12 @@ func TestAnalyze(t *testing.T) {
 				},
 			},
 			expectedIssues: map[IssueType]int{
-				IssueShortTitle:    1,
-				IssueShortMetaDesc: 1,
-				IssueLongTitle:     1,
-				IssueLongMetaDesc:  1,
+				IssueDuplicateH1:      2,
+				IssueMissingOpenGraph: 2,
+				IssueShortTitle:       1,
+				IssueShortMetaDesc:    1,
+				IssueLongTitle:        1,
+				IssueLongMetaDesc:     1,
 			},
 			expectedPages: 2,
 		},
@@ -94,8 +111,9 @@ func TestAnalyze(t *testing.T) {
 				},
 			},
 			expectedIssues: map[IssueType]int{
-				IssueMultipleH1:  1,
-				IssueNoCanonical: 1,
+				IssueMissingOpenGraph: 1,
+				IssueMultipleH1:       1,
+				IssueNoCanonical:      1,
 			},
 			expectedPages: 1,
 		},
@@ -125,7 +143,8 @@ func TestAnalyze(t *testing.T) {
 				},
 			},
 			expectedIssues: map[IssueType]int{
-				IssueRedirectChain: 1,
+				IssueMissingOpenGraph: 1,
+				IssueRedirectChain:    1,
 				// Note: Other issues might be skipped or present depending on logic,
 				// but we primarily check for the redirect chain here.
 				// Based on code: it continues to analyze SEO issues for redirects if status is 200.
@@ -137,6 +156,206 @@ func TestAnalyze(t *testing.T) {
 			},
 			expectedPages: 1,
 		},
+		{
+			name: "Redirect Hops",
+			results: []*models.PageResult{
+				{
+					URL:           "http://example.com/old",
+					StatusCode:    404,
+					Error:         "HTTP 404",
+					RedirectChain: []string{"https://www.example.com/old", "https://www.example.com/gone"},
+					RedirectHops: []models.RedirectHop{
+						{URL: "http://example.com/old", StatusCode: 301, Target: "https://www.example.com/old"},
+						{URL: "https://www.example.com/old", StatusCode: 302, Target: "https://www.example.com/gone"},
+					},
+				},
+				{
+					URL:           "https://example.com/a",
+					StatusCode:    307,
+					Error:         "redirect loop: https://example.com/a -> https://example.com/b -> https://example.com/a",
+					RedirectChain: []string{"https://example.com/b", "https://example.com/a"},
+					RedirectHops: []models.RedirectHop{
+						{URL: "https://example.com/a", StatusCode: 307, Target: "https://example.com/b"},
+						{URL: "https://example.com/b", StatusCode: 307, Target: "https://example.com/a"},
+					},
+					RedirectLoop: true,
+				},
+				{
+					URL:           "https://example.com/c1",
+					StatusCode:    301,
+					Error:         "stopped after 2 redirects",
+					RedirectChain: []string{"https://example.com/c2", "https://example.com/c3"},
+					RedirectHops: []models.RedirectHop{
+						{URL: "https://example.com/c1", StatusCode: 301, Target: "https://example.com/c2"},
+						{URL: "https://example.com/c2", StatusCode: 301, Target: "https://example.com/c3"},
+					},
+					TooManyRedirects: true,
+				},
+			},
+			expectedIssues: map[IssueType]int{
+				IssueRedirectToError:     1,
+				IssueTemporaryRedirect:   1,
+				IssueHTTPToHTTPSRedirect: 1,
+				IssueWWWRedirect:         1,
+				IssueBrokenLink:          1,
+				IssueRedirectLoop:        1,
+				IssueTooManyRedirects:    1,
+			},
+			expectedPages: 3,
+		},
+		{
+			name: "TLS and Mixed Content",
+			results: []*models.PageResult{
+				{
+					URL:        "https://example.com/",
+					StatusCode: 200,
+					Title:      "Perfect Title for SEO Optimization",
+					MetaDesc:   "This is a perfect meta description that falls right within the recommended length range of 120 to 160 characters for optimal search engine display.",
+					H1:         []string{"Main Heading"},
+					Canonical:  "https://example.com/",
+					TLS: &models.TLSInfo{
+						Host:       "example.com",
+						Version:    "TLS 1.1",
+						DNSNames:   []string{"example.com"},
+						NotAfter:   time.Now().Add(10 * 24 * time.Hour),
+						CoversHost: true,
+					},
+					MixedContent: []models.MixedContent{{URL: "http://cdn.example.com/app.js", Tag: "script"}},
+				},
+				{
+					URL:   "https://shop.example.com/",
+					Error: "request failed: tls: failed to verify certificate",
+					TLS: &models.TLSInfo{
+						Host:     "shop.example.com",
+						Version:  "TLS 1.3",
+						DNSNames: []string{"example.com"},
+						NotAfter: time.Now().Add(365 * 24 * time.Hour),
+						Error:    "tls: failed to verify certificate",
+					},
+				},
+			},
+			expectedIssues: map[IssueType]int{
+				IssueMissingOpenGraph:    1,
+				IssueMixedContent:        1,
+				IssueCertificateExpiring: 1,
+				IssueOldTLSVersion:       1,
+				IssueCertificateInvalid:  1,
+			},
+			expectedPages: 2,
+		},
+		{
+			name: "Render-Blocking Scripts",
+			results: []*models.PageResult{
+				{
+					URL:        "https://example.com/",
+					StatusCode: 200,
+					Title:      "Perfect Title for SEO Optimization",
+					MetaDesc:   "This is a perfect meta description that falls right within the recommended length range of 120 to 160 characters for optimal search engine display.",
+					H1:         []string{"Main Heading"},
+					Canonical:  "https://example.com/",
+					Resources: []models.Resource{
+						{URL: "https://example.com/a.js", Type: models.ResourceScript, RenderBlocking: true},
+						{URL: "https://example.com/b.js", Type: models.ResourceScript, RenderBlocking: true},
+						{URL: "https://example.com/c.js", Type: models.ResourceScript},
+					},
+				},
+			},
+			expectedIssues: map[IssueType]int{
+				IssueMissingOpenGraph:     1,
+				IssueRenderBlockingScript: 1,
+			},
+			expectedPages: 1,
+		},
+		{
+			name: "Network Timing",
+			results: []*models.PageResult{
+				{
+					URL:        "https://example.com/slow-server",
+					StatusCode: 200,
+					Title:      "Perfect Title for SEO Optimization",
+					MetaDesc:   "This is a perfect meta description that falls right within the recommended length range of 120 to 160 characters for optimal search engine display.",
+					H1:         []string{"Main Heading"},
+					Canonical:  "https://example.com/slow-server",
+					Timing:     &models.Timing{DNSMs: 20, ConnectMs: 30, TTFBMs: 1500, DownloadMs: 40},
+				},
+				{
+					URL:        "https://example.com/slow-transfer",
+					StatusCode: 200,
+					Title:      "Perfect Title for SEO Optimization",
+					MetaDesc:   "This is a perfect meta description that falls right within the recommended length range of 120 to 160 characters for optimal search engine display.",
+					H1:         []string{"Main Heading"},
+					Canonical:  "https://example.com/slow-transfer",
+					Timing:     &models.Timing{TTFBMs: 200, DownloadMs: 2500, Reused: true},
+				},
+			},
+			expectedIssues: map[IssueType]int{
+				IssueDuplicateTitle:    2,
+				IssueDuplicateMetaDesc: 2,
+				IssueDuplicateH1:       2,
+				IssueMissingOpenGraph:  2,
+				IssueSlowTTFB:          1,
+				IssueSlowDownload:      1,
+			},
+			expectedPages: 2,
+		},
+		{
+			name: "Structured Data",
+			results: []*models.PageResult{
+				{
+					URL:        "https://example.com/product",
+					StatusCode: 200,
+					Title:      "Perfect Title for SEO Optimization",
+					MetaDesc:   "This is a perfect meta description that falls right within the recommended length range of 120 to 160 characters for optimal search engine display.",
+					H1:         []string{"Main Heading"},
+					Canonical:  "https://example.com/product",
+					StructuredData: []models.StructuredData{
+						{Format: models.StructuredDataJSONLD, Types: []string{"Product"}, Properties: []string{"brand", "description", "image", "name", "sku"}},
+						{Format: models.StructuredDataMicrodata, Types: []string{"BreadcrumbList"}, Properties: []string{"itemListElement"}},
+						{Format: models.StructuredDataJSONLD, Types: []string{"BlogPosting"}, Properties: []string{"author", "datePublished", "dateModified", "headline"}},
+						{Format: models.StructuredDataJSONLD, Types: []string{"WebSite"}},
+						{Format: models.StructuredDataJSONLD, Error: "invalid character '}'"},
+					},
+				},
+			},
+			expectedIssues: map[IssueType]int{
+				IssueMissingOpenGraph:                 1,
+				IssueStructuredDataMissingRequired:    1, // Product without offers, review or aggregateRating
+				IssueStructuredDataMissingRecommended: 1, // BlogPosting without image
+				IssueStructuredDataInvalid:            1,
+			},
+			expectedPages: 1,
+		},
+		{
+			name: "Social Metadata",
+			results: []*models.PageResult{
+				{
+					URL:        "https://example.com/a",
+					StatusCode: 200,
+					Title:      "Perfect Title for SEO Optimization",
+					MetaDesc:   "This is a perfect meta description that falls right within the recommended length range of 120 to 160 characters for optimal search engine display.",
+					H1:         []string{"Main Heading"},
+					Canonical:  "https://example.com/a",
+					Social:     &models.SocialMeta{OGTitle: "Shared Title", OGImage: "https://example.com/a.png", OGURL: "https://example.com/a?utm_source=x"},
+				},
+				{
+					URL:        "https://example.com/b",
+					StatusCode: 200,
+					Title:      "Another Perfect Title for SEO Tests",
+					MetaDesc:   "This is a perfect meta description that falls right within the recommended length range of 120 to 160 characters for optimal search engine display.",
+					H1:         []string{"Main Heading"},
+					Canonical:  "https://example.com/b",
+					Social:     &models.SocialMeta{TwitterTitle: "shared title", TwitterCard: "summary"},
+				},
+			},
+			expectedIssues: map[IssueType]int{
+				IssueDuplicateMetaDesc:    2,
+				IssueDuplicateH1:          2,
+				IssueOGURLMismatch:        1,
+				IssueMissingOpenGraph:     1,
+				IssueDuplicateSocialTitle: 2,
+			},
+			expectedPages: 2,
+		},
 		{
 			name: "Non-Indexable Page",
 			results: []*models.PageResult{
@@ -152,6 +371,141 @@ func TestAnalyze(t *testing.T) {
 			},
 			expectedPages: 1,
 		},
+		{
+			name: "JavaScript-Only Content",
+			results: []*models.PageResult{
+				{
+					URL:                "https://example.com/spa",
+					StatusCode:         200,
+					Title:              "Perfect Title for SEO Optimization",
+					MetaDesc:           "This is a perfect meta description that falls right within the recommended length range of 120 to 160 characters for optimal search engine display.",
+					H1:                 []string{"Main Heading"},
+					Canonical:          "https://example.com/spa",
+					IndexabilityStatus: models.IndexabilityIndexable,
+					RenderMode:         "js",
+					Raw: &models.RawSnapshot{
+						Title: "Loading...", // Replaced by JS
+						H1:    []string{},   // Only rendered client-side
+					},
+				},
+			},
+			expectedIssues: map[IssueType]int{
+				IssueMissingOpenGraph: 1,
+				IssueJSOnlyContent:    1,
+			},
+			expectedPages: 1,
+		},
+		{
+			name: "Mobile Desktop Parity",
+			results: []*models.PageResult{
+				{
+					URL:                "https://example.com/product",
+					StatusCode:         200,
+					Title:              "Perfect Title for SEO Optimization",
+					MetaDesc:           "This is a perfect meta description that falls right within the recommended length range of 120 to 160 characters for optimal search engine display.",
+					H1:                 []string{"Main Heading"},
+					Canonical:          "https://example.com/product",
+					InternalLinks:      []string{"https://example.com/"},
+					MetaRobots:         "noindex",
+					IndexabilityStatus: models.IndexabilityNoindex,
+					Device:             "mobile",
+					Desktop: &models.PageResult{
+						URL:                "https://example.com/product",
+						StatusCode:         200,
+						Title:              "Perfect Title for SEO Optimization",
+						H1:                 []string{"Main Heading"},
+						Canonical:          "https://example.com/product",
+						InternalLinks:      []string{"https://example.com/", "https://example.com/related"},
+						IndexabilityStatus: models.IndexabilityIndexable,
+						Device:             "desktop",
+					},
+				},
+				{
+					URL:                "https://example.com/m-only",
+					StatusCode:         404,
+					IndexabilityStatus: models.IndexabilityIndexable,
+					Device:             "mobile",
+					Desktop: &models.PageResult{
+						URL:        "https://example.com/m-only",
+						StatusCode: 200,
+						Device:     "desktop",
+					},
+				},
+			},
+			expectedIssues: map[IssueType]int{
+				IssueMobileRobotsMismatch:  1,
+				IssueMobileContentMismatch: 1,
+				IssueMobileStatusMismatch:  1,
+				IssueBrokenLink:            1,
+			},
+			expectedPages: 2,
+		},
+		{
+			name: "Response Headers",
+			results: []*models.PageResult{
+				{
+					URL:        "https://example.com/secure",
+					StatusCode: 200,
+					Title:      "Perfect Title for SEO Optimization",
+					MetaDesc:   "This is a perfect meta description that falls right within the recommended length range of 120 to 160 characters for optimal search engine display.",
+					H1:         []string{"Main Heading"},
+					Canonical:  "/secure",
+					Headers: &models.ResponseHeaders{
+						ContentType:             "text/html; charset=utf-8",
+						ContentEncoding:         "br",
+						CacheControl:            "max-age=300",
+						LinkCanonical:           "https://example.com/secure",
+						StrictTransportSecurity: "max-age=63072000; includeSubDomains",
+						ContentSecurityPolicy:   "default-src 'self'; frame-ancestors 'none'",
+						XContentTypeOptions:     "nosniff",
+						ReferrerPolicy:          "strict-origin-when-cross-origin",
+					},
+				},
+				{
+					URL:        "https://example.com/plain",
+					StatusCode: 200,
+					Title:      "Perfect Title for SEO Optimization",
+					MetaDesc:   "This is a perfect meta description that falls right within the recommended length range of 120 to 160 characters for optimal search engine display.",
+					H1:         []string{"Main Heading"},
+					Canonical:  "https://example.com/plain",
+					Headers: &models.ResponseHeaders{
+						ContentType:             "text/html",
+						ContentLength:           24000,
+						LinkCanonical:           "https://example.com/other",
+						StrictTransportSecurity: "max-age=3600",
+						ContentSecurityPolicy:   "script-src 'self' 'unsafe-inline'",
+						XContentTypeOptions:     "nosniff",
+						XFrameOptions:           "SAMEORIGIN",
+						ReferrerPolicy:          "no-referrer",
+					},
+				},
+				{
+					URL:        "https://example.com/header-canonical",
+					StatusCode: 200,
+					Title:      "Perfect Title for SEO Optimization",
+					MetaDesc:   "This is a perfect meta description that falls right within the recommended length range of 120 to 160 characters for optimal search engine display.",
+					H1:         []string{"Main Heading"},
+					Headers: &models.ResponseHeaders{
+						ContentType:     "text/html",
+						ContentEncoding: "gzip",
+						Expires:         "Thu, 01 Jan 2099 00:00:00 GMT",
+						LinkCanonical:   "https://example.com/header-canonical",
+					},
+				},
+			},
+			expectedIssues: map[IssueType]int{
+				IssueDuplicateTitle:          2,
+				IssueDuplicateMetaDesc:       2,
+				IssueDuplicateH1:             2,
+				IssueMissingOpenGraph:        3,
+				IssueMissingCompression:      1,
+				IssueMissingCacheHeaders:     1,
+				IssueWeakSecurityHeaders:     1,
+				IssueCanonicalHeaderConflict: 1,
+				IssueMissingSecurityHeaders:  1,
+			},
+			expectedPages: 3,
+		},
 	}
 
 	for _, tt := range tests {
@@ -179,3 +533,430 @@ func TestAnalyze(t *testing.T) {
 		})
 	}
 }
+
+func TestAnalyzeRobots(t *testing.T) {
+	report := &models.RobotsReport{
+		Hosts: []models.RobotsHost{
+			{URL: "https://example.com/robots.txt", StatusCode: 200, Warnings: []string{"line 3: unknown directive \"disalow\""}},
+			{URL: "https://shop.example.com/robots.txt", StatusCode: 503},
+			{URL: "https://blog.example.com/robots.txt", StatusCode: 404},
+		},
+		Blocked: []models.RobotsBlockedURL{
+			{URL: "https://example.com/private", Rule: "Disallow: /private (line 2)", LinkedFrom: []string{"https://example.com/"}},
+			{URL: "https://example.com/orphan", Rule: "Disallow: /orphan (line 4)"},
+		},
+	}
+
+	counts := make(map[IssueType]int)
+	for _, issue := range AnalyzeRobots(report) {
+		counts[issue.Type]++
+	}
+
+	want := map[IssueType]int{
+		IssueRobotsTxtSyntax:      1,
+		IssueRobotsTxtUnreachable: 1,
+		IssueBlockedByRobots:      1,
+	}
+	for issueType, count := range want {
+		if counts[issueType] != count {
+			t.Errorf("AnalyzeRobots() %v count = %d, want %d", issueType, counts[issueType], count)
+		}
+	}
+	if len(counts) != len(want) {
+		t.Errorf("AnalyzeRobots() issue types = %v, want %v", counts, want)
+	}
+}
+
+func TestAnalyzeSitemap(t *testing.T) {
+	inSitemap := &models.SitemapEntry{Source: "https://example.com/sitemap.xml"}
+	results := []*models.PageResult{
+		{URL: "https://example.com", StatusCode: 200, IndexabilityStatus: models.IndexabilityIndexable, Sitemap: inSitemap},
+		{URL: "https://example.com/gone", StatusCode: 404, Sitemap: inSitemap},
+		{URL: "https://example.com/old", StatusCode: 200, RedirectChain: []string{"https://example.com/new"}, Sitemap: inSitemap},
+		{URL: "https://example.com/hidden", StatusCode: 200, IndexabilityStatus: models.IndexabilityNoindex, Sitemap: inSitemap},
+		{URL: "https://example.com/print", StatusCode: 200, Canonical: "https://example.com/", IndexabilityStatus: models.IndexabilityIndexable, Sitemap: inSitemap},
+		{URL: "https://example.com/lonely", StatusCode: 200, IndexabilityStatus: models.IndexabilityIndexable, Sitemap: inSitemap},
+		{URL: "https://example.com/unlisted", StatusCode: 200, IndexabilityStatus: models.IndexabilityIndexable},
+	}
+
+	linkGraph := graph.NewGraph()
+	linkGraph.AddEdges("https://example.com", []string{"https://example.com/gone", "https://example.com/old", "https://example.com/hidden", "https://example.com/print", "https://example.com/unlisted"})
+	linkGraph.AddEdges("https://example.com/unlisted", []string{"https://example.com"})
+
+	robots := &models.RobotsReport{
+		Blocked: []models.RobotsBlockedURL{
+			{URL: "https://example.com/private", Rule: "Disallow: /private (line 2)", Sitemap: "https://example.com/sitemap.xml"},
+		},
+	}
+
+	counts := make(map[IssueType]int)
+	for _, issue := range AnalyzeSitemap(results, linkGraph, robots) {
+		counts[issue.Type]++
+	}
+
+	want := map[IssueType]int{
+		IssueSitemapNon200:        1,
+		IssueSitemapRedirect:      1,
+		IssueSitemapNoindex:       1,
+		IssueSitemapCanonicalized: 1,
+		IssueSitemapBlocked:       1,
+		IssueSitemapOrphan:        1,
+		IssueMissingFromSitemap:   1,
+	}
+	for issueType, count := range want {
+		if counts[issueType] != count {
+			t.Errorf("AnalyzeSitemap() %v count = %d, want %d", issueType, counts[issueType], count)
+		}
+	}
+	if len(counts) != len(want) {
+		t.Errorf("AnalyzeSitemap() issue types = %v, want %v", counts, want)
+	}
+
+	if issues := AnalyzeSitemap([]*models.PageResult{results[6]}, linkGraph, nil); len(issues) != 0 {
+		t.Errorf("AnalyzeSitemap() without a sitemap = %v, want no issues", issues)
+	}
+}
+
+func TestBuildSecuritySummary(t *testing.T) {
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	tlsInfo := &models.TLSInfo{Host: "example.com", Version: "TLS 1.3", NotAfter: now.Add(90 * 24 * time.Hour), CoversHost: true}
+	results := []*models.PageResult{
+		{URL: "https://example.com/", StatusCode: 200, TLS: tlsInfo,
+			Headers: &models.ResponseHeaders{ContentType: "text/html", StrictTransportSecurity: "max-age=63072000"}},
+		{URL: "https://example.com/a", StatusCode: 200, TLS: tlsInfo,
+			MixedContent: []models.MixedContent{{URL: "http://example.com/a.png", Tag: "img"}}},
+		{URL: "http://legacy.example.com/", StatusCode: 200},
+	}
+
+	security := BuildSecuritySummary(results, now)
+	if security.HTTPSPages != 2 || security.HTTPPages != 1 || security.MixedContentPages != 1 {
+		t.Errorf("unexpected page counts %+v", security)
+	}
+	if len(security.Hosts) != 1 || security.Hosts[0].Pages != 2 || security.Hosts[0].DaysUntilExpiry != 90 {
+		t.Errorf("unexpected hosts %+v", security.Hosts)
+	}
+	if security.MissingHeaders["Content-Security-Policy"] != 1 || security.MissingHeaders["Strict-Transport-Security"] != 0 {
+		t.Errorf("unexpected missing headers %v", security.MissingHeaders)
+	}
+}
+
+func TestAnalyzeResources(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/site.css":
+			w.Write([]byte(strings.Repeat("a", 2048)))
+		case "/big.js":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			w.Write([]byte(strings.Repeat("b", 4096)))
+		case "/photo.jpg":
+			w.Write([]byte(strings.Repeat("c", 1024)))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	results := []*models.PageResult{
+		{
+			URL:        server.URL + "/",
+			StatusCode: 200,
+			Size:       1024,
+			Resources: []models.Resource{
+				{URL: server.URL + "/site.css", Type: models.ResourceStylesheet},
+				{URL: server.URL + "/big.js", Type: models.ResourceScript},
+				{URL: server.URL + "/missing.woff2", Type: models.ResourceFont},
+				{URL: server.URL + "/missing.png", Type: models.ResourceImage}, // Reported by AnalyzeImages
+			},
+		},
+		{
+			URL:        server.URL + "/light",
+			StatusCode: 200,
+			Size:       512,
+			Resources:  []models.Resource{{URL: server.URL + "/photo.jpg", Type: models.ResourceImage}},
+		},
+	}
+
+	issues := AnalyzeResources(results, &http.Client{Timeout: 5 * time.Second}, 5)
+	counts := make(map[IssueType]int)
+	for _, issue := range issues {
+		counts[issue.Type]++
+		if issue.URL != server.URL+"/" {
+			t.Errorf("unexpected issue on %s: %+v", issue.URL, issue)
+		}
+	}
+	if counts[IssueBrokenResource] != 1 || counts[IssuePageWeight] != 1 || len(issues) != 2 {
+		t.Errorf("AnalyzeResources() = %+v, want one broken resource and one page over budget", issues)
+	}
+
+	resources := results[0].Resources
+	if resources[0].StatusCode != 200 || resources[0].Size != 2048 {
+		t.Errorf("stylesheet check = %+v, want 200 and 2048 bytes", resources[0])
+	}
+	if resources[1].StatusCode != 200 || resources[1].Size != 4096 {
+		t.Errorf("script check (HEAD rejected) = %+v, want 200 and 4096 bytes", resources[1])
+	}
+	if resources[2].StatusCode != 404 {
+		t.Errorf("missing font status = %d, want 404", resources[2].StatusCode)
+	}
+}
+
+func TestBuildPerformanceSummary(t *testing.T) {
+	var results []*models.PageResult
+	for i := 1; i <= 20; i++ {
+		results = append(results, &models.PageResult{
+			URL:          "https://example.com/" + strings.Repeat("a", i),
+			StatusCode:   200,
+			ResponseTime: int64(i * 100),
+			Timing:       &models.Timing{DNSMs: 5, ConnectMs: int64(i), TTFBMs: int64(i * 10), DownloadMs: int64(i), Reused: i > 2},
+		})
+	}
+	results = append(results, &models.PageResult{URL: "https://example.com/no-timing", StatusCode: 200, ResponseTime: 9000})
+
+	performance := BuildPerformanceSummary(results)
+	if performance.Pages != 20 {
+		t.Errorf("Pages = %d, want 20", performance.Pages)
+	}
+	want := Percentiles{P50: 100, P75: 150, P90: 180, P95: 190, P99: 200, Max: 200}
+	if performance.TTFB != want {
+		t.Errorf("TTFB = %+v, want %+v", performance.TTFB, want)
+	}
+	if performance.ResponseTime.Max != 2000 || performance.ResponseTime.P50 != 1000 {
+		t.Errorf("ResponseTime = %+v, want p50 1000 and max 2000", performance.ResponseTime)
+	}
+	// Only the two new connections count towards connection setup
+	if performance.Connect.Max != 2 || performance.Connect.P50 != 1 {
+		t.Errorf("Connect = %+v, want p50 1 and max 2", performance.Connect)
+	}
+	if (BuildPerformanceSummary(nil).TTFB != Percentiles{}) {
+		t.Error("expected empty percentiles without results")
+	}
+}
+
+func TestBuildStructuredDataSummary(t *testing.T) {
+	results := []*models.PageResult{
+		{URL: "https://example.com/a", StatusCode: 200, StructuredData: []models.StructuredData{
+			{Format: models.StructuredDataJSONLD, Types: []string{"Product"}, Properties: []string{"name", "offers"}},
+			{Format: models.StructuredDataJSONLD, Types: []string{"Product"}, Properties: []string{"name"}},
+			{Format: models.StructuredDataJSONLD, Error: "unexpected end of JSON input"},
+		}},
+		{URL: "https://example.com/b", StatusCode: 200, StructuredData: []models.StructuredData{
+			{Format: models.StructuredDataMicrodata, Types: []string{"Product"}, Properties: []string{"brand", "description", "image", "name", "offers", "sku"}},
+			{Format: models.StructuredDataRDFa, Types: []string{"Organization"}, Properties: []string{"logo", "name", "sameAs", "url"}},
+		}},
+		{URL: "https://example.com/c", StatusCode: 200},
+	}
+
+	summary := BuildStructuredDataSummary(results)
+	if summary.PagesWithStructuredData != 2 || summary.InvalidJSONBlocks != 1 {
+		t.Errorf("unexpected page counts %+v", summary)
+	}
+	if len(summary.Types) != 2 {
+		t.Fatalf("expected 2 types, got %+v", summary.Types)
+	}
+	product := summary.Types[0]
+	if product.Type != "Product" || product.Items != 3 || product.Pages != 2 || product.WithErrors != 1 || product.WithWarnings != 1 {
+		t.Errorf("unexpected Product summary %+v", product)
+	}
+	if strings.Join(product.Formats, ",") != "json-ld,microdata" {
+		t.Errorf("Product formats = %v, want json-ld and microdata", product.Formats)
+	}
+	if organization := summary.Types[1]; organization.Type != "Organization" || organization.WithErrors != 0 || organization.WithWarnings != 0 {
+		t.Errorf("unexpected Organization summary %+v", organization)
+	}
+}
+
+func TestHreflangIssues(t *testing.T) {
+	alt := func(lang, url string) models.Hreflang {
+		return models.Hreflang{Lang: lang, URL: url, Source: models.HreflangHTML}
+	}
+	results := []*models.PageResult{
+		{URL: "https://example.com/en", StatusCode: 200, HTMLLang: "de", Hreflang: []models.Hreflang{
+			alt("en", "https://example.com/en"),
+			alt("de", "https://example.com/de"),
+			alt("fr", "https://example.com/fr"), // No return link
+			alt("es", "https://example.com/es"), // 404
+			alt("it", "https://example.com/it"), // noindex
+			alt("pt", "https://example.com/pt"), // Canonicalised elsewhere
+			alt("en-UK", "https://example.com/uk"),
+			alt("x-default", "https://example.com/"),
+		}},
+		{URL: "https://example.com/de", StatusCode: 200, HTMLLang: "de-DE", Hreflang: []models.Hreflang{
+			alt("de", "https://example.com/de"),
+			{Lang: "en", URL: "https://example.com/en", Source: models.HreflangHeader},
+		}},
+		{URL: "https://example.com/fr", StatusCode: 200},
+		{URL: "https://example.com/es", StatusCode: 404, Error: "HTTP 404"},
+		{URL: "https://example.com/it", StatusCode: 200, IndexabilityStatus: models.IndexabilityNoindex},
+		{URL: "https://example.com/pt", StatusCode: 200, Canonical: "https://example.com/pt-br"},
+		{URL: "https://example.com/nl", StatusCode: 200, Sitemap: &models.SitemapEntry{
+			Loc:        "https://example.com/nl",
+			Alternates: []models.SitemapAlternate{{Hreflang: "de", Href: "https://example.com/de/"}},
+		}},
+	}
+
+	counts := make(map[IssueType]int)
+	for _, issue := range hreflangIssues(results) {
+		counts[issue.Type]++
+	}
+	want := map[IssueType]int{
+		IssueHreflangInvalidCode:        1, // en-UK
+		IssueHreflangMissingSelf:        1, // nl
+		IssueHreflangMissingXDefault:    2, // de, nl
+		IssueHreflangMissingReturn:      2, // en -> fr, nl -> de
+		IssueHreflangTargetNon200:       1,
+		IssueHreflangTargetNoindex:      1,
+		IssueHreflangTargetNonCanonical: 1,
+		IssueHreflangLangMismatch:       1, // en page with <html lang="de">
+	}
+	for issueType, count := range want {
+		if counts[issueType] != count {
+			t.Errorf("hreflangIssues() %v count = %d, want %d", issueType, counts[issueType], count)
+		}
+	}
+	if len(counts) != len(want) {
+		t.Errorf("hreflangIssues() issue types = %v, want %v", counts, want)
+	}
+
+	for value, valid := range map[string]bool{"en": true, "en-GB": true, "zh-Hant-TW": true, "X-Default": true, "en-UK": false, "eng": false, "gb": false} {
+		if validHreflang(value) != valid {
+			t.Errorf("validHreflang(%q) = %v, want %v", value, !valid, valid)
+		}
+	}
+}
+
+func TestAnalyzeSocialImages(t *testing.T) {
+	encodePNG := func(width, height int) []byte {
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height))); err != nil {
+			t.Fatal(err)
+		}
+		return buf.Bytes()
+	}
+	large, small := encodePNG(1200, 630), encodePNG(100, 100)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		switch r.URL.Path {
+		case "/large.png":
+			w.Write(large)
+		case "/small.png":
+			w.Write(small)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	page := func(path, image string) *models.PageResult {
+		return &models.PageResult{URL: server.URL + path, StatusCode: 200, Social: &models.SocialMeta{OGImage: server.URL + image}}
+	}
+	results := []*models.PageResult{
+		page("/ok", "/large.png"),
+		page("/small", "/small.png"),
+		page("/broken", "/missing.png"),
+		{URL: server.URL + "/none", StatusCode: 200},
+	}
+
+	issues := AnalyzeSocialImages(results, 5*time.Second)
+	got := make(map[string]IssueType)
+	for _, issue := range issues {
+		got[issue.URL] = issue.Type
+	}
+	want := map[string]IssueType{
+		server.URL + "/small":  IssueSmallSocialImage,
+		server.URL + "/broken": IssueBrokenSocialImage,
+	}
+	if len(got) != len(want) || len(issues) != len(want) {
+		t.Fatalf("AnalyzeSocialImages() = %+v, want %v", issues, want)
+	}
+	for url, issueType := range want {
+		if got[url] != issueType {
+			t.Errorf("issue for %s = %q, want %q", url, got[url], issueType)
+		}
+	}
+}
+
+func TestFindDuplicateContent(t *testing.T) {
+	page := func(path, textHash, simHash string, words int) *models.PageResult {
+		return &models.PageResult{URL: "https://example.com" + path, StatusCode: 200, TextHash: textHash, SimHash: simHash, WordCount: words}
+	}
+	canonicalised := page("/shoes?sort=price", "shoes", "ffffffff00000000", 10)
+	canonicalised.Canonical = "https://example.com/shoes"
+	linking := page("/", "home", "00000000ffff0000", 80)
+	linking.InternalLinks = []string{"https://example.com/london", "https://example.com/london/", "https://example.com/paris"}
+	about := page("/about", "about", "000000000000ffff", 120)
+	about.InternalLinks = []string{"https://example.com/london"}
+
+	results := []*models.PageResult{
+		page("/shoes", "shoes", "ffffffff00000000", 10),
+		page("/shoes?color=red", "shoes", "ffffffff00000000", 10),
+		canonicalised,
+		page("/paris", "paris", "0000000000000000", 120),
+		page("/london", "london", "0000000000000007", 120), // 3 bits from /paris
+		about,
+		page("/short", "short", "0000000000000001", 10), // Too short to compare approximately
+		linking,
+	}
+
+	duplicates := FindDuplicateContent(results, 0.9)
+	want := []DuplicateCluster{
+		{URLs: []string{"https://example.com/london", "https://example.com/paris"}, Canonical: "https://example.com/london", Similarity: 0.95},
+		{URLs: []string{"https://example.com/shoes", "https://example.com/shoes?color=red"}, Canonical: "https://example.com/shoes", Similarity: 1, Exact: true},
+	}
+	if !reflect.DeepEqual(duplicates.Clusters, want) {
+		t.Fatalf("Clusters = %+v, want %+v", duplicates.Clusters, want)
+	}
+	if duplicates.Pages != 4 || duplicates.Similarity != 0.9 {
+		t.Errorf("Pages = %d, Similarity = %v, want 4 and 0.9", duplicates.Pages, duplicates.Similarity)
+	}
+
+	issues := DuplicateContentIssues(duplicates)
+	var flagged []string
+	for _, issue := range issues {
+		if issue.Type != IssueDuplicateContent {
+			t.Errorf("issue type = %q, want %q", issue.Type, IssueDuplicateContent)
+		}
+		flagged = append(flagged, issue.URL)
+	}
+	if want := []string{"https://example.com/paris", "https://example.com/shoes?color=red"}; !reflect.DeepEqual(flagged, want) {
+		t.Errorf("flagged pages = %v, want %v", flagged, want)
+	}
+
+	// A stricter threshold keeps only the exact duplicates
+	if strict := FindDuplicateContent(results, 0.97); len(strict.Clusters) != 1 || !strict.Clusters[0].Exact {
+		t.Errorf("Clusters at 0.97 = %+v, want only the exact cluster", strict.Clusters)
+	}
+}
+
+func TestDuplicateMetadataIssues(t *testing.T) {
+	results := []*models.PageResult{
+		{URL: "https://example.com/a", StatusCode: 200, Title: "Shoes | Shop", MetaDesc: "Buy shoes", H1: []string{"Shoes", "Sale"}},
+		{URL: "https://example.com/b", StatusCode: 200, Title: "  shoes |  SHOP ", MetaDesc: "Other", H1: []string{"shoes", "Shoes"}},
+		{URL: "https://example.com/c", StatusCode: 200, Title: "Shoes | Shop", MetaDesc: "Buy shoes", IndexabilityStatus: models.IndexabilityNoindex},
+		{URL: "https://example.com/d", StatusCode: 200, Title: "Shoes | Shop", MetaDesc: "Buy shoes", Canonical: "https://example.com/a"},
+		{URL: "https://example.com/e", StatusCode: 404, Title: "Shoes | Shop"},
+	}
+
+	groups, issues := duplicateMetadataIssues(results)
+	want := []DuplicateGroup{
+		{Field: DuplicateFieldTitle, Value: "Shoes | Shop", URLs: []string{"https://example.com/a", "https://example.com/b"}},
+		{Field: DuplicateFieldH1, Value: "Shoes", URLs: []string{"https://example.com/a", "https://example.com/b"}},
+	}
+	if !reflect.DeepEqual(groups, want) {
+		t.Fatalf("groups = %+v, want %+v", groups, want)
+	}
+
+	counts := make(map[IssueType]int)
+	values := map[IssueType]string{IssueDuplicateTitle: "Shoes | Shop", IssueDuplicateH1: "Shoes"}
+	for _, issue := range issues {
+		counts[issue.Type]++
+		if issue.Value != values[issue.Type] {
+			t.Errorf("issue %s for %s has value %q, want the shared value %q", issue.Type, issue.URL, issue.Value, values[issue.Type])
+		}
+	}
+	if want := map[IssueType]int{IssueDuplicateTitle: 2, IssueDuplicateH1: 2}; !reflect.DeepEqual(counts, want) {
+		t.Errorf("issue counts = %v, want %v", counts, want)
+	}
+}
@@ -0,0 +1,315 @@
+package analyzer
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dillonlara115/barracudaseo/internal/utils"
+	"github.com/dillonlara115/barracudaseo/pkg/models"
+)
+
+const (
+	// DefaultDuplicateSimilarity is the share of matching SimHash bits at which pages are near duplicates
+	DefaultDuplicateSimilarity = 0.9
+	// minNearDuplicateWords is the main content size below which pages are only compared exactly,
+	// since short texts produce close SimHashes by chance
+	minNearDuplicateWords = 50
+)
+
+// DuplicateCluster is a group of pages with the same or nearly the same main content
+type DuplicateCluster struct {
+	URLs       []string `json:"urls"`
+	Canonical  string   `json:"suggested_canonical"` // Member with the most internal links pointing to it
+	Similarity float64  `json:"similarity"`          // Lowest similarity between two members, 1 when all are identical
+	Exact      bool     `json:"exact"`               // All members have identical text
+}
+
+// DuplicateContentSummary lists the pages of a crawl that duplicate each other
+type DuplicateContentSummary struct {
+	Similarity float64            `json:"similarity"` // Threshold used for near duplicates
+	Pages      int                `json:"pages"`      // Pages in a cluster
+	Clusters   []DuplicateCluster `json:"clusters"`   // Largest first
+}
+
+// duplicateCandidate is an indexable page with content fingerprints
+type duplicateCandidate struct {
+	url      string
+	textHash string
+	simHash  uint64
+	words    int
+}
+
+// FindDuplicateContent clusters indexable pages whose main content is identical (same text hash)
+// or whose SimHashes match in at least similarity of their bits (DefaultDuplicateSimilarity when
+// similarity is not between 0 and 1). Pages canonicalised elsewhere are left out because they
+// already declare which version to index.
+func FindDuplicateContent(results []*models.PageResult, similarity float64) *DuplicateContentSummary {
+	if similarity <= 0 || similarity > 1 {
+		similarity = DefaultDuplicateSimilarity
+	}
+	maxDistance := int(math.Floor((1-similarity)*64 + 1e-9))
+	summary := &DuplicateContentSummary{Similarity: similarity, Clusters: []DuplicateCluster{}}
+
+	var candidates []duplicateCandidate
+	for _, result := range results {
+		if !IsIndexablePage(result) || result.TextHash == "" {
+			continue
+		}
+		simHash, err := strconv.ParseUint(result.SimHash, 16, 64)
+		if err != nil {
+			continue
+		}
+		candidates = append(candidates, duplicateCandidate{url: result.URL, textHash: result.TextHash, simHash: simHash, words: result.WordCount})
+	}
+
+	// Union pages with the same text, then compare one page per distinct text for near duplicates
+	parent := make([]int, len(candidates))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	union := func(a, b int) { parent[find(a)] = find(b) }
+
+	firstByHash := make(map[string]int)
+	var distinct []int
+	for i, candidate := range candidates {
+		if first, ok := firstByHash[candidate.textHash]; ok {
+			union(i, first)
+			continue
+		}
+		firstByHash[candidate.textHash] = i
+		if candidate.words >= minNearDuplicateWords {
+			distinct = append(distinct, i)
+		}
+	}
+	for x := 0; x < len(distinct); x++ {
+		for y := x + 1; y < len(distinct); y++ {
+			a, b := candidates[distinct[x]], candidates[distinct[y]]
+			if bits.OnesCount64(a.simHash^b.simHash) <= maxDistance {
+				union(distinct[x], distinct[y])
+			}
+		}
+	}
+
+	groups := make(map[int][]duplicateCandidate)
+	for i, candidate := range candidates {
+		root := find(i)
+		groups[root] = append(groups[root], candidate)
+	}
+
+	inbound := inboundLinkCounts(results)
+	for _, members := range groups {
+		if len(members) < 2 {
+			continue
+		}
+		cluster := DuplicateCluster{Similarity: 1, Exact: true}
+		simHashes := make(map[uint64]bool)
+		for _, member := range members {
+			cluster.URLs = append(cluster.URLs, member.url)
+			simHashes[member.simHash] = true
+			if member.textHash != members[0].textHash {
+				cluster.Exact = false
+			}
+		}
+		if !cluster.Exact {
+			cluster.Similarity = lowestSimilarity(simHashes)
+		}
+		sort.Strings(cluster.URLs)
+		cluster.Canonical = suggestedCanonical(cluster.URLs, inbound)
+		summary.Clusters = append(summary.Clusters, cluster)
+		summary.Pages += len(cluster.URLs)
+	}
+
+	sort.Slice(summary.Clusters, func(i, j int) bool {
+		a, b := summary.Clusters[i], summary.Clusters[j]
+		if len(a.URLs) != len(b.URLs) {
+			return len(a.URLs) > len(b.URLs)
+		}
+		return a.URLs[0] < b.URLs[0]
+	})
+	return summary
+}
+
+// lowestSimilarity returns the share of matching bits of the two most different SimHashes
+func lowestSimilarity(simHashes map[uint64]bool) float64 {
+	hashes := make([]uint64, 0, len(simHashes))
+	for hash := range simHashes {
+		hashes = append(hashes, hash)
+	}
+	maxDistance := 0
+	for i := range hashes {
+		for j := i + 1; j < len(hashes); j++ {
+			if distance := bits.OnesCount64(hashes[i] ^ hashes[j]); distance > maxDistance {
+				maxDistance = distance
+			}
+		}
+	}
+	return math.Round((1-float64(maxDistance)/64)*100) / 100
+}
+
+// inboundLinkCounts counts the pages linking to each normalized URL
+func inboundLinkCounts(results []*models.PageResult) map[string]int {
+	counts := make(map[string]int)
+	for _, result := range results {
+		seen := make(map[string]bool)
+		for _, link := range result.InternalLinks {
+			if normalized, err := utils.NormalizeURL(link); err == nil {
+				link = normalized
+			}
+			if !seen[link] {
+				seen[link] = true
+				counts[link]++
+			}
+		}
+	}
+	return counts
+}
+
+// suggestedCanonical picks the cluster member with the most inbound internal links,
+// preferring the shortest URL (usually the one without facets or parameters) on ties
+func suggestedCanonical(urls []string, inbound map[string]int) string {
+	best, bestLinks := "", -1
+	for _, pageURL := range urls {
+		normalized, err := utils.NormalizeURL(pageURL)
+		if err != nil {
+			normalized = pageURL
+		}
+		links := inbound[normalized]
+		if links > bestLinks || (links == bestLinks && len(pageURL) < len(best)) {
+			best, bestLinks = pageURL, links
+		}
+	}
+	return best
+}
+
+// DuplicateContentIssues reports every page of a duplicate cluster except its suggested canonical
+func DuplicateContentIssues(duplicates *DuplicateContentSummary) []Issue {
+	if duplicates == nil {
+		return nil
+	}
+	var issues []Issue
+	for _, cluster := range duplicates.Clusters {
+		match := "identical text"
+		if !cluster.Exact {
+			match = fmt.Sprintf("at least %.0f%% similar", cluster.Similarity*100)
+		}
+		for _, pageURL := range cluster.URLs {
+			if pageURL == cluster.Canonical {
+				continue
+			}
+			others := make([]string, 0, len(cluster.URLs)-1)
+			for _, other := range cluster.URLs {
+				if other != pageURL {
+					others = append(others, other)
+				}
+			}
+			issues = append(issues, Issue{
+				Type:           IssueDuplicateContent,
+				Severity:       "warning",
+				URL:            pageURL,
+				Message:        fmt.Sprintf("Content duplicates %d other page(s) (%s): %s", len(others), match, strings.Join(others, ", ")),
+				Value:          cluster.Canonical,
+				Recommendation: fmt.Sprintf("Add rel=canonical pointing to %s, consolidate the pages, or make their content distinct", cluster.Canonical),
+			})
+		}
+	}
+	return issues
+}
+
+// Fields compared across pages by duplicateMetadataIssues
+const (
+	DuplicateFieldTitle    = "title"
+	DuplicateFieldMetaDesc = "meta_description"
+	DuplicateFieldH1       = "h1"
+)
+
+// DuplicateGroup is a title, meta description or H1 shared by several indexable pages
+type DuplicateGroup struct {
+	Field string   `json:"field"`
+	Value string   `json:"value"` // As written on the first page of the group
+	URLs  []string `json:"urls"`
+}
+
+// duplicateMetadataIssues groups indexable pages that share a title, meta description or H1,
+// ignoring case and whitespace, and reports every page of each group with the shared value
+// (the group's URLs are only listed once, in the returned groups). Pages that are not
+// indexable or are canonicalised elsewhere are expected to repeat their canonical's metadata.
+func duplicateMetadataIssues(results []*models.PageResult) ([]DuplicateGroup, []Issue) {
+	fields := []struct {
+		name           string
+		label          string
+		issueType      IssueType
+		severity       string
+		values         func(*models.PageResult) []string
+		recommendation string
+	}{
+		{DuplicateFieldTitle, "Title", IssueDuplicateTitle, "warning",
+			func(result *models.PageResult) []string { return []string{result.Title} },
+			"Give each page a unique title that describes its content"},
+		{DuplicateFieldMetaDesc, "Meta description", IssueDuplicateMetaDesc, "warning",
+			func(result *models.PageResult) []string { return []string{result.MetaDesc} },
+			"Write a unique meta description for each page"},
+		{DuplicateFieldH1, "H1", IssueDuplicateH1, "info",
+			func(result *models.PageResult) []string { return result.H1 },
+			"Use an H1 that describes what sets this page apart"},
+	}
+
+	groups := make([]DuplicateGroup, 0)
+	var issues []Issue
+	for _, field := range fields {
+		var keys []string
+		byKey := make(map[string]*DuplicateGroup)
+		for _, result := range results {
+			if !IsIndexablePage(result) || utils.IsImageURL(result.URL) {
+				continue
+			}
+			seen := make(map[string]bool)
+			for _, value := range field.values(result) {
+				value = strings.Join(strings.Fields(value), " ")
+				key := strings.ToLower(value)
+				if key == "" || seen[key] {
+					continue
+				}
+				seen[key] = true
+				if byKey[key] == nil {
+					byKey[key] = &DuplicateGroup{Field: field.name, Value: value}
+					keys = append(keys, key)
+				}
+				byKey[key].URLs = append(byKey[key].URLs, result.URL)
+			}
+		}
+
+		for _, key := range keys {
+			group := byKey[key]
+			if len(group.URLs) < 2 {
+				continue
+			}
+			groups = append(groups, *group)
+			for _, pageURL := range group.URLs {
+				issues = append(issues, Issue{
+					Type:           field.issueType,
+					Severity:       field.severity,
+					URL:            pageURL,
+					Message:        fmt.Sprintf("%s %q is shared by %d pages", field.label, group.Value, len(group.URLs)),
+					Value:          group.Value,
+					Recommendation: field.recommendation,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		return len(groups[i].URLs) > len(groups[j].URLs)
+	})
+	return groups, issues
+}
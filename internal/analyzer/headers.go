@@ -0,0 +1,192 @@
+package analyzer
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/dillonlara115/barracudaseo/internal/utils"
+	"github.com/dillonlara115/barracudaseo/pkg/models"
+)
+
+// hstsMinMaxAge is the shortest Strict-Transport-Security max-age considered strong (180 days)
+const hstsMinMaxAge = 180 * 24 * 60 * 60
+
+// minCompressibleSize is the response size below which compression is not worth flagging
+const minCompressibleSize = 1400
+
+// headerIssues audits the response headers of a successfully fetched HTML page
+func headerIssues(result *models.PageResult) []Issue {
+	headers := result.Headers
+	if headers == nil || result.StatusCode != 200 {
+		return nil
+	}
+	if headers.ContentType != "" && !strings.Contains(strings.ToLower(headers.ContentType), "html") {
+		return nil
+	}
+
+	var issues []Issue
+	if headers.ContentEncoding == "" && (headers.ContentLength == 0 || headers.ContentLength >= minCompressibleSize) {
+		issues = append(issues, Issue{
+			Type:           IssueMissingCompression,
+			Severity:       "warning",
+			URL:            result.URL,
+			Message:        "Page is served without compression",
+			Recommendation: "Enable gzip or Brotli compression for HTML responses",
+		})
+	}
+
+	if headers.CacheControl == "" && headers.Expires == "" {
+		issues = append(issues, Issue{
+			Type:           IssueMissingCacheHeaders,
+			Severity:       "info",
+			URL:            result.URL,
+			Message:        "No Cache-Control or Expires header",
+			Recommendation: "Send a Cache-Control header so browsers and CDNs know how long the page may be reused",
+		})
+	}
+
+	if missing := missingSecurityHeaders(result.URL, headers); len(missing) > 0 {
+		issues = append(issues, Issue{
+			Type:           IssueMissingSecurityHeaders,
+			Severity:       "warning",
+			URL:            result.URL,
+			Message:        fmt.Sprintf("Missing security headers: %s", strings.Join(missing, ", ")),
+			Value:          strings.Join(missing, ", "),
+			Recommendation: "Send HSTS, Content-Security-Policy, X-Content-Type-Options, X-Frame-Options and Referrer-Policy headers",
+		})
+	}
+
+	if weak := weakSecurityHeaders(headers); len(weak) > 0 {
+		issues = append(issues, Issue{
+			Type:           IssueWeakSecurityHeaders,
+			Severity:       "info",
+			URL:            result.URL,
+			Message:        fmt.Sprintf("Weak security headers: %s", strings.Join(weak, "; ")),
+			Value:          strings.Join(weak, "; "),
+			Recommendation: "Tighten the listed header values",
+		})
+	}
+
+	if conflict := canonicalHeaderConflict(result); conflict != "" {
+		issues = append(issues, Issue{
+			Type:           IssueCanonicalHeaderConflict,
+			Severity:       "error",
+			URL:            result.URL,
+			Message:        fmt.Sprintf("Link header canonical %s conflicts with HTML canonical %s", result.Headers.LinkCanonical, result.Canonical),
+			Value:          conflict,
+			Recommendation: "Declare a single canonical URL, or make the Link header and the <link rel=\"canonical\"> tag agree",
+		})
+	}
+	return issues
+}
+
+// missingSecurityHeaders lists the recommended security headers a page does not send
+func missingSecurityHeaders(pageURL string, headers *models.ResponseHeaders) []string {
+	var missing []string
+	if strings.HasPrefix(strings.ToLower(pageURL), "https://") && headers.StrictTransportSecurity == "" {
+		missing = append(missing, "Strict-Transport-Security")
+	}
+	if headers.ContentSecurityPolicy == "" {
+		missing = append(missing, "Content-Security-Policy")
+	}
+	if headers.XContentTypeOptions == "" {
+		missing = append(missing, "X-Content-Type-Options")
+	}
+	// CSP frame-ancestors supersedes X-Frame-Options
+	if headers.XFrameOptions == "" && cspDirective(headers.ContentSecurityPolicy, "frame-ancestors") == "" {
+		missing = append(missing, "X-Frame-Options")
+	}
+	if headers.ReferrerPolicy == "" {
+		missing = append(missing, "Referrer-Policy")
+	}
+	return missing
+}
+
+// weakSecurityHeaders describes security headers that are present but ineffective
+func weakSecurityHeaders(headers *models.ResponseHeaders) []string {
+	var weak []string
+
+	if hsts := headers.StrictTransportSecurity; hsts != "" {
+		maxAge := -1
+		for _, directive := range strings.Split(hsts, ";") {
+			name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+			if strings.EqualFold(strings.TrimSpace(name), "max-age") {
+				if seconds, err := strconv.Atoi(strings.Trim(strings.TrimSpace(value), `"`)); err == nil {
+					maxAge = seconds
+				}
+			}
+		}
+		switch {
+		case maxAge < 0:
+			weak = append(weak, "Strict-Transport-Security has no valid max-age")
+		case maxAge < hstsMinMaxAge:
+			weak = append(weak, fmt.Sprintf("Strict-Transport-Security max-age %d is under 180 days", maxAge))
+		}
+	}
+
+	if value := headers.XContentTypeOptions; value != "" && !strings.EqualFold(value, "nosniff") {
+		weak = append(weak, fmt.Sprintf("X-Content-Type-Options is %q instead of nosniff", value))
+	}
+
+	if value := headers.XFrameOptions; value != "" && !strings.EqualFold(value, "DENY") && !strings.EqualFold(value, "SAMEORIGIN") {
+		weak = append(weak, fmt.Sprintf("X-Frame-Options %q is not DENY or SAMEORIGIN", value))
+	}
+
+	if csp := headers.ContentSecurityPolicy; csp != "" {
+		scripts := cspDirective(csp, "script-src")
+		if scripts == "" {
+			scripts = cspDirective(csp, "default-src")
+		}
+		for _, source := range []string{"'unsafe-inline'", "'unsafe-eval'"} {
+			if strings.Contains(strings.ToLower(scripts), source) && !strings.Contains(strings.ToLower(scripts), "'strict-dynamic'") {
+				weak = append(weak, fmt.Sprintf("Content-Security-Policy allows %s scripts", strings.Trim(source, "'")))
+			}
+		}
+	}
+
+	if value := strings.ToLower(headers.ReferrerPolicy); strings.Contains(value, "unsafe-url") {
+		weak = append(weak, "Referrer-Policy unsafe-url leaks full URLs to other sites")
+	}
+	return weak
+}
+
+// cspDirective returns the value of a Content-Security-Policy directive, empty when absent
+func cspDirective(policy, name string) string {
+	for _, directive := range strings.Split(policy, ";") {
+		fields := strings.Fields(directive)
+		if len(fields) > 0 && strings.EqualFold(fields[0], name) {
+			if len(fields) == 1 {
+				return "'none'"
+			}
+			return strings.Join(fields[1:], " ")
+		}
+	}
+	return ""
+}
+
+// canonicalHeaderConflict returns both canonicals when the Link header and the HTML disagree
+func canonicalHeaderConflict(result *models.PageResult) string {
+	if result.Headers == nil || result.Headers.LinkCanonical == "" || strings.TrimSpace(result.Canonical) == "" {
+		return ""
+	}
+	header, err := utils.NormalizeURL(result.Headers.LinkCanonical)
+	if err != nil {
+		header = result.Headers.LinkCanonical
+	}
+	// The HTML canonical may be relative to the page
+	html := strings.TrimSpace(result.Canonical)
+	if base, err := url.Parse(result.URL); err == nil {
+		if ref, err := url.Parse(html); err == nil {
+			html = base.ResolveReference(ref).String()
+		}
+	}
+	if normalized, err := utils.NormalizeURL(html); err == nil {
+		html = normalized
+	}
+	if header == html {
+		return ""
+	}
+	return fmt.Sprintf("header %s, HTML %s", result.Headers.LinkCanonical, result.Canonical)
+}
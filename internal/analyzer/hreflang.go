@@ -0,0 +1,257 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dillonlara115/barracudaseo/internal/utils"
+	"github.com/dillonlara115/barracudaseo/pkg/models"
+)
+
+// isoLanguages are the ISO 639-1 language codes accepted in hreflang values
+var isoLanguages = codeSet(`aa ab ae af ak am an ar as av ay az ba be bg bh bi bm bn bo br bs ca ce ch co cr cs cu cv cy
+	da de dv dz ee el en eo es et eu fa ff fi fj fo fr fy ga gd gl gn gu gv ha he hi ho hr ht hu hy hz ia id ie ig ii ik
+	io is it iu ja jv ka kg ki kj kk kl km kn ko kr ks ku kv kw ky la lb lg li ln lo lt lu lv mg mh mi mk ml mn mr ms mt
+	my na nb nd ne ng nl nn no nr nv ny oc oj om or os pa pi pl ps pt qu rm rn ro ru rw sa sc sd se sg si sk sl sm sn so
+	sq sr ss st su sv sw ta te tg th ti tk tl tn to tr ts tt tw ty ug uk ur uz ve vi vo wa wo xh yi yo za zh zu`)
+
+// isoRegions are the ISO 3166-1 alpha-2 region codes accepted in hreflang values
+var isoRegions = codeSet(`ad ae af ag ai al am ao aq ar as at au aw ax az ba bb bd be bf bg bh bi bj bl bm bn bo bq br bs
+	bt bv bw by bz ca cc cd cf cg ch ci ck cl cm cn co cr cu cv cw cx cy cz de dj dk dm do dz ec ee eg eh er es et fi fj
+	fk fm fo fr ga gb gd ge gf gg gh gi gl gm gn gp gq gr gs gt gu gw gy hk hm hn hr ht hu id ie il im in io iq ir is it
+	je jm jo jp ke kg kh ki km kn kp kr kw ky kz la lb lc li lk lr ls lt lu lv ly ma mc md me mf mg mh mk ml mm mn mo mp
+	mq mr ms mt mu mv mw mx my mz na nc ne nf ng ni nl no np nr nu nz om pa pe pf pg ph pk pl pm pn pr ps pt pw py qa re
+	ro rs ru rw sa sb sc sd se sg sh si sj sk sl sm sn so sr ss st sv sx sy sz tc td tf tg th tj tk tl tm tn to tr tt tv
+	tw tz ua ug um us uy uz va vc ve vg vi vn vu wf ws ye yt za zm zw`)
+
+func codeSet(codes string) map[string]bool {
+	set := make(map[string]bool)
+	for _, code := range strings.Fields(codes) {
+		set[code] = true
+	}
+	return set
+}
+
+// validHreflang reports whether an hreflang value is "x-default" or an ISO 639-1 language,
+// optionally followed by a script (e.g. "zh-Hant") and an ISO 3166-1 alpha-2 region
+func validHreflang(value string) bool {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "x-default" {
+		return true
+	}
+	parts := strings.Split(value, "-")
+	if !isoLanguages[parts[0]] {
+		return false
+	}
+	parts = parts[1:]
+	if len(parts) > 0 && len(parts[0]) == 4 {
+		parts = parts[1:] // Script subtag
+	}
+	switch len(parts) {
+	case 0:
+		return true
+	case 1:
+		return isoRegions[parts[0]]
+	}
+	return false
+}
+
+// hreflangAnnotations returns a page's hreflang annotations from its HTML, Link header and
+// sitemap entry, with normalized URLs
+func hreflangAnnotations(result *models.PageResult) []models.Hreflang {
+	annotations := make([]models.Hreflang, 0, len(result.Hreflang))
+	add := func(lang, target, source string) {
+		if normalized, err := utils.NormalizeURL(target); err == nil {
+			target = normalized
+		}
+		annotations = append(annotations, models.Hreflang{Lang: strings.TrimSpace(lang), URL: target, Source: source})
+	}
+	for _, annotation := range result.Hreflang {
+		add(annotation.Lang, annotation.URL, annotation.Source)
+	}
+	if result.Sitemap != nil {
+		for _, alternate := range result.Sitemap.Alternates {
+			add(alternate.Hreflang, alternate.Href, models.HreflangSitemap)
+		}
+	}
+	return annotations
+}
+
+// hreflangIssues validates hreflang annotations across the crawl: language and region codes,
+// self-references, x-default, return links from the alternates, alternates that are not
+// indexable 200 pages, and conflicts with the page's <html lang>
+func hreflangIssues(results []*models.PageResult) []Issue {
+	byURL := make(map[string]*models.PageResult, len(results))
+	annotationsByURL := make(map[string][]models.Hreflang, len(results))
+	for _, result := range results {
+		pageURL, err := utils.NormalizeURL(result.URL)
+		if err != nil {
+			pageURL = result.URL
+		}
+		byURL[pageURL] = result
+		annotationsByURL[pageURL] = hreflangAnnotations(result)
+	}
+
+	var issues []Issue
+	for _, result := range results {
+		if !IsIndexablePage(result) {
+			continue
+		}
+		pageURL, err := utils.NormalizeURL(result.URL)
+		if err != nil {
+			pageURL = result.URL
+		}
+		annotations := annotationsByURL[pageURL]
+		if len(annotations) == 0 {
+			continue
+		}
+
+		var invalid, noReturn, non200, noindex, nonCanonical []string
+		selfLang := ""
+		hasXDefault := false
+		seenTargets := make(map[string]bool)
+		for _, annotation := range annotations {
+			if !validHreflang(annotation.Lang) {
+				invalid = append(invalid, fmt.Sprintf("%s (%s)", annotation.Lang, annotation.Source))
+			}
+			if strings.EqualFold(annotation.Lang, "x-default") {
+				hasXDefault = true
+			}
+			if annotation.URL == pageURL {
+				if selfLang == "" && !strings.EqualFold(annotation.Lang, "x-default") {
+					selfLang = annotation.Lang
+				}
+				continue
+			}
+			if seenTargets[annotation.URL] {
+				continue
+			}
+			seenTargets[annotation.URL] = true
+
+			// Alternates outside the crawl cannot be checked
+			target, crawled := byURL[annotation.URL]
+			if !crawled {
+				continue
+			}
+			switch {
+			case target.StatusCode != 200 || target.Error != "" || len(target.RedirectChain) > 0:
+				status := fmt.Sprintf("HTTP %d", target.StatusCode)
+				if len(target.RedirectChain) > 0 {
+					status = "redirects to " + target.RedirectChain[len(target.RedirectChain)-1]
+				} else if target.StatusCode == 0 {
+					status = target.Error
+				}
+				non200 = append(non200, fmt.Sprintf("%s (%s)", annotation.URL, status))
+				continue
+			case target.IndexabilityStatus != models.IndexabilityIndexable && target.IndexabilityStatus != "":
+				noindex = append(noindex, annotation.URL)
+				continue
+			}
+			if canonical := canonicalElsewhere(target); canonical != "" {
+				nonCanonical = append(nonCanonical, fmt.Sprintf("%s (canonical %s)", annotation.URL, canonical))
+				continue
+			}
+
+			returns := false
+			for _, back := range annotationsByURL[annotation.URL] {
+				if back.URL == pageURL {
+					returns = true
+					break
+				}
+			}
+			if !returns {
+				noReturn = append(noReturn, annotation.URL)
+			}
+		}
+
+		if len(invalid) > 0 {
+			issues = append(issues, Issue{
+				Type:           IssueHreflangInvalidCode,
+				Severity:       "error",
+				URL:            result.URL,
+				Message:        fmt.Sprintf("Invalid hreflang codes: %s", strings.Join(invalid, ", ")),
+				Value:          strings.Join(invalid, ", "),
+				Recommendation: "Use an ISO 639-1 language code, optionally followed by an ISO 3166-1 alpha-2 region (e.g. en-GB), or x-default",
+			})
+		}
+		if selfLang == "" {
+			issues = append(issues, Issue{
+				Type:           IssueHreflangMissingSelf,
+				Severity:       "warning",
+				URL:            result.URL,
+				Message:        "Hreflang annotations do not include the page itself",
+				Recommendation: "Add an hreflang annotation that points to the page's own URL",
+			})
+		}
+		if !hasXDefault {
+			issues = append(issues, Issue{
+				Type:           IssueHreflangMissingXDefault,
+				Severity:       "info",
+				URL:            result.URL,
+				Message:        "Hreflang annotations have no x-default",
+				Recommendation: "Add an x-default alternate for users whose language is not targeted",
+			})
+		}
+		if len(noReturn) > 0 {
+			issues = append(issues, Issue{
+				Type:           IssueHreflangMissingReturn,
+				Severity:       "error",
+				URL:            result.URL,
+				Message:        fmt.Sprintf("Hreflang alternates do not link back: %s", strings.Join(noReturn, ", ")),
+				Value:          strings.Join(noReturn, ", "),
+				Recommendation: "Every alternate must annotate all other versions, including this page, or search engines ignore the annotations",
+			})
+		}
+		if len(non200) > 0 {
+			issues = append(issues, Issue{
+				Type:           IssueHreflangTargetNon200,
+				Severity:       "error",
+				URL:            result.URL,
+				Message:        fmt.Sprintf("Hreflang alternates are not 200 pages: %s", strings.Join(non200, ", ")),
+				Value:          strings.Join(non200, ", "),
+				Recommendation: "Point hreflang annotations at the final, working URL of each alternate",
+			})
+		}
+		if len(noindex) > 0 {
+			issues = append(issues, Issue{
+				Type:           IssueHreflangTargetNoindex,
+				Severity:       "error",
+				URL:            result.URL,
+				Message:        fmt.Sprintf("Hreflang alternates are not indexable: %s", strings.Join(noindex, ", ")),
+				Value:          strings.Join(noindex, ", "),
+				Recommendation: "Remove noindex from the alternates or drop them from the hreflang annotations",
+			})
+		}
+		if len(nonCanonical) > 0 {
+			issues = append(issues, Issue{
+				Type:           IssueHreflangTargetNonCanonical,
+				Severity:       "warning",
+				URL:            result.URL,
+				Message:        fmt.Sprintf("Hreflang alternates are canonicalised elsewhere: %s", strings.Join(nonCanonical, ", ")),
+				Value:          strings.Join(nonCanonical, ", "),
+				Recommendation: "Point hreflang annotations at the canonical URL of each alternate",
+			})
+		}
+		if selfLang != "" && result.HTMLLang != "" && !sameLanguage(selfLang, result.HTMLLang) {
+			issues = append(issues, Issue{
+				Type:           IssueHreflangLangMismatch,
+				Severity:       "warning",
+				URL:            result.URL,
+				Message:        fmt.Sprintf("Hreflang %q conflicts with <html lang=%q>", selfLang, result.HTMLLang),
+				Value:          fmt.Sprintf("%s / %s", selfLang, result.HTMLLang),
+				Recommendation: "Make the page's hreflang and <html lang> declare the same language",
+			})
+		}
+	}
+	return issues
+}
+
+// sameLanguage reports whether two language tags share their primary language ("en-GB" and "en")
+func sameLanguage(a, b string) bool {
+	primary := func(tag string) string {
+		tag = strings.ToLower(strings.TrimSpace(strings.ReplaceAll(tag, "_", "-")))
+		language, _, _ := strings.Cut(tag, "-")
+		return language
+	}
+	return primary(a) == primary(b)
+}
@@ -209,7 +209,14 @@ func AnalyzeImages(results []*models.PageResult, timeout time.Duration) []Issue
 
 // fetchImageSizesInParallel fetches sizes for the given URLs using a worker pool.
 func fetchImageSizesInParallel(urls map[string]bool, timeout time.Duration) map[string]ImageSizeInfo {
-	cache := make(map[string]ImageSizeInfo)
+	return fetchInParallel(urls, imageAnalysisWorkers, func(url string) ImageSizeInfo {
+		return CheckImageSize(url, timeout)
+	})
+}
+
+// fetchInParallel runs check for each URL on a pool of up to workers goroutines and returns the results by URL
+func fetchInParallel[T any](urls map[string]bool, workers int, check func(url string) T) map[string]T {
+	cache := make(map[string]T)
 	var mu sync.Mutex
 
 	work := make(chan string, len(urls))
@@ -219,7 +226,6 @@ func fetchImageSizesInParallel(urls map[string]bool, timeout time.Duration) map[
 	close(work)
 
 	var wg sync.WaitGroup
-	workers := imageAnalysisWorkers
 	if len(urls) < workers {
 		workers = len(urls)
 	}
@@ -232,7 +238,7 @@ func fetchImageSizesInParallel(urls map[string]bool, timeout time.Duration) map[
 		go func() {
 			defer wg.Done()
 			for url := range work {
-				info := CheckImageSize(url, timeout)
+				info := check(url)
 				mu.Lock()
 				cache[url] = info
 				mu.Unlock()
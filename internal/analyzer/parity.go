@@ -0,0 +1,123 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dillonlara115/barracudaseo/pkg/models"
+)
+
+// parityIssues compares the smartphone version of a page with the desktop version fetched in
+// parity mode. Google indexes the smartphone version, so anything missing from it is lost.
+func parityIssues(result *models.PageResult) []Issue {
+	desktop := result.Desktop
+	if desktop == nil {
+		return nil
+	}
+
+	if desktop.StatusCode != result.StatusCode {
+		return []Issue{{
+			Type:           IssueMobileStatusMismatch,
+			Severity:       "error",
+			URL:            result.URL,
+			Message:        fmt.Sprintf("Status differs between devices: mobile %d, desktop %d", result.StatusCode, desktop.StatusCode),
+			Value:          fmt.Sprintf("%d / %d", result.StatusCode, desktop.StatusCode),
+			Recommendation: "Serve the same status code to smartphone and desktop crawlers",
+		}}
+	}
+	if result.StatusCode != 200 || result.Error != "" || desktop.Error != "" {
+		return nil
+	}
+
+	var issues []Issue
+	if robots := robotsMismatches(result, desktop); len(robots) > 0 {
+		issues = append(issues, Issue{
+			Type:           IssueMobileRobotsMismatch,
+			Severity:       "error",
+			URL:            result.URL,
+			Message:        fmt.Sprintf("Robots directives differ between devices: %s", strings.Join(robots, "; ")),
+			Value:          strings.Join(robots, "; "),
+			Recommendation: "Serve the same meta robots and X-Robots-Tag directives to smartphone and desktop crawlers",
+		})
+	}
+	if content := contentMismatches(result, desktop); len(content) > 0 {
+		issues = append(issues, Issue{
+			Type:           IssueMobileContentMismatch,
+			Severity:       "warning",
+			URL:            result.URL,
+			Message:        fmt.Sprintf("Mobile version differs from desktop: %s", strings.Join(content, ", ")),
+			Value:          strings.Join(content, ", "),
+			Recommendation: "Keep titles, meta descriptions, canonicals, headings and internal links equivalent on the mobile version",
+		})
+	}
+	return issues
+}
+
+// robotsMismatches returns the robots directives that differ between the mobile and desktop versions
+func robotsMismatches(mobile, desktop *models.PageResult) []string {
+	var fields []string
+	compare := func(name, mobileValue, desktopValue string) {
+		if !strings.EqualFold(strings.TrimSpace(mobileValue), strings.TrimSpace(desktopValue)) {
+			fields = append(fields, fmt.Sprintf("%s mobile %q, desktop %q", name, mobileValue, desktopValue))
+		}
+	}
+
+	compare("meta robots", mobile.MetaRobots, desktop.MetaRobots)
+	compare("X-Robots-Tag", mobile.XRobotsTag, desktop.XRobotsTag)
+	if len(fields) == 0 && mobile.IndexabilityStatus != desktop.IndexabilityStatus {
+		fields = append(fields, fmt.Sprintf("indexability mobile %s, desktop %s", mobile.IndexabilityStatus, desktop.IndexabilityStatus))
+	}
+	return fields
+}
+
+// contentMismatches returns the SEO fields that differ between the mobile and desktop versions
+func contentMismatches(mobile, desktop *models.PageResult) []string {
+	var fields []string
+	compare := func(name, mobileValue, desktopValue string) {
+		if mobileValue == desktopValue {
+			return
+		}
+		switch {
+		case mobileValue == "":
+			fields = append(fields, name+" missing on mobile")
+		case desktopValue == "":
+			fields = append(fields, name+" only on mobile")
+		default:
+			fields = append(fields, name+" differs")
+		}
+	}
+
+	compare("title", strings.TrimSpace(mobile.Title), strings.TrimSpace(desktop.Title))
+	compare("meta description", strings.TrimSpace(mobile.MetaDesc), strings.TrimSpace(desktop.MetaDesc))
+	compare("canonical", mobile.Canonical, desktop.Canonical)
+	compare("H1", strings.Join(mobile.H1, " | "), strings.Join(desktop.H1, " | "))
+
+	onlyDesktop, onlyMobile := linkSetDifference(desktop.InternalLinks, mobile.InternalLinks)
+	if onlyDesktop > 0 || onlyMobile > 0 {
+		fields = append(fields, fmt.Sprintf("internal links differ (%d only on desktop, %d only on mobile)", onlyDesktop, onlyMobile))
+	}
+	return fields
+}
+
+// linkSetDifference counts the links only in a and the links only in b
+func linkSetDifference(a, b []string) (onlyA, onlyB int) {
+	inA := make(map[string]bool, len(a))
+	for _, link := range a {
+		inA[link] = true
+	}
+	inB := make(map[string]bool, len(b))
+	for _, link := range b {
+		inB[link] = true
+	}
+	for link := range inA {
+		if !inB[link] {
+			onlyA++
+		}
+	}
+	for link := range inB {
+		if !inA[link] {
+			onlyB++
+		}
+	}
+	return onlyA, onlyB
+}
@@ -0,0 +1,127 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/dillonlara115/barracudaseo/internal/utils"
+	"github.com/dillonlara115/barracudaseo/pkg/models"
+)
+
+const (
+	// SlowTTFBMs is the time to first byte above which a page is reported as slow to respond
+	SlowTTFBMs = 800
+
+	// SlowDownloadMs is the body download time above which a page is reported as slow to transfer
+	SlowDownloadMs = 1000
+)
+
+// PerformanceSummary gives percentiles of the network timings across crawled pages
+type PerformanceSummary struct {
+	Pages        int         `json:"pages"` // Pages with timing details
+	ResponseTime Percentiles `json:"response_time"`
+	DNS          Percentiles `json:"dns"`
+	Connect      Percentiles `json:"connect"`
+	TLS          Percentiles `json:"tls"`
+	TTFB         Percentiles `json:"ttfb"`
+	Download     Percentiles `json:"download"`
+}
+
+// Percentiles summarizes a set of durations in milliseconds
+type Percentiles struct {
+	P50 int64 `json:"p50"`
+	P75 int64 `json:"p75"`
+	P90 int64 `json:"p90"`
+	P95 int64 `json:"p95"`
+	P99 int64 `json:"p99"`
+	Max int64 `json:"max"`
+}
+
+// timingIssues reports a slow server response (TTFB) separately from a slow body transfer
+func timingIssues(result *models.PageResult) []Issue {
+	timing := result.Timing
+	if timing == nil {
+		return nil
+	}
+
+	var issues []Issue
+	if timing.TTFBMs > SlowTTFBMs {
+		issues = append(issues, Issue{
+			Type:     IssueSlowTTFB,
+			Severity: "warning",
+			URL:      result.URL,
+			Message: fmt.Sprintf("Time to first byte is %d ms (DNS %d ms, connect %d ms, TLS %d ms)",
+				timing.TTFBMs, timing.DNSMs, timing.ConnectMs, timing.TLSMs),
+			Value:          fmt.Sprintf("%d ms", timing.TTFBMs),
+			Recommendation: fmt.Sprintf("Reduce server processing time below %d ms with caching, a CDN or faster backend queries", SlowTTFBMs),
+		})
+	}
+	if timing.DownloadMs > SlowDownloadMs {
+		issues = append(issues, Issue{
+			Type:           IssueSlowDownload,
+			Severity:       "warning",
+			URL:            result.URL,
+			Message:        fmt.Sprintf("Downloading the page took %d ms (%d KB)", timing.DownloadMs, result.Size/1024),
+			Value:          fmt.Sprintf("%d ms", timing.DownloadMs),
+			Recommendation: "Compress the response and reduce the HTML size, or serve it from a CDN closer to users",
+		})
+	}
+	return issues
+}
+
+// BuildPerformanceSummary computes percentiles of the response time and of each network phase
+// over the crawled pages that have timing details
+func BuildPerformanceSummary(results []*models.PageResult) *PerformanceSummary {
+	var responseTime, dns, connect, tlsHandshake, ttfb, download []int64
+	for _, result := range results {
+		if result.Timing == nil || utils.IsImageURL(result.URL) {
+			continue
+		}
+		responseTime = append(responseTime, result.ResponseTime)
+		ttfb = append(ttfb, result.Timing.TTFBMs)
+		download = append(download, result.Timing.DownloadMs)
+		// Connection setup only happens on new connections
+		if !result.Timing.Reused {
+			dns = append(dns, result.Timing.DNSMs)
+			connect = append(connect, result.Timing.ConnectMs)
+			if result.TLS != nil {
+				tlsHandshake = append(tlsHandshake, result.Timing.TLSMs)
+			}
+		}
+	}
+
+	return &PerformanceSummary{
+		Pages:        len(ttfb),
+		ResponseTime: percentiles(responseTime),
+		DNS:          percentiles(dns),
+		Connect:      percentiles(connect),
+		TLS:          percentiles(tlsHandshake),
+		TTFB:         percentiles(ttfb),
+		Download:     percentiles(download),
+	}
+}
+
+// percentiles computes nearest-rank percentiles of values
+func percentiles(values []int64) Percentiles {
+	if len(values) == 0 {
+		return Percentiles{}
+	}
+	sorted := append([]int64(nil), values...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	rank := func(p int) int64 {
+		index := (p*len(sorted)+99)/100 - 1
+		if index < 0 {
+			index = 0
+		}
+		return sorted[index]
+	}
+	return Percentiles{
+		P50: rank(50),
+		P75: rank(75),
+		P90: rank(90),
+		P95: rank(95),
+		P99: rank(99),
+		Max: sorted[len(sorted)-1],
+	}
+}
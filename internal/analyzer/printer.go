@@ -2,7 +2,9 @@ package analyzer
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"strings"
 	"text/tabwriter"
 )
 
@@ -61,6 +63,92 @@ func PrintSummary(summary *Summary) {
 		fmt.Fprintf(w, "\n")
 	}
 
+	// Network timing percentiles
+	if performance := summary.Performance; performance != nil && performance.Pages > 0 {
+		fmt.Fprintf(os.Stdout, "Performance (%d pages):\n", performance.Pages)
+		fmt.Fprintf(w, "  \tp50\tp75\tp90\tp95\tp99\tmax\n")
+		printPercentiles(w, "Response Time", performance.ResponseTime)
+		printPercentiles(w, "DNS", performance.DNS)
+		printPercentiles(w, "Connect", performance.Connect)
+		printPercentiles(w, "TLS", performance.TLS)
+		printPercentiles(w, "TTFB", performance.TTFB)
+		printPercentiles(w, "Download", performance.Download)
+		fmt.Fprintf(w, "\n")
+	}
+
+	// Structured data per schema.org type
+	if structured := summary.StructuredData; structured != nil && structured.PagesWithStructuredData > 0 {
+		fmt.Fprintf(os.Stdout, "Structured Data (%d pages):\n", structured.PagesWithStructuredData)
+		for _, entry := range structured.Types {
+			fmt.Fprintf(w, "  %s:\t%d items on %d pages (%s)\t%d with errors, %d with warnings\n",
+				entry.Type, entry.Items, entry.Pages, strings.Join(entry.Formats, ", "), entry.WithErrors, entry.WithWarnings)
+		}
+		if structured.InvalidJSONBlocks > 0 {
+			fmt.Fprintf(w, "  Invalid JSON-LD blocks:\t%d\n", structured.InvalidJSONBlocks)
+		}
+		fmt.Fprintf(w, "\n")
+	}
+
+	// Shared titles, meta descriptions and H1s, largest groups first
+	if len(summary.DuplicateGroups) > 0 {
+		fmt.Fprintf(os.Stdout, "Duplicate Titles, Meta Descriptions and H1s:\n")
+		labels := map[string]string{DuplicateFieldTitle: "Title", DuplicateFieldMetaDesc: "Meta Description", DuplicateFieldH1: "H1"}
+		for i, group := range summary.DuplicateGroups {
+			if i >= 10 {
+				fmt.Fprintf(w, "  ... and %d more groups\n", len(summary.DuplicateGroups)-10)
+				break
+			}
+			value := group.Value
+			if len(value) > 60 {
+				value = value[:57] + "..."
+			}
+			fmt.Fprintf(w, "  %s %q:\t%d pages\n", labels[group.Field], value, len(group.URLs))
+			for j, pageURL := range group.URLs {
+				if j >= 3 {
+					fmt.Fprintf(w, "    ... and %d more\n", len(group.URLs)-3)
+					break
+				}
+				fmt.Fprintf(w, "    • %s\n", pageURL)
+			}
+		}
+		fmt.Fprintf(w, "\n")
+	}
+
+	// Duplicate content clusters, largest first
+	if duplicates := summary.DuplicateContent; duplicates != nil && len(duplicates.Clusters) > 0 {
+		fmt.Fprintf(os.Stdout, "Duplicate Content (%d clusters, %d pages):\n", len(duplicates.Clusters), duplicates.Pages)
+		for i, cluster := range duplicates.Clusters {
+			if i >= 5 {
+				fmt.Fprintf(w, "  ... and %d more clusters\n", len(duplicates.Clusters)-5)
+				break
+			}
+			match := "identical"
+			if !cluster.Exact {
+				match = fmt.Sprintf("%.0f%% similar", cluster.Similarity*100)
+			}
+			fmt.Fprintf(w, "  %s:\t%d pages, %s\n", cluster.Canonical, len(cluster.URLs), match)
+		}
+		fmt.Fprintf(w, "\n")
+	}
+
+	// Transport security
+	if security := summary.Security; security != nil && (security.HTTPSPages > 0 || security.HTTPPages > 0) {
+		fmt.Fprintf(os.Stdout, "Security:\n")
+		fmt.Fprintf(w, "  HTTPS Pages:\t%d\n", security.HTTPSPages)
+		fmt.Fprintf(w, "  HTTP Pages:\t%d\n", security.HTTPPages)
+		fmt.Fprintf(w, "  Pages with Mixed Content:\t%d\n", security.MixedContentPages)
+		for _, host := range security.Hosts {
+			status := fmt.Sprintf("%s, expires %s (%d days)", host.Version, host.NotAfter.Format("2006-01-02"), host.DaysUntilExpiry)
+			if host.Error != "" {
+				status = "certificate rejected"
+			} else if !host.CoversHost {
+				status += ", does not cover host"
+			}
+			fmt.Fprintf(w, "  %s:\t%s\n", host.Host, status)
+		}
+		fmt.Fprintf(w, "\n")
+	}
+
 	// Top issues detail
 	if len(summary.Issues) > 0 {
 		fmt.Fprintf(os.Stdout, "Top Issues:\n")
@@ -98,13 +186,31 @@ func PrintSummary(summary *Summary) {
 	fmt.Fprintf(os.Stdout, "═══════════════════════════════════════════════════════════\n")
 }
 
+// printPercentiles prints one row of the performance table, in milliseconds
+func printPercentiles(w io.Writer, name string, p Percentiles) {
+	fmt.Fprintf(w, "  %s:\t%d ms\t%d ms\t%d ms\t%d ms\t%d ms\t%d ms\n", name, p.P50, p.P75, p.P90, p.P95, p.P99, p.Max)
+}
+
 func getIssueIcon(issueType IssueType) string {
 	switch issueType {
-	case IssueMissingH1, IssueMissingTitle, IssueMissingMetaDesc, IssueBrokenLink, IssueBrokenImage, IssueEmptyH1:
+	case IssueMissingH1, IssueMissingTitle, IssueMissingMetaDesc, IssueBrokenLink, IssueBrokenImage, IssueEmptyH1,
+		IssueMobileStatusMismatch, IssueMobileRobotsMismatch, IssueRobotsTxtUnreachable, IssueSitemapNon200, IssueCanonicalHeaderConflict,
+		IssueRedirectLoop, IssueTooManyRedirects, IssueRedirectToError, IssueMixedContent, IssueCertificateInvalid, IssueBrokenResource,
+		IssueStructuredDataInvalid, IssueStructuredDataMissingRequired,
+		IssueHreflangInvalidCode, IssueHreflangMissingReturn, IssueHreflangTargetNon200, IssueHreflangTargetNoindex, IssueBrokenSocialImage:
 		return "🔴"
-	case IssueLongTitle, IssueLongMetaDesc, IssueShortTitle, IssueShortMetaDesc, IssueMultipleH1, IssueRedirectChain, IssueLargeImage, IssueMissingImageAlt:
+	case IssueLongTitle, IssueLongMetaDesc, IssueShortTitle, IssueShortMetaDesc, IssueMultipleH1, IssueRedirectChain, IssueLargeImage, IssueMissingImageAlt, IssueJSOnlyContent,
+		IssueMobileContentMismatch, IssueRobotsTxtSyntax, IssueBlockedByRobots,
+		IssueSitemapRedirect, IssueSitemapNoindex, IssueSitemapCanonicalized, IssueSitemapBlocked, IssueSitemapOrphan,
+		IssueMissingCompression, IssueMissingSecurityHeaders, IssueTemporaryRedirect, IssueCertificateExpiring, IssueOldTLSVersion,
+		IssueRenderBlockingScript, IssuePageWeight, IssueSlowTTFB, IssueSlowDownload,
+		IssueHreflangMissingSelf, IssueHreflangTargetNonCanonical, IssueHreflangLangMismatch,
+		IssueMissingOpenGraph, IssueOGURLMismatch, IssueSmallSocialImage, IssueDuplicateSocialTitle, IssueDuplicateContent,
+		IssueDuplicateTitle, IssueDuplicateMetaDesc:
 		return "⚠️"
-	case IssueNoCanonical, IssueSlowResponse:
+	case IssueNoCanonical, IssueSlowResponse, IssueMissingFromSitemap, IssueMissingCacheHeaders, IssueWeakSecurityHeaders,
+		IssueHTTPToHTTPSRedirect, IssueWWWRedirect, IssueStructuredDataMissingRecommended,
+		IssueHreflangMissingXDefault, IssueDuplicateH1:
 		return "ℹ️"
 	default:
 		return "•"
@@ -135,6 +241,18 @@ func formatIssueType(issueType IssueType) string {
 		return "Slow Response"
 	case IssueRedirectChain:
 		return "Redirect Chain"
+	case IssueRedirectLoop:
+		return "Redirect Loops"
+	case IssueTooManyRedirects:
+		return "Too Many Redirects"
+	case IssueRedirectToError:
+		return "Redirects to Error Pages"
+	case IssueTemporaryRedirect:
+		return "Temporary Redirects (302/307)"
+	case IssueHTTPToHTTPSRedirect:
+		return "HTTP to HTTPS Redirects"
+	case IssueWWWRedirect:
+		return "www Host Redirects"
 	case IssueNoCanonical:
 		return "No Canonical"
 	case IssueBrokenLink:
@@ -145,6 +263,102 @@ func formatIssueType(issueType IssueType) string {
 		return "Multiple H1 Tags"
 	case IssueEmptyH1:
 		return "Empty H1 Tag"
+	case IssueJSOnlyContent:
+		return "JavaScript-Only Content"
+	case IssueMobileStatusMismatch:
+		return "Mobile/Desktop Status Mismatch"
+	case IssueMobileRobotsMismatch:
+		return "Mobile/Desktop Robots Mismatch"
+	case IssueMobileContentMismatch:
+		return "Mobile/Desktop Content Mismatch"
+	case IssueRobotsTxtUnreachable:
+		return "robots.txt Unreachable"
+	case IssueRobotsTxtSyntax:
+		return "robots.txt Syntax Problems"
+	case IssueBlockedByRobots:
+		return "Linked URLs Blocked by robots.txt"
+	case IssueSitemapNon200:
+		return "Sitemap URLs Not Returning 200"
+	case IssueSitemapRedirect:
+		return "Redirected Sitemap URLs"
+	case IssueSitemapNoindex:
+		return "Noindex Sitemap URLs"
+	case IssueSitemapCanonicalized:
+		return "Canonicalised Sitemap URLs"
+	case IssueSitemapBlocked:
+		return "Sitemap URLs Blocked by robots.txt"
+	case IssueSitemapOrphan:
+		return "Orphaned Sitemap URLs"
+	case IssueMissingFromSitemap:
+		return "Pages Missing from Sitemap"
+	case IssueMissingCompression:
+		return "Uncompressed Pages"
+	case IssueMissingCacheHeaders:
+		return "Missing Cache Headers"
+	case IssueMissingSecurityHeaders:
+		return "Missing Security Headers"
+	case IssueWeakSecurityHeaders:
+		return "Weak Security Headers"
+	case IssueCanonicalHeaderConflict:
+		return "Link Header Canonical Conflicts"
+	case IssueMixedContent:
+		return "Mixed Content"
+	case IssueCertificateInvalid:
+		return "Invalid TLS Certificates"
+	case IssueCertificateExpiring:
+		return "Expiring TLS Certificates"
+	case IssueOldTLSVersion:
+		return "Outdated TLS Versions"
+	case IssueBrokenResource:
+		return "Broken Resources"
+	case IssueRenderBlockingScript:
+		return "Render-Blocking Scripts"
+	case IssuePageWeight:
+		return "Pages Over Weight Budget"
+	case IssueSlowTTFB:
+		return "Slow Time to First Byte"
+	case IssueSlowDownload:
+		return "Slow Page Downloads"
+	case IssueStructuredDataInvalid:
+		return "Invalid Structured Data"
+	case IssueStructuredDataMissingRequired:
+		return "Structured Data Missing Required Properties"
+	case IssueStructuredDataMissingRecommended:
+		return "Structured Data Missing Recommended Properties"
+	case IssueHreflangInvalidCode:
+		return "Invalid Hreflang Codes"
+	case IssueHreflangMissingSelf:
+		return "Hreflang Missing Self-Reference"
+	case IssueHreflangMissingXDefault:
+		return "Hreflang Missing x-default"
+	case IssueHreflangMissingReturn:
+		return "Hreflang Missing Return Links"
+	case IssueHreflangTargetNon200:
+		return "Hreflang Alternates Not 200"
+	case IssueHreflangTargetNoindex:
+		return "Hreflang Alternates Noindex"
+	case IssueHreflangTargetNonCanonical:
+		return "Hreflang Alternates Not Canonical"
+	case IssueHreflangLangMismatch:
+		return "Hreflang Conflicts with HTML lang"
+	case IssueMissingOpenGraph:
+		return "Missing Open Graph Tags"
+	case IssueOGURLMismatch:
+		return "og:url Differs from Canonical"
+	case IssueBrokenSocialImage:
+		return "Broken Social Images"
+	case IssueSmallSocialImage:
+		return "Small Social Images"
+	case IssueDuplicateSocialTitle:
+		return "Duplicate Social Titles"
+	case IssueDuplicateContent:
+		return "Duplicate Content"
+	case IssueDuplicateTitle:
+		return "Duplicate Titles"
+	case IssueDuplicateMetaDesc:
+		return "Duplicate Meta Descriptions"
+	case IssueDuplicateH1:
+		return "Duplicate H1s"
 	default:
 		return string(issueType)
 	}
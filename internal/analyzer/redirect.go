@@ -0,0 +1,125 @@
+package analyzer
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/dillonlara115/barracudaseo/pkg/models"
+)
+
+// redirectIssues audits the redirect hops followed to reach a page: loops, chains too long to
+// follow, chains ending in errors, temporary redirects and hops that only switch to HTTPS or
+// add/remove www.
+func redirectIssues(result *models.PageResult) []Issue {
+	if len(result.RedirectHops) == 0 {
+		return nil
+	}
+	path := formatRedirectHops(result)
+
+	if result.RedirectLoop {
+		return []Issue{{
+			Type:           IssueRedirectLoop,
+			Severity:       "error",
+			URL:            result.URL,
+			Message:        fmt.Sprintf("Redirect loop: %s", path),
+			Value:          path,
+			Recommendation: "Fix the redirect rules so the URL resolves to a page",
+		}}
+	}
+
+	var issues []Issue
+	if result.TooManyRedirects {
+		issues = append(issues, Issue{
+			Type:           IssueTooManyRedirects,
+			Severity:       "error",
+			URL:            result.URL,
+			Message:        fmt.Sprintf("Gave up after %d redirects without reaching a page: %s", len(result.RedirectHops), path),
+			Value:          path,
+			Recommendation: "Redirect straight to the final URL; crawlers stop following long chains",
+		})
+	}
+	if result.StatusCode >= 400 {
+		issues = append(issues, Issue{
+			Type:           IssueRedirectToError,
+			Severity:       "error",
+			URL:            result.URL,
+			Message:        fmt.Sprintf("Redirect chain ends in HTTP %d: %s", result.StatusCode, path),
+			Value:          path,
+			Recommendation: "Redirect to a working page, or update links to point at a live URL",
+		})
+	}
+
+	var temporary, https, www []string
+	for _, hop := range result.RedirectHops {
+		if hop.StatusCode == 302 || hop.StatusCode == 307 {
+			temporary = append(temporary, fmt.Sprintf("%s (%d)", hop.URL, hop.StatusCode))
+		}
+		from, err := url.Parse(hop.URL)
+		if err != nil {
+			continue
+		}
+		to, err := url.Parse(hop.Target)
+		if err != nil {
+			continue
+		}
+		if from.Scheme == "http" && to.Scheme == "https" {
+			https = append(https, hop.URL)
+		}
+		if fromHost, toHost := strings.ToLower(from.Hostname()), strings.ToLower(to.Hostname()); fromHost != toHost &&
+			strings.TrimPrefix(fromHost, "www.") == strings.TrimPrefix(toHost, "www.") {
+			www = append(www, fmt.Sprintf("%s -> %s", fromHost, toHost))
+		}
+	}
+
+	if len(temporary) > 0 {
+		issues = append(issues, Issue{
+			Type:           IssueTemporaryRedirect,
+			Severity:       "warning",
+			URL:            result.URL,
+			Message:        fmt.Sprintf("Temporary redirect: %s", strings.Join(temporary, ", ")),
+			Value:          strings.Join(temporary, ", "),
+			Recommendation: "Use 301 or 308 for permanent moves so search engines transfer signals to the target",
+		})
+	}
+	if len(https) > 0 {
+		issues = append(issues, Issue{
+			Type:           IssueHTTPToHTTPSRedirect,
+			Severity:       "info",
+			URL:            result.URL,
+			Message:        fmt.Sprintf("Redirects from HTTP to HTTPS: %s", strings.Join(https, ", ")),
+			Value:          strings.Join(https, ", "),
+			Recommendation: "Link directly to the HTTPS URL",
+		})
+	}
+	if len(www) > 0 {
+		issues = append(issues, Issue{
+			Type:           IssueWWWRedirect,
+			Severity:       "info",
+			URL:            result.URL,
+			Message:        fmt.Sprintf("Redirects between www and non-www hosts: %s", strings.Join(www, ", ")),
+			Value:          strings.Join(www, ", "),
+			Recommendation: "Link directly to the preferred host",
+		})
+	}
+	return issues
+}
+
+// formatRedirectHops describes the redirect path with the status of each hop,
+// e.g. "http://a.com/ (301) -> https://a.com/ (302) -> https://a.com/home"
+func formatRedirectHops(result *models.PageResult) string {
+	parts := make([]string, 0, len(result.RedirectHops)+1)
+	for _, hop := range result.RedirectHops {
+		parts = append(parts, fmt.Sprintf("%s (%d)", hop.URL, hop.StatusCode))
+	}
+	parts = append(parts, result.RedirectHops[len(result.RedirectHops)-1].Target)
+	return strings.Join(parts, " -> ")
+}
+
+// redirectPath describes how a page was reached, with hop statuses when they were recorded
+func redirectPath(result *models.PageResult) string {
+	if len(result.RedirectHops) > 0 {
+		return formatRedirectHops(result)
+	}
+	return strings.Join(result.RedirectChain, " -> ")
+}
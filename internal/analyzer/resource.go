@@ -0,0 +1,209 @@
+package analyzer
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/dillonlara115/barracudaseo/internal/utils"
+	"github.com/dillonlara115/barracudaseo/pkg/models"
+)
+
+const (
+	// DefaultPageWeightBudgetKB is the page weight (HTML plus subresources) above which a page is reported
+	DefaultPageWeightBudgetKB = 3072
+
+	// resourceAnalysisWorkers is the number of concurrent resource checks
+	resourceAnalysisWorkers = 16
+
+	// maxResourceReadBytes caps how much of a resource without Content-Length is read to measure it
+	maxResourceReadBytes = 10 * 1024 * 1024
+)
+
+// ResourceCheck is the outcome of fetching a subresource
+type ResourceCheck struct {
+	URL        string
+	StatusCode int
+	Size       int64
+	Error      error
+}
+
+// CheckResource fetches a subresource's status and size with client. HEAD is tried first; servers that reject
+// HEAD or omit Content-Length get a GET whose body is read (up to a limit) to measure the size.
+func CheckResource(resourceURL string, client *http.Client) ResourceCheck {
+	check := ResourceCheck{URL: resourceURL}
+
+	req, err := http.NewRequest("HEAD", resourceURL, nil)
+	if err != nil {
+		check.Error = err
+		return check
+	}
+	resp, err := client.Do(req)
+	if err == nil {
+		resp.Body.Close()
+		check.StatusCode = resp.StatusCode
+		if resp.StatusCode == 200 && resp.ContentLength > 0 {
+			check.Size = resp.ContentLength
+			return check
+		}
+	}
+
+	getReq, err := http.NewRequest("GET", resourceURL, nil)
+	if err != nil {
+		check.Error = err
+		return check
+	}
+	getResp, err := client.Do(getReq)
+	if err != nil {
+		check.Error = err
+		return check
+	}
+	defer getResp.Body.Close()
+
+	check.StatusCode = getResp.StatusCode
+	if getResp.StatusCode >= 400 {
+		return check
+	}
+	if getResp.ContentLength > 0 {
+		check.Size = getResp.ContentLength
+		return check
+	}
+	check.Size, _ = io.Copy(io.Discard, io.LimitReader(getResp.Body, maxResourceReadBytes))
+	return check
+}
+
+// CheckResources fetches the status and size of every subresource of the crawled HTML pages,
+// in parallel (resourceAnalysisWorkers), and records them on the pages' Resources. Pass the
+// crawl's client (crawler.Manager.HTTPClient) so resources behind authentication load and
+// requests respect the crawl's throttle.
+func CheckResources(results []*models.PageResult, client *http.Client) {
+	urls := make(map[string]bool)
+	for _, result := range results {
+		if !hasCheckableResources(result) {
+			continue
+		}
+		for _, resource := range result.Resources {
+			urls[resource.URL] = true
+		}
+	}
+
+	cache := fetchResourcesInParallel(urls, client)
+	for _, result := range results {
+		if !hasCheckableResources(result) {
+			continue
+		}
+		for i := range result.Resources {
+			check := cache[result.Resources[i].URL]
+			result.Resources[i].StatusCode = check.StatusCode
+			result.Resources[i].Size = check.Size
+			result.Resources[i].Error = ""
+			if check.Error != nil {
+				result.Resources[i].Error = check.Error.Error()
+			}
+		}
+	}
+}
+
+// AnalyzeResources checks every subresource and reports broken ones and pages heavier than
+// budgetKB (DefaultPageWeightBudgetKB when budgetKB is not positive). Broken images are left to
+// AnalyzeImages.
+func AnalyzeResources(results []*models.PageResult, client *http.Client, budgetKB int) []Issue {
+	if budgetKB <= 0 {
+		budgetKB = DefaultPageWeightBudgetKB
+	}
+	CheckResources(results, client)
+
+	var issues []Issue
+	totalResources := 0
+	brokenResources := 0
+	overBudget := 0
+	for _, result := range results {
+		if !hasCheckableResources(result) {
+			continue
+		}
+		totalResources += len(result.Resources)
+
+		weight := result.Size
+		for _, resource := range result.Resources {
+			weight += resource.Size
+			if resource.Type == models.ResourceImage || !isBrokenResource(resource) {
+				continue
+			}
+			brokenResources++
+			status := resource.Error
+			if status == "" {
+				status = fmt.Sprintf("HTTP %d", resource.StatusCode)
+			}
+			issues = append(issues, Issue{
+				Type:           IssueBrokenResource,
+				Severity:       "error",
+				URL:            result.URL,
+				Message:        fmt.Sprintf("Broken %s (%s): %s", resource.Type, status, resource.URL),
+				Value:          resource.URL,
+				Recommendation: "Fix or remove the reference so the page doesn't load a missing file",
+			})
+		}
+
+		if weight > int64(budgetKB)*1024 {
+			overBudget++
+			issues = append(issues, Issue{
+				Type:     IssuePageWeight,
+				Severity: "warning",
+				URL:      result.URL,
+				Message: fmt.Sprintf("Page weight %d KB exceeds the %d KB budget (HTML %d KB + %d resources)",
+					weight/1024, budgetKB, result.Size/1024, len(result.Resources)),
+				Value:          fmt.Sprintf("%d KB", weight/1024),
+				Recommendation: "Compress and resize images, remove unused CSS and JavaScript, and lazy-load below-the-fold content",
+			})
+		}
+	}
+
+	if totalResources > 0 {
+		utils.Debug("Resource analysis complete",
+			utils.NewField("total_resources", totalResources),
+			utils.NewField("broken_resources", brokenResources),
+			utils.NewField("pages_over_budget", overBudget),
+			utils.NewField("budget_kb", budgetKB))
+	}
+
+	return issues
+}
+
+// renderBlockingIssues reports external scripts in <head> that block rendering
+func renderBlockingIssues(result *models.PageResult) []Issue {
+	var scripts []string
+	for _, resource := range result.Resources {
+		if resource.RenderBlocking {
+			scripts = append(scripts, resource.URL)
+		}
+	}
+	if len(scripts) == 0 {
+		return nil
+	}
+	return []Issue{{
+		Type:           IssueRenderBlockingScript,
+		Severity:       "warning",
+		URL:            result.URL,
+		Message:        fmt.Sprintf("%d render-blocking script(s) in <head>: %s", len(scripts), strings.Join(scripts, ", ")),
+		Value:          strings.Join(scripts, ", "),
+		Recommendation: "Add defer or async to scripts in <head>, or move them to the end of <body>",
+	}}
+}
+
+// hasCheckableResources reports whether a page was crawled successfully and loads subresources
+func hasCheckableResources(result *models.PageResult) bool {
+	return len(result.Resources) > 0 && result.StatusCode == 200 && result.Error == "" && !utils.IsImageURL(result.URL)
+}
+
+// isBrokenResource reports whether a checked resource failed to load
+func isBrokenResource(resource models.Resource) bool {
+	return resource.Error != "" || resource.StatusCode >= 400
+}
+
+// fetchResourcesInParallel checks the given URLs using a worker pool
+func fetchResourcesInParallel(urls map[string]bool, client *http.Client) map[string]ResourceCheck {
+	return fetchInParallel(urls, resourceAnalysisWorkers, func(url string) ResourceCheck {
+		return CheckResource(url, client)
+	})
+}
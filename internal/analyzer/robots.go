@@ -0,0 +1,67 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dillonlara115/barracudaseo/pkg/models"
+)
+
+// AnalyzeRobots reports robots.txt problems: hosts whose robots.txt could not be fetched,
+// syntax problems, and URLs that are disallowed even though crawled pages link to them
+func AnalyzeRobots(report *models.RobotsReport) []Issue {
+	if report == nil {
+		return nil
+	}
+
+	var issues []Issue
+	for _, host := range report.Hosts {
+		switch {
+		case host.Unavailable || host.StatusCode >= 500:
+			issues = append(issues, Issue{
+				Type:           IssueRobotsTxtUnreachable,
+				Severity:       "error",
+				URL:            host.URL,
+				Message:        fmt.Sprintf("robots.txt returned HTTP %d; the whole site is treated as disallowed (by Google and this crawl) while robots.txt returns server errors", host.StatusCode),
+				Value:          fmt.Sprintf("%d", host.StatusCode),
+				Recommendation: "Make robots.txt return 200 (or 404 if the site has no rules)",
+			})
+		case host.StatusCode == 0 && host.Error != "":
+			issues = append(issues, Issue{
+				Type:           IssueRobotsTxtUnreachable,
+				Severity:       "error",
+				URL:            host.URL,
+				Message:        fmt.Sprintf("robots.txt could not be fetched: %s", host.Error),
+				Value:          host.Error,
+				Recommendation: "Make sure robots.txt is reachable; Google treats an unreachable robots.txt as disallowing the whole site, while this crawl continued as if there were none",
+			})
+		}
+
+		if len(host.Warnings) > 0 {
+			issues = append(issues, Issue{
+				Type:           IssueRobotsTxtSyntax,
+				Severity:       "warning",
+				URL:            host.URL,
+				Message:        fmt.Sprintf("robots.txt has %d syntax problem(s): %s", len(host.Warnings), strings.Join(host.Warnings, "; ")),
+				Value:          strings.Join(host.Warnings, "; "),
+				Recommendation: "Fix the listed lines; crawlers ignore lines they cannot parse",
+			})
+		}
+	}
+
+	for _, blocked := range report.Blocked {
+		if len(blocked.LinkedFrom) == 0 {
+			continue
+		}
+		issues = append(issues, Issue{
+			Type:           IssueBlockedByRobots,
+			Severity:       "warning",
+			URL:            blocked.URL,
+			Message:        fmt.Sprintf("Disallowed by robots.txt (%s) but linked from %d crawled page(s)", blocked.Rule, len(blocked.LinkedFrom)),
+			Value:          blocked.Rule,
+			Recommendation: "Remove internal links to disallowed URLs, or allow them in robots.txt if they should be crawled",
+		})
+	}
+
+	return issues
+}
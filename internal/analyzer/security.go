@@ -0,0 +1,163 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dillonlara115/barracudaseo/pkg/models"
+)
+
+// certificateExpiryWarning is how long before expiry a certificate is reported
+const certificateExpiryWarning = 30 * 24 * time.Hour
+
+// SecuritySummary gives an overview of transport security across a crawl
+type SecuritySummary struct {
+	HTTPSPages        int            `json:"https_pages"`
+	HTTPPages         int            `json:"http_pages"`
+	MixedContentPages int            `json:"mixed_content_pages"`
+	MissingHeaders    map[string]int `json:"missing_headers,omitempty"` // Security header -> HTML pages without it
+	Hosts             []HostSecurity `json:"hosts,omitempty"`
+}
+
+// HostSecurity is the TLS certificate of a crawled host and how many pages it served
+type HostSecurity struct {
+	models.TLSInfo
+	DaysUntilExpiry int `json:"days_until_expiry"`
+	Pages           int `json:"pages"`
+}
+
+// mixedContentIssues reports http:// subresources on an HTTPS page. Scripts, stylesheets and
+// iframes are blocked by browsers, so they are errors; images still load but mark the page insecure.
+func mixedContentIssues(result *models.PageResult) []Issue {
+	if len(result.MixedContent) == 0 {
+		return nil
+	}
+
+	severity := "warning"
+	urls := make([]string, 0, len(result.MixedContent))
+	for _, resource := range result.MixedContent {
+		if resource.Tag != "img" {
+			severity = "error"
+		}
+		urls = append(urls, fmt.Sprintf("%s (%s)", resource.URL, resource.Tag))
+	}
+	return []Issue{{
+		Type:           IssueMixedContent,
+		Severity:       severity,
+		URL:            result.URL,
+		Message:        fmt.Sprintf("HTTPS page loads %d insecure resource(s): %s", len(urls), strings.Join(urls, ", ")),
+		Value:          strings.Join(urls, ", "),
+		Recommendation: "Load images, scripts, stylesheets and iframes over HTTPS",
+	}}
+}
+
+// tlsIssues reports certificate and protocol problems once per host
+func tlsIssues(results []*models.PageResult, now time.Time) []Issue {
+	var issues []Issue
+	for _, host := range securityHosts(results, now) {
+		hostURL := "https://" + host.Host + "/"
+		switch {
+		case host.Error != "":
+			issues = append(issues, Issue{
+				Type:           IssueCertificateInvalid,
+				Severity:       "error",
+				URL:            hostURL,
+				Message:        fmt.Sprintf("TLS certificate for %s was rejected: %s", host.Host, host.Error),
+				Value:          host.Error,
+				Recommendation: "Install a valid certificate from a trusted authority that covers the host",
+			})
+		case !host.CoversHost:
+			issues = append(issues, Issue{
+				Type:           IssueCertificateInvalid,
+				Severity:       "error",
+				URL:            hostURL,
+				Message:        fmt.Sprintf("TLS certificate does not cover %s (covers %s)", host.Host, strings.Join(host.DNSNames, ", ")),
+				Value:          strings.Join(host.DNSNames, ", "),
+				Recommendation: "Add the host to the certificate's subject alternative names",
+			})
+		case !host.NotAfter.IsZero() && host.NotAfter.Sub(now) < certificateExpiryWarning:
+			message := fmt.Sprintf("TLS certificate for %s expires in %d days (%s)", host.Host, host.DaysUntilExpiry, host.NotAfter.Format("2006-01-02"))
+			severity := "warning"
+			if !host.NotAfter.After(now) {
+				message = fmt.Sprintf("TLS certificate for %s expired on %s", host.Host, host.NotAfter.Format("2006-01-02"))
+				severity = "error"
+			}
+			issues = append(issues, Issue{
+				Type:           IssueCertificateExpiring,
+				Severity:       severity,
+				URL:            hostURL,
+				Message:        message,
+				Value:          host.NotAfter.Format("2006-01-02"),
+				Recommendation: "Renew the certificate (and automate renewal)",
+			})
+		}
+
+		if host.Version == "TLS 1.0" || host.Version == "TLS 1.1" || strings.HasPrefix(host.Version, "SSL") {
+			issues = append(issues, Issue{
+				Type:           IssueOldTLSVersion,
+				Severity:       "warning",
+				URL:            hostURL,
+				Message:        fmt.Sprintf("%s only supports %s", host.Host, host.Version),
+				Value:          host.Version,
+				Recommendation: "Enable TLS 1.2 and TLS 1.3; browsers no longer accept older versions",
+			})
+		}
+	}
+	return issues
+}
+
+// BuildSecuritySummary aggregates the transport security details of crawled pages: HTTPS coverage,
+// mixed content, missing security headers and the certificate of each host
+func BuildSecuritySummary(results []*models.PageResult, now time.Time) *SecuritySummary {
+	security := &SecuritySummary{
+		MissingHeaders: make(map[string]int),
+		Hosts:          securityHosts(results, now),
+	}
+	for _, result := range results {
+		if result.StatusCode == 0 && result.TLS == nil {
+			continue
+		}
+		if result.TLS != nil {
+			security.HTTPSPages++
+		} else {
+			security.HTTPPages++
+		}
+		if len(result.MixedContent) > 0 {
+			security.MixedContentPages++
+		}
+		if result.Headers != nil && result.StatusCode == 200 && strings.Contains(strings.ToLower(result.Headers.ContentType), "html") {
+			for _, header := range missingSecurityHeaders(result.URL, result.Headers) {
+				security.MissingHeaders[header]++
+			}
+		}
+	}
+	return security
+}
+
+// securityHosts collects the TLS details of each crawled HTTPS host, sorted by host
+func securityHosts(results []*models.PageResult, now time.Time) []HostSecurity {
+	byHost := make(map[string]*HostSecurity)
+	for _, result := range results {
+		if result.TLS == nil {
+			continue
+		}
+		host, ok := byHost[result.TLS.Host]
+		if !ok {
+			host = &HostSecurity{TLSInfo: *result.TLS}
+			if !result.TLS.NotAfter.IsZero() {
+				host.DaysUntilExpiry = int(result.TLS.NotAfter.Sub(now).Hours() / 24)
+			}
+			byHost[result.TLS.Host] = host
+		}
+		host.Pages++
+	}
+
+	hosts := make([]HostSecurity, 0, len(byHost))
+	for _, host := range byHost {
+		hosts = append(hosts, *host)
+	}
+	sort.Slice(hosts, func(i, j int) bool { return hosts[i].Host < hosts[j].Host })
+	return hosts
+}
@@ -0,0 +1,173 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dillonlara115/barracudaseo/internal/graph"
+	"github.com/dillonlara115/barracudaseo/internal/utils"
+	"github.com/dillonlara115/barracudaseo/pkg/models"
+)
+
+// AnalyzeSitemap cross-checks sitemap URLs with the crawl: sitemap URLs that are broken, redirected,
+// noindex, canonicalised elsewhere, blocked by robots.txt or without internal links, and indexable
+// pages missing from the sitemap. It does nothing unless the crawl parsed a sitemap.
+// linkGraph and robots may be nil, which skips the checks that need them.
+func AnalyzeSitemap(results []*models.PageResult, linkGraph *graph.Graph, robots *models.RobotsReport) []Issue {
+	sitemapPages := 0
+	for _, result := range results {
+		if result.Sitemap != nil {
+			sitemapPages++
+		}
+	}
+
+	var issues []Issue
+	if robots != nil {
+		for _, blocked := range robots.Blocked {
+			if blocked.Sitemap == "" {
+				continue
+			}
+			sitemapPages++
+			issues = append(issues, sitemapBlockedIssue(blocked.URL, blocked.Sitemap, blocked.Rule))
+		}
+	}
+	if sitemapPages == 0 {
+		return issues
+	}
+
+	var inlinks map[string]int
+	if linkGraph != nil {
+		inlinks = linkGraph.InlinkCounts()
+	}
+
+	for _, result := range results {
+		if utils.IsImageURL(result.URL) {
+			continue
+		}
+		if result.Sitemap == nil {
+			if IsIndexablePage(result) {
+				issues = append(issues, Issue{
+					Type:           IssueMissingFromSitemap,
+					Severity:       "info",
+					URL:            result.URL,
+					Message:        "Indexable page is not listed in the sitemap",
+					Recommendation: "Add the page to the sitemap so search engines can discover it",
+				})
+			}
+			continue
+		}
+
+		source := result.Sitemap.Source
+		if len(result.RedirectChain) > 0 {
+			target := result.RedirectChain[len(result.RedirectChain)-1]
+			issues = append(issues, Issue{
+				Type:           IssueSitemapRedirect,
+				Severity:       "warning",
+				URL:            result.URL,
+				Message:        fmt.Sprintf("Sitemap URL redirects to %s (listed in %s)", target, source),
+				Value:          target,
+				Recommendation: "List the final URL in the sitemap instead of the redirecting one",
+			})
+		}
+		if result.StatusCode != 200 {
+			status := fmt.Sprintf("HTTP %d", result.StatusCode)
+			if result.StatusCode == 0 {
+				status = "no response"
+			}
+			issues = append(issues, Issue{
+				Type:           IssueSitemapNon200,
+				Severity:       "error",
+				URL:            result.URL,
+				Message:        fmt.Sprintf("Sitemap URL returns %s (listed in %s)", status, source),
+				Value:          fmt.Sprintf("%d", result.StatusCode),
+				Recommendation: "Only list URLs that return 200 in the sitemap",
+			})
+			continue
+		}
+
+		switch result.IndexabilityStatus {
+		case models.IndexabilityNoindex:
+			issues = append(issues, Issue{
+				Type:           IssueSitemapNoindex,
+				Severity:       "warning",
+				URL:            result.URL,
+				Message:        fmt.Sprintf("Sitemap URL is noindex (listed in %s)", source),
+				Recommendation: "Remove noindex pages from the sitemap, or remove the noindex directive",
+			})
+		case models.IndexabilityBlocked:
+			issues = append(issues, sitemapBlockedIssue(result.URL, source, result.RobotsRule))
+		}
+
+		if canonical := canonicalElsewhere(result); canonical != "" {
+			issues = append(issues, Issue{
+				Type:           IssueSitemapCanonicalized,
+				Severity:       "warning",
+				URL:            result.URL,
+				Message:        fmt.Sprintf("Sitemap URL is canonicalised to %s (listed in %s)", canonical, source),
+				Value:          canonical,
+				Recommendation: "List the canonical URL in the sitemap instead",
+			})
+		}
+
+		if inlinks != nil && inlinks[result.URL] == 0 {
+			issues = append(issues, Issue{
+				Type:           IssueSitemapOrphan,
+				Severity:       "warning",
+				URL:            result.URL,
+				Message:        fmt.Sprintf("Sitemap URL has no internal links pointing to it (listed in %s)", source),
+				Recommendation: "Link to the page from related pages or navigation so it is not only discoverable through the sitemap",
+			})
+		}
+	}
+
+	return issues
+}
+
+// sitemapBlockedIssue reports a sitemap URL disallowed by robots.txt
+func sitemapBlockedIssue(pageURL, source, rule string) Issue {
+	message := fmt.Sprintf("Sitemap URL is blocked by robots.txt (listed in %s)", source)
+	if rule != "" {
+		message = fmt.Sprintf("Sitemap URL is blocked by robots.txt rule %s (listed in %s)", rule, source)
+	}
+	return Issue{
+		Type:           IssueSitemapBlocked,
+		Severity:       "warning",
+		URL:            pageURL,
+		Message:        message,
+		Value:          rule,
+		Recommendation: "Remove blocked URLs from the sitemap, or allow them in robots.txt",
+	}
+}
+
+// IsIndexablePage reports whether a crawled page is a 200, indexable, self-canonical HTML page
+// reached without redirects - the pages that belong in a sitemap
+func IsIndexablePage(result *models.PageResult) bool {
+	if result.StatusCode != 200 || result.Error != "" || len(result.RedirectChain) > 0 {
+		return false
+	}
+	if result.IndexabilityStatus != models.IndexabilityIndexable && result.IndexabilityStatus != "" {
+		return false
+	}
+	return canonicalElsewhere(result) == ""
+}
+
+// canonicalElsewhere returns the page's canonical URL (from the HTML, or else the Link header)
+// when it points to a different URL
+func canonicalElsewhere(result *models.PageResult) string {
+	declared := strings.TrimSpace(result.Canonical)
+	if declared == "" && result.Headers != nil {
+		declared = result.Headers.LinkCanonical
+	}
+	if declared == "" {
+		return ""
+	}
+	canonical, err := utils.NormalizeURL(declared)
+	if err != nil {
+		return ""
+	}
+	page, err := utils.NormalizeURL(result.URL)
+	if err != nil || canonical == page {
+		return ""
+	}
+	return canonical
+}
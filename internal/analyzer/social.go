@@ -0,0 +1,211 @@
+package analyzer
+
+import (
+	"fmt"
+	"image"
+	_ "image/gif" // Register decoders for image.DecodeConfig
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dillonlara115/barracudaseo/internal/utils"
+	"github.com/dillonlara115/barracudaseo/pkg/models"
+)
+
+const (
+	// MinSocialImageWidth and MinSocialImageHeight are the smallest og:image Facebook will show
+	MinSocialImageWidth  = 200
+	MinSocialImageHeight = 200
+
+	// maxImageHeaderBytes caps how much of an image is read to find its dimensions
+	maxImageHeaderBytes = 1024 * 1024
+)
+
+// socialIssues reports missing Open Graph tags and an og:url that disagrees with the canonical URL
+func socialIssues(result *models.PageResult) []Issue {
+	social := result.Social
+	if social == nil {
+		social = &models.SocialMeta{}
+	}
+
+	var issues []Issue
+	var missing []string
+	if social.OGTitle == "" {
+		missing = append(missing, "og:title")
+	}
+	if social.OGImage == "" {
+		missing = append(missing, "og:image")
+	}
+	if social.OGURL == "" {
+		missing = append(missing, "og:url")
+	}
+	if len(missing) > 0 {
+		issues = append(issues, Issue{
+			Type:           IssueMissingOpenGraph,
+			Severity:       "warning",
+			URL:            result.URL,
+			Message:        fmt.Sprintf("Missing Open Graph tags: %s", strings.Join(missing, ", ")),
+			Value:          strings.Join(missing, ", "),
+			Recommendation: "Add og:title, og:image and og:url so links to the page show a title and preview image when shared",
+		})
+	}
+
+	if social.OGURL != "" {
+		expected := canonicalElsewhere(result)
+		if expected == "" {
+			expected = result.URL
+		}
+		ogURL, err := utils.NormalizeURL(social.OGURL)
+		if err != nil {
+			ogURL = social.OGURL
+		}
+		if normalized, err := utils.NormalizeURL(expected); err == nil {
+			expected = normalized
+		}
+		if ogURL != expected {
+			issues = append(issues, Issue{
+				Type:           IssueOGURLMismatch,
+				Severity:       "warning",
+				URL:            result.URL,
+				Message:        fmt.Sprintf("og:url %s does not match the canonical URL %s", social.OGURL, expected),
+				Value:          social.OGURL,
+				Recommendation: "Set og:url to the canonical URL so shares of every variant are counted together",
+			})
+		}
+	}
+	return issues
+}
+
+// duplicateSocialTitleIssues reports indexable pages that share their social title with other pages
+func duplicateSocialTitleIssues(results []*models.PageResult) []Issue {
+	byTitle := make(map[string][]string)
+	for _, result := range results {
+		if !IsIndexablePage(result) || utils.IsImageURL(result.URL) {
+			continue
+		}
+		if title := strings.TrimSpace(result.Social.Title()); title != "" {
+			key := strings.ToLower(title)
+			byTitle[key] = append(byTitle[key], result.URL)
+		}
+	}
+
+	var issues []Issue
+	for _, result := range results {
+		if !IsIndexablePage(result) || utils.IsImageURL(result.URL) {
+			continue
+		}
+		title := strings.TrimSpace(result.Social.Title())
+		pages := byTitle[strings.ToLower(title)]
+		if title == "" || len(pages) < 2 {
+			continue
+		}
+		others := make([]string, 0, len(pages)-1)
+		for _, page := range pages {
+			if page != result.URL {
+				others = append(others, page)
+			}
+		}
+		sort.Strings(others)
+		issues = append(issues, Issue{
+			Type:           IssueDuplicateSocialTitle,
+			Severity:       "warning",
+			URL:            result.URL,
+			Message:        fmt.Sprintf("Social title %q is also used by %d other page(s): %s", title, len(others), strings.Join(others, ", ")),
+			Value:          title,
+			Recommendation: "Give each page a unique og:title that describes its content",
+		})
+	}
+	return issues
+}
+
+// AnalyzeSocialImages checks the og:image of each page, reporting images that are broken or
+// smaller than MinSocialImageWidth x MinSocialImageHeight. Broken images are found with the image
+// size checker; dimensions are read from the image header for JPEG, PNG and GIF.
+func AnalyzeSocialImages(results []*models.PageResult, timeout time.Duration) []Issue {
+	urls := make(map[string]bool)
+	for _, result := range results {
+		if hasSocialImage(result) {
+			urls[result.Social.OGImage] = true
+		}
+	}
+	if len(urls) == 0 {
+		return nil
+	}
+
+	sizes := fetchImageSizesInParallel(urls, timeout)
+	working := make(map[string]bool)
+	for url, info := range sizes {
+		if info.Error == nil {
+			working[url] = true
+		}
+	}
+	dimensions := fetchInParallel(working, imageAnalysisWorkers, func(url string) imageDimensions {
+		return checkImageDimensions(url, timeout)
+	})
+
+	var issues []Issue
+	for _, result := range results {
+		if !hasSocialImage(result) {
+			continue
+		}
+		imageURL := result.Social.OGImage
+		if info := sizes[imageURL]; info.Error != nil {
+			issues = append(issues, Issue{
+				Type:           IssueBrokenSocialImage,
+				Severity:       "error",
+				URL:            result.URL,
+				Message:        fmt.Sprintf("og:image is broken (%s): %s", info.Error, imageURL),
+				Value:          imageURL,
+				Recommendation: "Point og:image at a working, publicly accessible image",
+			})
+			continue
+		}
+		size := dimensions[imageURL]
+		if size.Width > 0 && (size.Width < MinSocialImageWidth || size.Height < MinSocialImageHeight) {
+			issues = append(issues, Issue{
+				Type:           IssueSmallSocialImage,
+				Severity:       "warning",
+				URL:            result.URL,
+				Message:        fmt.Sprintf("og:image is %dx%d, smaller than the %dx%d minimum: %s", size.Width, size.Height, MinSocialImageWidth, MinSocialImageHeight, imageURL),
+				Value:          fmt.Sprintf("%dx%d", size.Width, size.Height),
+				Recommendation: "Use an og:image of at least 1200x630 pixels for large link previews",
+			})
+		}
+	}
+	return issues
+}
+
+// hasSocialImage reports whether a successfully crawled page declares an og:image
+func hasSocialImage(result *models.PageResult) bool {
+	return result.Social != nil && result.Social.OGImage != "" && result.StatusCode == 200 && result.Error == "" &&
+		!utils.IsImageURL(result.URL)
+}
+
+// imageDimensions is the pixel size of an image; zero when it could not be decoded
+type imageDimensions struct {
+	Width  int
+	Height int
+}
+
+// checkImageDimensions reads the start of an image and decodes its dimensions
+func checkImageDimensions(imageURL string, timeout time.Duration) imageDimensions {
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Get(imageURL)
+	if err != nil {
+		return imageDimensions{}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return imageDimensions{}
+	}
+
+	config, _, err := image.DecodeConfig(io.LimitReader(resp.Body, maxImageHeaderBytes))
+	if err != nil {
+		return imageDimensions{}
+	}
+	return imageDimensions{Width: config.Width, Height: config.Height}
+}
@@ -0,0 +1,214 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dillonlara115/barracudaseo/internal/utils"
+	"github.com/dillonlara115/barracudaseo/pkg/models"
+)
+
+// schemaRule lists the properties search engines need for a schema.org type to be eligible for
+// rich results. Each Required group is satisfied by any one of its properties.
+type schemaRule struct {
+	Required    [][]string
+	Recommended []string
+}
+
+// schemaRules are the validated schema.org types, based on Google's rich result documentation
+var schemaRules = map[string]schemaRule{
+	"Article": {
+		Required:    [][]string{{"headline"}},
+		Recommended: []string{"author", "datePublished", "dateModified", "image"},
+	},
+	"Product": {
+		Required:    [][]string{{"name"}, {"offers", "review", "aggregateRating"}},
+		Recommended: []string{"image", "description", "brand", "sku"},
+	},
+	"BreadcrumbList": {
+		Required: [][]string{{"itemListElement"}},
+	},
+	"FAQPage": {
+		Required: [][]string{{"mainEntity"}},
+	},
+	"Organization": {
+		Required:    [][]string{{"name"}},
+		Recommended: []string{"url", "logo", "sameAs"},
+	},
+	"LocalBusiness": {
+		Required:    [][]string{{"name"}, {"address"}},
+		Recommended: []string{"telephone", "openingHoursSpecification", "geo", "url", "image", "priceRange"},
+	},
+}
+
+// schemaSubtypes maps common subtypes to the validated type whose rules they follow
+var schemaSubtypes = map[string]string{
+	"NewsArticle":         "Article",
+	"BlogPosting":         "Article",
+	"Corporation":         "Organization",
+	"Restaurant":          "LocalBusiness",
+	"Store":               "LocalBusiness",
+	"ProfessionalService": "LocalBusiness",
+}
+
+// StructuredDataSummary gives an overview of the structured data across a crawl
+type StructuredDataSummary struct {
+	PagesWithStructuredData int                  `json:"pages_with_structured_data"`
+	InvalidJSONBlocks       int                  `json:"invalid_json_blocks"`
+	Types                   []StructuredDataType `json:"types,omitempty"`
+}
+
+// StructuredDataType counts the items of one schema.org type and how many passed validation
+type StructuredDataType struct {
+	Type         string   `json:"type"`
+	Formats      []string `json:"formats"` // json-ld, microdata, rdfa
+	Pages        int      `json:"pages"`
+	Items        int      `json:"items"`
+	WithErrors   int      `json:"with_errors"`   // Items missing required properties
+	WithWarnings int      `json:"with_warnings"` // Items missing only recommended properties
+}
+
+// structuredDataIssues validates a page's structured data: JSON-LD syntax, and required and
+// recommended properties of the schema.org types in schemaRules
+func structuredDataIssues(result *models.PageResult) []Issue {
+	var issues []Issue
+	for _, item := range result.StructuredData {
+		if item.Error != "" {
+			issues = append(issues, Issue{
+				Type:           IssueStructuredDataInvalid,
+				Severity:       "error",
+				URL:            result.URL,
+				Message:        fmt.Sprintf("Invalid JSON-LD: %s", item.Error),
+				Value:          item.Error,
+				Recommendation: "Fix the JSON syntax so search engines can read the structured data",
+			})
+			continue
+		}
+
+		for _, itemType := range item.Types {
+			missingRequired, missingRecommended := validateSchemaType(itemType, item.Properties)
+			if len(missingRequired) > 0 {
+				issues = append(issues, Issue{
+					Type:           IssueStructuredDataMissingRequired,
+					Severity:       "error",
+					URL:            result.URL,
+					Message:        fmt.Sprintf("%s (%s) is missing required properties: %s", itemType, item.Format, strings.Join(missingRequired, ", ")),
+					Value:          itemType,
+					Recommendation: fmt.Sprintf("Add %s so the %s is eligible for rich results", strings.Join(missingRequired, ", "), itemType),
+				})
+			}
+			if len(missingRecommended) > 0 {
+				issues = append(issues, Issue{
+					Type:           IssueStructuredDataMissingRecommended,
+					Severity:       "info",
+					URL:            result.URL,
+					Message:        fmt.Sprintf("%s (%s) is missing recommended properties: %s", itemType, item.Format, strings.Join(missingRecommended, ", ")),
+					Value:          itemType,
+					Recommendation: fmt.Sprintf("Add %s to improve how the %s appears in search results", strings.Join(missingRecommended, ", "), itemType),
+				})
+			}
+		}
+	}
+	return issues
+}
+
+// validateSchemaType returns the required property groups (joined with "or") and recommended
+// properties an item of the given type lacks. Types without rules always pass.
+func validateSchemaType(itemType string, properties []string) (missingRequired, missingRecommended []string) {
+	if parent, ok := schemaSubtypes[itemType]; ok {
+		itemType = parent
+	}
+	rule, ok := schemaRules[itemType]
+	if !ok {
+		return nil, nil
+	}
+
+	has := make(map[string]bool, len(properties))
+	for _, property := range properties {
+		has[property] = true
+	}
+	for _, group := range rule.Required {
+		found := false
+		for _, property := range group {
+			if has[property] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missingRequired = append(missingRequired, strings.Join(group, " or "))
+		}
+	}
+	for _, property := range rule.Recommended {
+		if !has[property] {
+			missingRecommended = append(missingRecommended, property)
+		}
+	}
+	return missingRequired, missingRecommended
+}
+
+// BuildStructuredDataSummary counts the structured data of crawled pages per schema.org type,
+// with how many items fail or only partly pass validation
+func BuildStructuredDataSummary(results []*models.PageResult) *StructuredDataSummary {
+	summary := &StructuredDataSummary{}
+	byType := make(map[string]*StructuredDataType)
+	for _, result := range results {
+		if len(result.StructuredData) == 0 || utils.IsImageURL(result.URL) {
+			continue
+		}
+		summary.PagesWithStructuredData++
+
+		seenOnPage := make(map[string]bool)
+		for _, item := range result.StructuredData {
+			if item.Error != "" {
+				summary.InvalidJSONBlocks++
+				continue
+			}
+			for _, itemType := range item.Types {
+				entry, ok := byType[itemType]
+				if !ok {
+					entry = &StructuredDataType{Type: itemType}
+					byType[itemType] = entry
+				}
+				entry.Items++
+				if !seenOnPage[itemType] {
+					seenOnPage[itemType] = true
+					entry.Pages++
+				}
+				if !containsString(entry.Formats, item.Format) {
+					entry.Formats = append(entry.Formats, item.Format)
+				}
+
+				missingRequired, missingRecommended := validateSchemaType(itemType, item.Properties)
+				if len(missingRequired) > 0 {
+					entry.WithErrors++
+				} else if len(missingRecommended) > 0 {
+					entry.WithWarnings++
+				}
+			}
+		}
+	}
+
+	for _, entry := range byType {
+		sort.Strings(entry.Formats)
+		summary.Types = append(summary.Types, *entry)
+	}
+	sort.Slice(summary.Types, func(i, j int) bool {
+		if summary.Types[i].Items != summary.Types[j].Items {
+			return summary.Types[i].Items > summary.Types[j].Items
+		}
+		return summary.Types[i].Type < summary.Types[j].Type
+	})
+	return summary
+}
+
+// containsString reports whether values contains value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,177 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+
+	"github.com/dillonlara115/barracudaseo/internal/utils"
+	"go.uber.org/zap"
+)
+
+// crawlAuthSettingsKey is the project settings key holding crawl credentials
+const crawlAuthSettingsKey = "crawl_auth"
+
+// handleProjectCrawlAuth handles /api/v1/projects/:id/crawl-auth.
+// GET returns the configured credentials with secrets masked, PUT replaces them and DELETE removes them.
+func (s *Server) handleProjectCrawlAuth(w http.ResponseWriter, r *http.Request, projectID, userID string) {
+	hasAccess, err := s.verifyProjectAccess(userID, projectID)
+	if err != nil {
+		s.logger.Error("Failed to verify project access", zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to verify project access")
+		return
+	}
+	if !hasAccess {
+		s.respondError(w, http.StatusForbidden, "You don't have access to this project")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		auth, err := s.getProjectCrawlAuth(projectID)
+		if err != nil {
+			s.logger.Error("Failed to load crawl credentials", zap.Error(err))
+			s.respondError(w, http.StatusInternalServerError, "Failed to load crawl credentials")
+			return
+		}
+		s.respondJSON(w, http.StatusOK, maskCrawlAuth(auth))
+
+	case http.MethodPut, http.MethodPost:
+		var auth utils.AuthConfig
+		if err := json.NewDecoder(r.Body).Decode(&auth); err != nil {
+			s.respondError(w, http.StatusBadRequest, fmt.Sprintf("Invalid request body: %v", err))
+			return
+		}
+
+		// Server-side file paths are never accepted from clients; send cookie contents instead
+		auth.CookieFile = ""
+
+		if auth.LoginURL != "" {
+			if u, err := url.Parse(auth.LoginURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+				s.respondError(w, http.StatusBadRequest, "login_url must be an http(s) URL")
+				return
+			}
+		}
+		if len(auth.LoginFields) > 0 && auth.LoginURL == "" {
+			s.respondError(w, http.StatusBadRequest, "login_fields require login_url")
+			return
+		}
+
+		var value interface{} = auth
+		if auth.IsZero() {
+			value = nil
+		}
+		if err := s.updateProjectSettings(projectID, map[string]interface{}{crawlAuthSettingsKey: value}); err != nil {
+			s.logger.Error("Failed to save crawl credentials", zap.Error(err))
+			s.respondError(w, http.StatusInternalServerError, "Failed to save crawl credentials")
+			return
+		}
+
+		s.logger.Info("Saved crawl credentials", zap.String("project_id", projectID), zap.String("user_id", userID))
+		if auth.IsZero() {
+			s.respondJSON(w, http.StatusOK, maskCrawlAuth(nil))
+			return
+		}
+		s.respondJSON(w, http.StatusOK, maskCrawlAuth(&auth))
+
+	case http.MethodDelete:
+		if err := s.updateProjectSettings(projectID, map[string]interface{}{crawlAuthSettingsKey: nil}); err != nil {
+			s.logger.Error("Failed to delete crawl credentials", zap.Error(err))
+			s.respondError(w, http.StatusInternalServerError, "Failed to delete crawl credentials")
+			return
+		}
+		s.respondJSON(w, http.StatusOK, maskCrawlAuth(nil))
+
+	default:
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// getProjectCrawlAuth loads crawl credentials from project settings. It returns nil if none are stored.
+func (s *Server) getProjectCrawlAuth(projectID string) (*utils.AuthConfig, error) {
+	settings, err := s.loadProjectSettings(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, ok := settings[crawlAuthSettingsKey]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode crawl credentials: %w", err)
+	}
+	var auth utils.AuthConfig
+	if err := json.Unmarshal(data, &auth); err != nil {
+		return nil, fmt.Errorf("failed to parse crawl credentials: %w", err)
+	}
+	if auth.IsZero() {
+		return nil, nil
+	}
+	return &auth, nil
+}
+
+// redactCrawlAuth replaces stored crawl credentials in a project row with their masked description
+func redactCrawlAuth(project map[string]interface{}) {
+	settings, ok := project["settings"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	raw, ok := settings[crawlAuthSettingsKey]
+	if !ok || raw == nil {
+		return
+	}
+
+	var auth utils.AuthConfig
+	if data, err := json.Marshal(raw); err == nil && json.Unmarshal(data, &auth) == nil {
+		settings[crawlAuthSettingsKey] = maskCrawlAuth(&auth)
+		return
+	}
+	delete(settings, crawlAuthSettingsKey)
+}
+
+// preserveCrawlAuth keeps stored crawl credentials when a project update replaces its settings.
+// Credentials can only be changed through the crawl-auth endpoint.
+func (s *Server) preserveCrawlAuth(projectID string, settings map[string]interface{}) error {
+	current, err := s.loadProjectSettings(projectID)
+	if err != nil {
+		return err
+	}
+	if stored, ok := current[crawlAuthSettingsKey]; ok && stored != nil {
+		settings[crawlAuthSettingsKey] = stored
+	} else {
+		delete(settings, crawlAuthSettingsKey)
+	}
+	return nil
+}
+
+// maskCrawlAuth describes stored credentials without revealing secret values
+func maskCrawlAuth(auth *utils.AuthConfig) map[string]interface{} {
+	if auth == nil {
+		return map[string]interface{}{"configured": false}
+	}
+
+	return map[string]interface{}{
+		"configured":         true,
+		"headers":            sortedKeys(auth.Headers),
+		"has_cookies":        auth.Cookies != "",
+		"basic_username":     auth.BasicUsername,
+		"has_basic_password": auth.BasicPassword != "",
+		"login_url":          auth.LoginURL,
+		"login_fields":       sortedKeys(auth.LoginFields),
+	}
+}
+
+// sortedKeys returns the keys of a map in sorted order
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,171 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/dillonlara115/barracudaseo/internal/crawler"
+	"github.com/dillonlara115/barracudaseo/pkg/models"
+	"go.uber.org/zap"
+)
+
+// baselinePageRow is a stored page with the fields needed to reuse it in an incremental crawl
+type baselinePageRow struct {
+	URL                string  `json:"url"`
+	StatusCode         int     `json:"status_code"`
+	ResponseTime       int64   `json:"response_time_ms"`
+	Title              string  `json:"title"`
+	MetaDesc           string  `json:"meta_description"`
+	Canonical          string  `json:"canonical_url"`
+	H1                 string  `json:"h1"`
+	IndexabilityStatus string  `json:"indexability_status"`
+	WordCount          int     `json:"word_count"`
+	TextRatio          float64 `json:"text_ratio"`
+	TextHash           string  `json:"text_hash"`
+	SimHash            string  `json:"simhash"`
+	ContentHash        string  `json:"content_hash"`
+	ETag               string  `json:"etag"`
+	LastModified       string  `json:"last_modified"`
+	Data               struct {
+		H1             []string                `json:"h1"`
+		H2             []string                `json:"h2"`
+		H3             []string                `json:"h3"`
+		H4             []string                `json:"h4"`
+		H5             []string                `json:"h5"`
+		H6             []string                `json:"h6"`
+		InternalLinks  []string                `json:"internal_links"`
+		ExternalLinks  []string                `json:"external_links"`
+		Links          []models.Link           `json:"links"`
+		Images         []models.Image          `json:"images"`
+		RenderMode     string                  `json:"render_mode"`
+		Raw            *models.RawSnapshot     `json:"raw"`
+		MetaRobots     string                  `json:"meta_robots"`
+		XRobotsTag     string                  `json:"x_robots_tag"`
+		RedirectChain  []string                `json:"redirect_chain"`
+		RedirectHops   []models.RedirectHop    `json:"redirect_hops"`
+		Sitemap        *models.SitemapEntry    `json:"sitemap"`
+		Headers        *models.ResponseHeaders `json:"headers"`
+		MixedContent   []models.MixedContent   `json:"mixed_content"`
+		Resources      []models.Resource       `json:"resources"`
+		Size           int64                   `json:"size"`
+		Timing         *models.Timing          `json:"timing"`
+		StructuredData []models.StructuredData `json:"structured_data"`
+		Hreflang       []models.Hreflang       `json:"hreflang"`
+		HTMLLang       string                  `json:"html_lang"`
+		Social         *models.SocialMeta      `json:"social"`
+	} `json:"data"`
+}
+
+// pageResult converts the stored row back into a crawl result
+func (row baselinePageRow) pageResult() *models.PageResult {
+	h1 := row.Data.H1
+	if h1 == nil && row.H1 != "" {
+		// Rows stored before the H1 list was kept in data only have the joined column
+		h1 = strings.Split(row.H1, ", ")
+	}
+
+	return &models.PageResult{
+		URL:                row.URL,
+		StatusCode:         row.StatusCode,
+		ResponseTime:       row.ResponseTime,
+		Title:              row.Title,
+		MetaDesc:           row.MetaDesc,
+		Canonical:          row.Canonical,
+		H1:                 h1,
+		H2:                 row.Data.H2,
+		H3:                 row.Data.H3,
+		H4:                 row.Data.H4,
+		H5:                 row.Data.H5,
+		H6:                 row.Data.H6,
+		InternalLinks:      row.Data.InternalLinks,
+		ExternalLinks:      row.Data.ExternalLinks,
+		Links:              row.Data.Links,
+		Images:             row.Data.Images,
+		RedirectChain:      row.Data.RedirectChain,
+		RedirectHops:       row.Data.RedirectHops,
+		XRobotsTag:         row.Data.XRobotsTag,
+		MetaRobots:         row.Data.MetaRobots,
+		IndexabilityStatus: models.IndexabilityStatus(row.IndexabilityStatus),
+		RenderMode:         row.Data.RenderMode,
+		Raw:                row.Data.Raw,
+		ETag:               row.ETag,
+		LastModified:       row.LastModified,
+		ContentHash:        row.ContentHash,
+		WordCount:          row.WordCount,
+		TextRatio:          row.TextRatio,
+		TextHash:           row.TextHash,
+		SimHash:            row.SimHash,
+		Sitemap:            row.Data.Sitemap,
+		Headers:            row.Data.Headers,
+		MixedContent:       row.Data.MixedContent,
+		Resources:          row.Data.Resources,
+		Size:               row.Data.Size,
+		Timing:             row.Data.Timing,
+		StructuredData:     row.Data.StructuredData,
+		Hreflang:           row.Data.Hreflang,
+		HTMLLang:           row.Data.HTMLLang,
+		Social:             row.Data.Social,
+	}
+}
+
+// loadCrawlBaseline loads the successfully fetched pages of the project's latest completed crawl,
+// so an incremental crawl can send conditional requests and reuse unchanged pages.
+// It returns nil when the project has no completed crawl.
+func (s *Server) loadCrawlBaseline(projectID, crawlID string) (*crawler.Baseline, error) {
+	data, _, err := s.serviceRole.From("crawls").
+		Select("id", "", false).
+		Eq("project_id", projectID).
+		Eq("status", "succeeded").
+		Neq("id", crawlID).
+		Order("started_at", nil).
+		Limit(1, "").
+		Execute()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find previous crawl: %w", err)
+	}
+
+	var crawls []struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(data, &crawls); err != nil {
+		return nil, fmt.Errorf("failed to parse previous crawl: %w", err)
+	}
+	if len(crawls) == 0 {
+		return nil, nil
+	}
+	previousID := crawls[0].ID
+
+	const pageChunkSize = 1000
+	var pages []*models.PageResult
+	for offset := 0; ; offset += pageChunkSize {
+		data, _, err := s.serviceRole.From("pages").
+			Select("url,status_code,response_time_ms,title,meta_description,canonical_url,h1,indexability_status,word_count,text_ratio,text_hash,simhash,content_hash,etag,last_modified,data", "", false).
+			Eq("crawl_id", previousID).
+			Eq("status_code", "200").
+			Order("id", nil).
+			Range(offset, offset+pageChunkSize-1, "").
+			Execute()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load previous pages: %w", err)
+		}
+
+		var rows []baselinePageRow
+		if err := json.Unmarshal(data, &rows); err != nil {
+			return nil, fmt.Errorf("failed to parse previous pages: %w", err)
+		}
+		for _, row := range rows {
+			pages = append(pages, row.pageResult())
+		}
+		if len(rows) < pageChunkSize {
+			break
+		}
+	}
+
+	s.logger.Info("Loaded incremental crawl baseline",
+		zap.String("crawl_id", crawlID),
+		zap.String("previous_crawl_id", previousID),
+		zap.Int("pages", len(pages)))
+
+	return crawler.NewBaseline(pages), nil
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/dillonlara115/barracudaseo/internal/analyzer"
+	"github.com/dillonlara115/barracudaseo/pkg/models"
+	"go.uber.org/zap"
+)
+
+// handleCrawlDuplicates handles GET /api/v1/crawls/:id/duplicates - clusters the crawl's pages by
+// their stored content fingerprints. The similarity query parameter (0-1, default 0.9) sets the
+// share of matching SimHash bits at which pages count as near duplicates.
+func (s *Server) handleCrawlDuplicates(w http.ResponseWriter, r *http.Request, crawlID string, userID string) {
+	// Verify user has access to this crawl (via project membership)
+	hasAccess, err := s.verifyCrawlAccess(userID, crawlID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			s.respondError(w, http.StatusNotFound, "Crawl not found")
+		} else {
+			s.logger.Error("Failed to verify crawl access", zap.String("crawl_id", crawlID), zap.String("user_id", userID), zap.Error(err))
+			s.respondError(w, http.StatusInternalServerError, "Failed to verify crawl access")
+		}
+		return
+	}
+	if !hasAccess {
+		s.respondError(w, http.StatusForbidden, "You don't have access to this crawl")
+		return
+	}
+
+	similarity := analyzer.DefaultDuplicateSimilarity
+	if value := r.URL.Query().Get("similarity"); value != "" {
+		if similarity, err = strconv.ParseFloat(value, 64); err != nil || similarity <= 0 || similarity > 1 {
+			s.respondError(w, http.StatusBadRequest, "similarity must be a number between 0 and 1")
+			return
+		}
+	}
+
+	// Fetch pages using service role — paginate to exceed PostgREST 1000-row default
+	var pages []*models.PageResult
+	const chunkSize = 1000
+	for offset := 0; ; offset += chunkSize {
+		data, _, err := s.serviceRole.From("pages").
+			Select("url,status_code,canonical_url,indexability_status,word_count,text_hash,simhash,data", "", false).
+			Eq("crawl_id", crawlID).
+			Eq("status_code", "200").
+			Order("id", nil).
+			Range(offset, offset+chunkSize-1, "").
+			Execute()
+		if err != nil {
+			s.logger.Error("Failed to fetch pages", zap.String("crawl_id", crawlID), zap.Int("offset", offset), zap.Error(err))
+			s.respondError(w, http.StatusInternalServerError, "Failed to fetch pages")
+			return
+		}
+		var rows []baselinePageRow
+		if err := json.Unmarshal(data, &rows); err != nil {
+			s.logger.Error("Failed to parse pages data", zap.String("crawl_id", crawlID), zap.Error(err))
+			s.respondError(w, http.StatusInternalServerError, "Failed to parse pages")
+			return
+		}
+		for _, row := range rows {
+			pages = append(pages, row.pageResult())
+		}
+		if len(rows) < chunkSize {
+			break
+		}
+	}
+
+	s.respondJSON(w, http.StatusOK, analyzer.FindDuplicateContent(pages, similarity))
+}
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/dillonlara115/barracudaseo/pkg/models"
+	"go.uber.org/zap"
+)
+
+// handleCrawlGraphEdges handles GET /api/v1/crawls/:id/graph?format=edges - returns every link of
+// the crawl with its source page, anchor text, rel, target and position. Pages stored before link
+// details were kept only have their target URLs.
+func (s *Server) handleCrawlGraphEdges(w http.ResponseWriter, crawlID string) {
+	// Fetch pages using service role — paginate to exceed PostgREST 1000-row default
+	links := make([]models.Link, 0)
+	const chunkSize = 1000
+	for offset := 0; ; offset += chunkSize {
+		data, _, err := s.serviceRole.From("pages").
+			Select("url,data", "", false).
+			Eq("crawl_id", crawlID).
+			Order("id", nil).
+			Range(offset, offset+chunkSize-1, "").
+			Execute()
+		if err != nil {
+			s.logger.Error("Failed to fetch pages for graph", zap.String("crawl_id", crawlID), zap.Int("offset", offset), zap.Error(err))
+			s.respondError(w, http.StatusInternalServerError, "Failed to fetch pages")
+			return
+		}
+		var rows []baselinePageRow
+		if err := json.Unmarshal(data, &rows); err != nil {
+			s.logger.Error("Failed to parse pages data", zap.String("crawl_id", crawlID), zap.Error(err))
+			s.respondError(w, http.StatusInternalServerError, "Failed to parse pages")
+			return
+		}
+		for _, row := range rows {
+			links = append(links, pageLinks(row.URL, row.Data.Links, row.Data.InternalLinks, row.Data.ExternalLinks)...)
+		}
+		if len(rows) < chunkSize {
+			break
+		}
+	}
+
+	s.logger.Info("Built link edges", zap.String("crawl_id", crawlID), zap.Int("links", len(links)))
+	s.respondJSON(w, http.StatusOK, links)
+}
+
+// pageLinks returns a page's links with their source set, falling back to the internal and
+// external URL lists when the page has no link details
+func pageLinks(source string, links []models.Link, internal, external []string) []models.Link {
+	links = models.LinksOrTargets(links, internal, external)
+	edges := make([]models.Link, len(links))
+	for i, link := range links {
+		link.Source = source
+		edges[i] = link
+	}
+	return edges
+}
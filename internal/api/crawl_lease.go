@@ -0,0 +1,93 @@
+package api
+
+import (
+	"encoding/json"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// crawlLeaseDuration is how long a running crawl stays owned by its instance without renewal
+	crawlLeaseDuration = 2 * time.Minute
+	// crawlLeaseRenewInterval is how often the owning instance renews the lease
+	crawlLeaseRenewInterval = 30 * time.Second
+)
+
+// crawlLease returns the lease columns claiming a crawl for this instance
+func (s *Server) crawlLease() map[string]interface{} {
+	return map[string]interface{}{
+		"lease_owner":      s.instanceID,
+		"lease_expires_at": time.Now().Add(crawlLeaseDuration).UTC().Format(time.RFC3339),
+	}
+}
+
+// expiredLeaseFilter matches running crawls no live instance owns
+func expiredLeaseFilter() string {
+	return "lease_expires_at.is.null,lease_expires_at.lt." + time.Now().UTC().Format(time.RFC3339)
+}
+
+// claimCrawl takes over a running crawl whose lease has expired. It reports false when
+// another instance owns the crawl or claimed it first.
+func (s *Server) claimCrawl(crawlID string) (bool, error) {
+	data, _, err := s.serviceRole.From("crawls").
+		Update(s.crawlLease(), "representation", "").
+		Eq("id", crawlID).
+		Eq("status", "running").
+		Or(expiredLeaseFilter(), "").
+		Execute()
+	if err != nil {
+		return false, err
+	}
+	var rows []struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(data, &rows); err != nil {
+		return false, err
+	}
+	return len(rows) > 0, nil
+}
+
+// holdCrawlLease renews this instance's lease on a crawl until the returned function is called
+func (s *Server) holdCrawlLease(crawlID string) func() {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(crawlLeaseRenewInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+			data, _, err := s.serviceRole.From("crawls").
+				Update(s.crawlLease(), "representation", "").
+				Eq("id", crawlID).
+				Eq("lease_owner", s.instanceID).
+				Execute()
+			if err != nil {
+				s.logger.Warn("Failed to renew crawl lease", zap.String("crawl_id", crawlID), zap.Error(err))
+				continue
+			}
+			var rows []struct {
+				ID string `json:"id"`
+			}
+			if err := json.Unmarshal(data, &rows); err == nil && len(rows) == 0 {
+				s.logger.Error("Crawl lease taken over by another instance",
+					zap.String("crawl_id", crawlID),
+					zap.String("instance_id", s.instanceID))
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+// releaseCrawlLease lets another instance take over a crawl this instance stopped running
+func (s *Server) releaseCrawlLease(crawlID string) {
+	update := map[string]interface{}{
+		"lease_expires_at": time.Now().UTC().Format(time.RFC3339),
+	}
+	if _, _, err := s.serviceRole.From("crawls").Update(update, "", "").Eq("id", crawlID).Eq("lease_owner", s.instanceID).Execute(); err != nil {
+		s.logger.Warn("Failed to release crawl lease", zap.String("crawl_id", crawlID), zap.Error(err))
+	}
+}
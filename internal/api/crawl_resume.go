@@ -0,0 +1,168 @@
+package api
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dillonlara115/barracudaseo/internal/crawler"
+	"github.com/dillonlara115/barracudaseo/internal/utils"
+	"go.uber.org/zap"
+)
+
+// crawlStateDirFor returns the checkpoint directory for a web-triggered crawl
+func (s *Server) crawlStateDirFor(crawlID string) string {
+	baseDir := s.config.CrawlStateDir
+	if baseDir == "" {
+		baseDir = filepath.Join(os.TempDir(), "barracuda-crawls")
+	}
+	return filepath.Join(baseDir, crawlID)
+}
+
+// WatchInterruptedCrawls resumes interrupted crawls now and then every lease period,
+// so crawls of instances that stopped without shutting down cleanly are picked up too.
+func (s *Server) WatchInterruptedCrawls() {
+	for {
+		s.ResumeInterruptedCrawls()
+		time.Sleep(crawlLeaseDuration)
+	}
+}
+
+// ResumeInterruptedCrawls restarts web crawls left in "running" by an instance that no longer
+// renews their lease. Crawls with a checkpoint on disk continue where they stopped; the rest
+// are marked failed. Crawls another live instance is running are left alone.
+func (s *Server) ResumeInterruptedCrawls() {
+	data, _, err := s.serviceRole.From("crawls").
+		Select("id,project_id", "", false).
+		Eq("status", "running").
+		Eq("source", "web").
+		Or(expiredLeaseFilter(), "").
+		Execute()
+	if err != nil {
+		s.logger.Error("Failed to query running crawls", zap.Error(err))
+		return
+	}
+
+	var crawls []struct {
+		ID        string `json:"id"`
+		ProjectID string `json:"project_id"`
+	}
+	if err := json.Unmarshal(data, &crawls); err != nil {
+		s.logger.Error("Failed to parse running crawls", zap.Error(err))
+		return
+	}
+
+	for _, crawl := range crawls {
+		claimed, err := s.claimCrawl(crawl.ID)
+		if err != nil {
+			s.logger.Error("Failed to claim interrupted crawl", zap.String("crawl_id", crawl.ID), zap.Error(err))
+			continue
+		}
+		if !claimed {
+			continue // Another instance took it over first
+		}
+
+		stateDir := s.crawlStateDirFor(crawl.ID)
+		checkpoint, err := crawler.LoadCheckpoint(stateDir)
+		if err != nil {
+			s.logger.Warn("No checkpoint for interrupted crawl, marking failed",
+				zap.String("crawl_id", crawl.ID),
+				zap.Error(err))
+			s.updateCrawlStatus(crawl.ID, "failed", "Crawl interrupted by server restart")
+			continue
+		}
+
+		// Checkpoints do not store credentials; reload them from the project settings
+		crawlAuth, err := s.getProjectCrawlAuth(crawl.ProjectID)
+		if err != nil {
+			s.logger.Error("Failed to load crawl credentials for interrupted crawl, marking failed",
+				zap.String("crawl_id", crawl.ID),
+				zap.Error(err))
+			s.updateCrawlStatus(crawl.ID, "failed", "Crawl interrupted by server restart")
+			continue
+		}
+
+		config := checkpoint.Config
+		config.StateDir = stateDir
+		if crawlAuth != nil {
+			config.Auth = *crawlAuth
+		}
+
+		s.logger.Info("Resuming interrupted crawl",
+			zap.String("crawl_id", crawl.ID),
+			zap.String("project_id", crawl.ProjectID),
+			zap.Int("results", len(checkpoint.Results)),
+			zap.Int("frontier", len(checkpoint.Frontier)))
+
+		go s.runCrawlAsync(crawl.ID, crawl.ProjectID, config, checkpoint)
+	}
+}
+
+// backfillCheckpointPages stores checkpointed results that never reached the pages table
+// (for example the unflushed batch when the server stopped). It returns the normalized URLs
+// already stored for the crawl so the progress callback does not insert them twice.
+func (s *Server) backfillCheckpointPages(crawlID string, checkpoint *crawler.Checkpoint) map[string]bool {
+	stored := make(map[string]bool)
+
+	const pageChunkSize = 1000
+	for offset := 0; ; offset += pageChunkSize {
+		data, _, err := s.serviceRole.From("pages").
+			Select("url", "", false).
+			Eq("crawl_id", crawlID).
+			Order("id", nil).
+			Range(offset, offset+pageChunkSize-1, "").
+			Execute()
+		if err != nil {
+			s.logger.Warn("Failed to load stored pages for resumed crawl", zap.String("crawl_id", crawlID), zap.Error(err))
+			break
+		}
+		var rows []struct {
+			URL string `json:"url"`
+		}
+		if err := json.Unmarshal(data, &rows); err != nil || len(rows) == 0 {
+			break
+		}
+		for _, row := range rows {
+			stored[row.URL] = true
+		}
+		if len(rows) < pageChunkSize {
+			break
+		}
+	}
+
+	missing := make([]map[string]interface{}, 0)
+	for _, page := range checkpoint.Results {
+		if _, queued := checkpoint.Frontier[page.URL]; queued {
+			continue // Will be crawled again
+		}
+		if utils.IsImageURL(page.URL) {
+			continue
+		}
+		record := s.pageRecord(crawlID, page)
+		url, _ := record["url"].(string)
+		if stored[url] {
+			continue
+		}
+		stored[url] = true
+		missing = append(missing, record)
+	}
+
+	const batchSize = 500
+	for i := 0; i < len(missing); i += batchSize {
+		end := i + batchSize
+		if end > len(missing) {
+			end = len(missing)
+		}
+		if _, _, err := s.serviceRole.From("pages").Insert(missing[i:end], false, "", "minimal", "").Execute(); err != nil {
+			s.logger.Error("Failed to backfill checkpoint pages", zap.String("crawl_id", crawlID), zap.Error(err))
+		}
+	}
+
+	s.logger.Info("Backfilled checkpoint pages",
+		zap.String("crawl_id", crawlID),
+		zap.Int("already_stored", len(stored)-len(missing)),
+		zap.Int("backfilled", len(missing)))
+
+	return stored
+}
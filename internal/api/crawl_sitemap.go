@@ -0,0 +1,130 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dillonlara115/barracudaseo/internal/exporter"
+	"github.com/dillonlara115/barracudaseo/pkg/models"
+	"go.uber.org/zap"
+)
+
+// sitemapPageRow is a stored page with its crawl time, used to generate a sitemap
+type sitemapPageRow struct {
+	baselinePageRow
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// handleCrawlSitemap handles GET /api/v1/crawls/:id/sitemap - generates sitemap.xml from a crawl.
+// Query parameters: part (1-based sitemap file when the crawl needs a sitemap index; the index
+// is returned without it), gzip, base_url (where the parts will be hosted) and lastmod
+// (crawl, previous or none).
+func (s *Server) handleCrawlSitemap(w http.ResponseWriter, r *http.Request, crawlID string, userID string) {
+	s.logger.Info("Generating crawl sitemap", zap.String("crawl_id", crawlID), zap.String("user_id", userID))
+
+	// Verify user has access to this crawl (via project membership)
+	hasAccess, err := s.verifyCrawlAccess(userID, crawlID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			s.respondError(w, http.StatusNotFound, "Crawl not found")
+		} else {
+			s.logger.Error("Failed to verify crawl access", zap.String("crawl_id", crawlID), zap.String("user_id", userID), zap.Error(err))
+			s.respondError(w, http.StatusInternalServerError, "Failed to verify crawl access")
+		}
+		return
+	}
+	if !hasAccess {
+		s.respondError(w, http.StatusForbidden, "You don't have access to this crawl")
+		return
+	}
+
+	query := r.URL.Query()
+	lastmod := query.Get("lastmod")
+	switch lastmod {
+	case "":
+		lastmod = exporter.LastModCrawl
+	case exporter.LastModCrawl, exporter.LastModPrevious, exporter.LastModNone:
+	default:
+		s.respondError(w, http.StatusBadRequest, "lastmod must be crawl, previous or none")
+		return
+	}
+	part := 0
+	if value := query.Get("part"); value != "" {
+		if part, err = strconv.Atoi(value); err != nil || part < 1 {
+			s.respondError(w, http.StatusBadRequest, "part must be a positive number")
+			return
+		}
+	}
+	gzipped := query.Get("gzip") == "true" || query.Get("gzip") == "1"
+
+	// Fetch pages using service role — paginate to exceed PostgREST 1000-row default
+	var pages []*models.PageResult
+	const chunkSize = 1000
+	for offset := 0; ; offset += chunkSize {
+		data, _, err := s.serviceRole.From("pages").
+			Select("url,status_code,canonical_url,indexability_status,created_at,data", "", false).
+			Eq("crawl_id", crawlID).
+			Eq("status_code", "200").
+			Order("id", nil).
+			Range(offset, offset+chunkSize-1, "").
+			Execute()
+		if err != nil {
+			s.logger.Error("Failed to fetch pages", zap.String("crawl_id", crawlID), zap.Int("offset", offset), zap.Error(err))
+			s.respondError(w, http.StatusInternalServerError, "Failed to fetch pages")
+			return
+		}
+		var rows []sitemapPageRow
+		if err := json.Unmarshal(data, &rows); err != nil {
+			s.logger.Error("Failed to parse pages data", zap.String("crawl_id", crawlID), zap.Error(err))
+			s.respondError(w, http.StatusInternalServerError, "Failed to parse pages")
+			return
+		}
+		for _, row := range rows {
+			page := row.pageResult()
+			page.CrawledAt = row.CreatedAt
+			pages = append(pages, page)
+		}
+		if len(rows) < chunkSize {
+			break
+		}
+	}
+
+	urls := exporter.SitemapURLs(pages, lastmod, nil)
+	files, err := exporter.BuildSitemaps(urls, exporter.SitemapOptions{
+		BaseURL: query.Get("base_url"),
+		Gzip:    gzipped,
+	})
+	if err != nil {
+		s.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// A single file is the sitemap itself; otherwise files[0] is the index and files[n] is part n
+	if part >= len(files) || (part > 0 && len(files) == 1) {
+		s.respondError(w, http.StatusNotFound, fmt.Sprintf("Sitemap part %d not found", part))
+		return
+	}
+	file := files[part]
+
+	s.logger.Info("Generated crawl sitemap",
+		zap.String("crawl_id", crawlID),
+		zap.Int("urls", len(urls)),
+		zap.Int("files", len(files)),
+		zap.String("file", file.Name))
+
+	if gzipped {
+		w.Header().Set("Content-Type", "application/gzip")
+	} else {
+		w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", file.Name))
+	w.Header().Set("X-Sitemap-Parts", strconv.Itoa(max(len(files)-1, 1)))
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(file.Data); err != nil {
+		s.logger.Error("Failed to write sitemap response", zap.Error(err))
+	}
+}
@@ -3,7 +3,9 @@ package api
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"net/http"
+	"os"
 	"strconv"
 	"strings"
 	"sync"
@@ -158,6 +160,11 @@ func (s *Server) handleCreateCrawl(w http.ResponseWriter, r *http.Request) {
 		},
 	}
 
+	// This instance owns the crawl until it finishes or stops renewing the lease
+	for column, value := range s.crawlLease() {
+		crawl[column] = value
+	}
+
 	// Insert crawl using service role (bypasses RLS)
 	_, _, err = s.serviceRole.From("crawls").Insert(crawl, false, "", "", "").Execute()
 	if err != nil {
@@ -184,29 +191,7 @@ func (s *Server) handleCreateCrawl(w http.ResponseWriter, r *http.Request) {
 		}
 		seenPageURLs[normalizedPageURL] = true
 
-		pageData := map[string]interface{}{
-			"crawl_id":            crawlID,
-			"url":                 normalizedPageURL,
-			"status_code":         page.StatusCode,
-			"response_time_ms":    page.ResponseTime,
-			"title":               page.Title,
-			"meta_description":    page.MetaDesc,
-			"canonical_url":       page.Canonical,
-			"h1":                  strings.Join(page.H1, ", "),
-			"indexability_status": string(page.IndexabilityStatus),
-			"word_count":          0, // TODO: calculate from content
-			"data": map[string]interface{}{
-				"h2":             page.H2,
-				"h3":             page.H3,
-				"h4":             page.H4,
-				"h5":             page.H5,
-				"h6":             page.H6,
-				"internal_links": page.InternalLinks,
-				"external_links": page.ExternalLinks,
-				"images":         page.Images,
-			},
-		}
-		pages = append(pages, pageData)
+		pages = append(pages, s.pageRecord(crawlID, page))
 	}
 
 	// Batch insert pages (Supabase supports up to 1000 rows per insert)
@@ -471,6 +456,10 @@ func (s *Server) handleListProjects(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	for _, project := range projects {
+		redactCrawlAuth(project)
+	}
+
 	s.logger.Debug("Listed projects", zap.String("user_id", userID), zap.Int("count", len(projects)))
 	s.respondJSON(w, http.StatusOK, map[string]interface{}{
 		"projects": projects,
@@ -528,6 +517,9 @@ func (s *Server) handleProjectByID(w http.ResponseWriter, r *http.Request) {
 				s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 			}
 			return
+		case "crawl-auth":
+			s.handleProjectCrawlAuth(w, r, projectID, userID)
+			return
 		case "gsc":
 			s.handleProjectGSC(w, r, projectID, userID, parts[2:])
 			return
@@ -624,6 +616,7 @@ func (s *Server) handleGetProject(w http.ResponseWriter, r *http.Request, projec
 		return
 	}
 
+	redactCrawlAuth(projects[0])
 	s.respondJSON(w, http.StatusOK, projects[0])
 }
 
@@ -656,6 +649,11 @@ func (s *Server) handleUpdateProject(w http.ResponseWriter, r *http.Request, pro
 		updateData["domain"] = req.Domain
 	}
 	if req.Settings != nil {
+		if err := s.preserveCrawlAuth(projectID, req.Settings); err != nil {
+			s.logger.Error("Failed to load project settings", zap.Error(err))
+			s.respondError(w, http.StatusInternalServerError, "Failed to update project")
+			return
+		}
 		updateData["settings"] = req.Settings
 	}
 
@@ -702,6 +700,7 @@ func (s *Server) handleUpdateProject(w http.ResponseWriter, r *http.Request, pro
 		return
 	}
 
+	redactCrawlAuth(projects[0])
 	s.respondJSON(w, http.StatusOK, projects[0])
 }
 
@@ -848,6 +847,19 @@ func (s *Server) handleTriggerCrawl(w http.ResponseWriter, r *http.Request, proj
 		return
 	}
 
+	// List mode: crawl exactly the given URLs
+	if len(req.URLs) > 0 {
+		urls, skipped := utils.NormalizeURLList(req.URLs)
+		if skipped > 0 {
+			s.respondError(w, http.StatusBadRequest, fmt.Sprintf("urls must be http(s) URLs (%d invalid)", skipped))
+			return
+		}
+		req.URLs = urls
+		if req.URL == "" {
+			req.URL = urls[0]
+		}
+	}
+
 	// Validate and set defaults
 	if req.URL == "" {
 		s.respondError(w, http.StatusBadRequest, "url is required")
@@ -874,6 +886,44 @@ func (s *Server) handleTriggerCrawl(w http.ResponseWriter, r *http.Request, proj
 		f := false
 		req.CrawlSitemapOnly = &f
 	}
+	// Default render mode to static HTML if not provided
+	if req.Render == "" {
+		req.Render = "static"
+	}
+	if req.Render != "static" && req.Render != "js" {
+		s.respondError(w, http.StatusBadRequest, "render must be 'static' or 'js'")
+		return
+	}
+	// Default crawl order to breadth-first if not provided
+	if req.CrawlOrder == "" {
+		req.CrawlOrder = "bfs"
+	}
+	if req.CrawlOrder != "bfs" && req.CrawlOrder != "dfs" && req.CrawlOrder != "sitemap-first" {
+		s.respondError(w, http.StatusBadRequest, "crawl_order must be 'bfs', 'dfs' or 'sitemap-first'")
+		return
+	}
+	// Default subdomain scope to the start host only if not provided
+	if req.SubdomainScope == "" {
+		req.SubdomainScope = utils.SubdomainScopeNone
+	}
+	if req.SubdomainScope != utils.SubdomainScopeNone && req.SubdomainScope != utils.SubdomainScopeAll {
+		s.respondError(w, http.StatusBadRequest, "subdomain_scope must be 'none' or 'all'")
+		return
+	}
+	for _, rule := range req.URLRules {
+		if _, err := utils.ParseURLRule(rule.Action + ":" + rule.Pattern); err != nil {
+			s.respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	// Credentials for protected sites are stored per project, never in the request body
+	crawlAuth, err := s.getProjectCrawlAuth(projectID)
+	if err != nil {
+		s.logger.Error("Failed to load crawl credentials", zap.String("project_id", projectID), zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to load crawl credentials")
+		return
+	}
 
 	// Get effective subscription for limits
 	subscription, err := s.resolveSubscription(userID)
@@ -885,7 +935,15 @@ func (s *Server) handleTriggerCrawl(w http.ResponseWriter, r *http.Request, proj
 
 	maxPagesLimit := getMaxPagesLimit(subscription.EffectiveTier)
 
-	// Set default max pages if not provided
+	if len(req.URLs) > maxPagesLimit {
+		s.respondError(w, http.StatusForbidden, fmt.Sprintf("Your %s plan allows a maximum of %d pages per crawl, but %d URLs were listed. Please upgrade to crawl more pages.", subscription.EffectiveTier, maxPagesLimit, len(req.URLs)))
+		return
+	}
+
+	// Set default max pages if not provided (a URL list is crawled in full)
+	if req.MaxPages == 0 && len(req.URLs) > 0 {
+		req.MaxPages = len(req.URLs)
+	}
 	if req.MaxPages == 0 {
 		req.MaxPages = maxPagesLimit
 	}
@@ -908,13 +966,25 @@ func (s *Server) handleTriggerCrawl(w http.ResponseWriter, r *http.Request, proj
 		"total_pages":  0,
 		"total_issues": 0,
 		"meta": map[string]interface{}{
-			"url":                req.URL,
-			"max_depth":          req.MaxDepth,
-			"max_pages":          req.MaxPages,
-			"workers":            req.Workers,
-			"respect_robots":     *req.RespectRobots,
-			"parse_sitemap":      *req.ParseSitemap,
-			"crawl_sitemap_only": *req.CrawlSitemapOnly,
+			"url":                  req.URL,
+			"max_depth":            req.MaxDepth,
+			"max_pages":            req.MaxPages,
+			"workers":              req.Workers,
+			"respect_robots":       *req.RespectRobots,
+			"parse_sitemap":        *req.ParseSitemap,
+			"crawl_sitemap_only":   *req.CrawlSitemapOnly,
+			"render":               req.Render,
+			"crawl_order":          req.CrawlOrder,
+			"subdomain_scope":      req.SubdomainScope,
+			"url_rules":            req.URLRules,
+			"strip_query_params":   req.StripQueryParams,
+			"authenticated":        crawlAuth != nil,
+			"incremental":          req.Incremental,
+			"list_mode":            len(req.URLs) > 0,
+			"url_count":            len(req.URLs),
+			"parity":               req.Parity,
+			"page_weight_budget":   req.PageWeightBudget,
+			"duplicate_similarity": req.DuplicateSimilarity,
 		},
 	}
 
@@ -957,7 +1027,11 @@ func (s *Server) handleTriggerCrawl(w http.ResponseWriter, r *http.Request, proj
 	}
 
 	// Start crawl asynchronously
-	go s.runCrawlAsync(crawlID, projectID, req)
+	config := s.newCrawlConfig(crawlID, req)
+	if crawlAuth != nil {
+		config.Auth = *crawlAuth
+	}
+	go s.runCrawlAsync(crawlID, projectID, config, nil)
 
 	// Return immediately with crawl ID
 	s.respondJSON(w, http.StatusAccepted, map[string]interface{}{
@@ -967,8 +1041,42 @@ func (s *Server) handleTriggerCrawl(w http.ResponseWriter, r *http.Request, proj
 	})
 }
 
-// runCrawlAsync runs the crawler and stores results
-func (s *Server) runCrawlAsync(crawlID, projectID string, req TriggerCrawlRequest) {
+// newCrawlConfig builds the crawler configuration for a web-triggered crawl
+func (s *Server) newCrawlConfig(crawlID string, req TriggerCrawlRequest) *utils.Config {
+	return &utils.Config{
+		StartURL:            req.URL,
+		URLList:             req.URLs,
+		MaxDepth:            req.MaxDepth,
+		MaxPages:            req.MaxPages,
+		Workers:             req.Workers,
+		Delay:               0,
+		Timeout:             30 * time.Second,
+		UserAgent:           "barracuda/1.0.0",
+		Parity:              req.Parity,
+		RespectRobots:       *req.RespectRobots,
+		ParseSitemap:        *req.ParseSitemap,
+		CrawlSitemapOnly:    *req.CrawlSitemapOnly,
+		RenderMode:          req.Render,
+		CrawlOrder:          req.CrawlOrder,
+		SubdomainScope:      req.SubdomainScope,
+		URLRules:            req.URLRules,
+		StripQueryParams:    req.StripQueryParams,
+		Incremental:         req.Incremental,
+		PageWeightBudgetKB:  req.PageWeightBudget,
+		DuplicateSimilarity: req.DuplicateSimilarity,
+		DomainFilter:        "same",
+		ExportFormat:        "csv", // Required for validation, but not used since we store in DB
+		ExportPath:          "",    // Not used for web crawls
+		StateDir:            s.crawlStateDirFor(crawlID),
+	}
+}
+
+// runCrawlAsync runs the crawler and stores results.
+// When checkpoint is non-nil the crawl resumes from it instead of starting over.
+func (s *Server) runCrawlAsync(crawlID, projectID string, config *utils.Config, checkpoint *crawler.Checkpoint) {
+	// Keep other instances from resuming the crawl while it runs here
+	defer s.holdCrawlLease(crawlID)()
+
 	// Initialize logger for crawler (enable debug temporarily to diagnose crawling issues)
 	if err := utils.InitLogger(true); err != nil {
 		s.logger.Error("Failed to initialize logger", zap.Error(err))
@@ -977,23 +1085,6 @@ func (s *Server) runCrawlAsync(crawlID, projectID string, req TriggerCrawlReques
 	}
 	defer utils.Sync()
 
-	// Create crawler config
-	config := &utils.Config{
-		StartURL:         req.URL,
-		MaxDepth:         req.MaxDepth,
-		MaxPages:         req.MaxPages,
-		Workers:          req.Workers,
-		Delay:            0,
-		Timeout:          30 * time.Second,
-		UserAgent:        "barracuda/1.0.0",
-		RespectRobots:    *req.RespectRobots,
-		ParseSitemap:     *req.ParseSitemap,
-		CrawlSitemapOnly: *req.CrawlSitemapOnly,
-		DomainFilter:     "same",
-		ExportFormat:     "csv", // Required for validation, but not used since we store in DB
-		ExportPath:       "",    // Not used for web crawls
-	}
-
 	// Validate config
 	if err := config.Validate(); err != nil {
 		s.logger.Error("Invalid crawl config", zap.Error(err))
@@ -1004,6 +1095,16 @@ func (s *Server) runCrawlAsync(crawlID, projectID string, req TriggerCrawlReques
 	// Create crawler manager
 	manager := crawler.NewManager(config)
 
+	// Incremental crawls compare against the project's last completed crawl
+	if config.Incremental {
+		baseline, err := s.loadCrawlBaseline(projectID, crawlID)
+		if err != nil {
+			s.logger.Warn("Failed to load previous crawl, crawling all pages in full", zap.String("crawl_id", crawlID), zap.Error(err))
+		} else {
+			manager.SetBaseline(baseline)
+		}
+	}
+
 	// Set initial phase for UI progress feedback
 	s.updateCrawlPhase(crawlID, "scanning")
 
@@ -1057,6 +1158,14 @@ func (s *Server) runCrawlAsync(crawlID, projectID string, req TriggerCrawlReques
 	var pagesMu sync.Mutex
 	totalPagesProcessed := int32(0)
 
+	// Restore checkpointed state and make sure its pages are stored before crawling resumes
+	storedURLs := make(map[string]bool)
+	if checkpoint != nil {
+		manager.Resume(checkpoint)
+		storedURLs = s.backfillCheckpointPages(crawlID, checkpoint)
+		totalPagesProcessed = int32(len(storedURLs))
+	}
+
 	// Set up progress callback to store pages in real-time
 	manager.SetProgressCallback(func(page *models.PageResult, totalPages int) {
 		pagesMu.Lock()
@@ -1074,69 +1183,10 @@ func (s *Server) runCrawlAsync(crawlID, projectID string, req TriggerCrawlReques
 			return
 		}
 
-		// Ensure arrays are never nil - use empty slices instead
-		// This prevents JSONB from storing null instead of []
-		internalLinks := page.InternalLinks
-		if internalLinks == nil {
-			internalLinks = []string{}
-		}
-		externalLinks := page.ExternalLinks
-		if externalLinks == nil {
-			externalLinks = []string{}
-		}
-		h2 := page.H2
-		if h2 == nil {
-			h2 = []string{}
-		}
-		h3 := page.H3
-		if h3 == nil {
-			h3 = []string{}
-		}
-		h4 := page.H4
-		if h4 == nil {
-			h4 = []string{}
-		}
-		h5 := page.H5
-		if h5 == nil {
-			h5 = []string{}
-		}
-		h6 := page.H6
-		if h6 == nil {
-			h6 = []string{}
-		}
-		images := page.Images
-		if images == nil {
-			images = []models.Image{}
-		}
-
-		// Normalize page URL to prevent duplicates (e.g., with/without trailing slash)
-		normalizedPageURL, err := utils.NormalizeURL(page.URL)
-		if err != nil {
-			s.logger.Warn("Failed to normalize page URL in progress callback", zap.String("url", page.URL), zap.Error(err))
-			normalizedPageURL = page.URL
-		}
-
-		pageData := map[string]interface{}{
-			"crawl_id":            crawlID,
-			"url":                 normalizedPageURL,
-			"status_code":         page.StatusCode,
-			"response_time_ms":    page.ResponseTime,
-			"title":               page.Title,
-			"meta_description":    page.MetaDesc,
-			"canonical_url":       page.Canonical,
-			"h1":                  strings.Join(page.H1, ", "),
-			"indexability_status": string(page.IndexabilityStatus),
-			"word_count":          0, // TODO: calculate from content
-			"data": map[string]interface{}{
-				"h2":             h2,
-				"h3":             h3,
-				"h4":             h4,
-				"h5":             h5,
-				"h6":             h6,
-				"internal_links": internalLinks,
-				"external_links": externalLinks,
-				"images":         images,
-			},
+		pageData := s.pageRecord(crawlID, page)
+		if url, _ := pageData["url"].(string); storedURLs[url] {
+			s.logger.Debug("Skipping page already stored before resume", zap.String("url", url))
+			return
 		}
 
 		// Log page data being stored for debugging (first few pages only)
@@ -1145,8 +1195,8 @@ func (s *Server) runCrawlAsync(crawlID, projectID string, req TriggerCrawlReques
 				zap.String("url", page.URL),
 				zap.Int("h1_count", len(page.H1)),
 				zap.Strings("h1_values", page.H1),
-				zap.Int("internal_links_count", len(internalLinks)),
-				zap.Int("external_links_count", len(externalLinks)))
+				zap.Int("internal_links_count", len(page.InternalLinks)),
+				zap.Int("external_links_count", len(page.ExternalLinks)))
 		}
 
 		pages = append(pages, pageData)
@@ -1181,6 +1231,7 @@ func (s *Server) runCrawlAsync(crawlID, projectID string, req TriggerCrawlReques
 				// Update crawl total_pages in real-time after batch insert
 				update := map[string]interface{}{
 					"total_pages": currentTotal,
+					"crawl_rate":  math.Round(manager.EffectiveRate()*100) / 100,
 					"status":      "running", // Ensure status stays as running
 				}
 				_, _, err = s.serviceRole.From("crawls").Update(update, "", "").Eq("id", crawlID).Execute()
@@ -1196,6 +1247,7 @@ func (s *Server) runCrawlAsync(crawlID, projectID string, req TriggerCrawlReques
 			// Only skip if we just updated in a batch to avoid redundant updates
 			update := map[string]interface{}{
 				"total_pages": currentTotal,
+				"crawl_rate":  math.Round(manager.EffectiveRate()*100) / 100,
 				"status":      "running", // Ensure status stays as running
 			}
 			_, _, err := s.serviceRole.From("crawls").Update(update, "", "").Eq("id", crawlID).Execute()
@@ -1209,6 +1261,17 @@ func (s *Server) runCrawlAsync(crawlID, projectID string, req TriggerCrawlReques
 
 	// Run crawl
 	results, err := manager.Crawl()
+	if manager.Interrupted() {
+		// Server is shutting down - leave the crawl running so ResumeInterruptedCrawls picks it up
+		s.logger.Warn("Crawl interrupted by shutdown, checkpoint kept for resume", zap.String("crawl_id", crawlID))
+		s.releaseCrawlLease(crawlID)
+		return
+	}
+	defer func() {
+		if err := os.RemoveAll(config.StateDir); err != nil {
+			s.logger.Warn("Failed to remove crawl state directory", zap.String("dir", config.StateDir), zap.Error(err))
+		}
+	}()
 	if err != nil {
 		s.logger.Error("Crawl failed", zap.Error(err))
 		s.updateCrawlStatus(crawlID, "failed", err.Error())
@@ -1271,11 +1334,18 @@ func (s *Server) runCrawlAsync(crawlID, projectID string, req TriggerCrawlReques
 	summary := analyzer.Analyze(filteredResults)
 	s.updateCrawlPhase(crawlID, "image_analysis")
 	imageIssues := analyzer.AnalyzeImages(filteredResults, config.Timeout)
+	imageIssues = append(imageIssues, analyzer.AnalyzeSocialImages(filteredResults, config.Timeout)...)
 	summary.Issues = append(summary.Issues, imageIssues...)
 	for _, issue := range imageIssues {
 		summary.IssuesByType[issue.Type]++
 	}
 	summary.TotalIssues = len(summary.Issues)
+	summary.AddIssues(analyzer.AnalyzeResources(filteredResults, manager.HTTPClient(), config.PageWeightBudgetKB))
+	summary.DuplicateContent = analyzer.FindDuplicateContent(filteredResults, config.DuplicateSimilarity)
+	summary.AddIssues(analyzer.DuplicateContentIssues(summary.DuplicateContent))
+	robotsReport := manager.RobotsReport()
+	summary.AddIssues(analyzer.AnalyzeRobots(robotsReport))
+	summary.AddIssues(analyzer.AnalyzeSitemap(filteredResults, manager.GetLinkGraph(), robotsReport))
 
 	// Refresh pageURLToID map before creating issues to ensure we have all pages.
 	// PostgREST limits to 1000 rows by default—paginate to fetch all.
@@ -1438,9 +1508,13 @@ func (s *Server) runCrawlAsync(crawlID, projectID string, req TriggerCrawlReques
 	// Update crawl status to succeeded (total_pages already updated via callback)
 	s.updateCrawlStatus(crawlID, "succeeded", "")
 	update := map[string]interface{}{
-		"total_pages":  finalTotal, // Use the final count from callback
-		"total_issues": len(summary.Issues),
-		"completed_at": time.Now().UTC().Format(time.RFC3339),
+		"total_pages":   finalTotal, // Use the final count from callback
+		"total_issues":  len(summary.Issues),
+		"completed_at":  time.Now().UTC().Format(time.RFC3339),
+		"robots_report": robotsReport,
+	}
+	if config.Incremental {
+		update["unchanged_pages"] = countUnchanged(filteredResults)
 	}
 	_, _, err = s.serviceRole.From("crawls").Update(update, "", "").Eq("id", crawlID).Execute()
 	if err != nil {
@@ -1448,6 +1522,120 @@ func (s *Server) runCrawlAsync(crawlID, projectID string, req TriggerCrawlReques
 	}
 }
 
+// pageRecord builds the pages table row for a crawled page
+func (s *Server) pageRecord(crawlID string, page *models.PageResult) map[string]interface{} {
+	// Ensure arrays are never nil - use empty slices instead
+	// This prevents JSONB from storing null instead of []
+	internalLinks := page.InternalLinks
+	if internalLinks == nil {
+		internalLinks = []string{}
+	}
+	externalLinks := page.ExternalLinks
+	if externalLinks == nil {
+		externalLinks = []string{}
+	}
+	h1 := page.H1
+	if h1 == nil {
+		h1 = []string{}
+	}
+	h2 := page.H2
+	if h2 == nil {
+		h2 = []string{}
+	}
+	h3 := page.H3
+	if h3 == nil {
+		h3 = []string{}
+	}
+	h4 := page.H4
+	if h4 == nil {
+		h4 = []string{}
+	}
+	h5 := page.H5
+	if h5 == nil {
+		h5 = []string{}
+	}
+	h6 := page.H6
+	if h6 == nil {
+		h6 = []string{}
+	}
+	images := page.Images
+	if images == nil {
+		images = []models.Image{}
+	}
+
+	// Normalize page URL to prevent duplicates (e.g., with/without trailing slash)
+	normalizedPageURL, err := utils.NormalizeURL(page.URL)
+	if err != nil {
+		s.logger.Warn("Failed to normalize page URL", zap.String("url", page.URL), zap.Error(err))
+		normalizedPageURL = page.URL
+	}
+
+	return map[string]interface{}{
+		"crawl_id":            crawlID,
+		"url":                 normalizedPageURL,
+		"status_code":         page.StatusCode,
+		"response_time_ms":    page.ResponseTime,
+		"title":               page.Title,
+		"meta_description":    page.MetaDesc,
+		"canonical_url":       page.Canonical,
+		"h1":                  strings.Join(page.H1, ", "),
+		"indexability_status": string(page.IndexabilityStatus),
+		"word_count":          page.WordCount,
+		"text_ratio":          page.TextRatio,
+		"text_hash":           page.TextHash,
+		"simhash":             page.SimHash,
+		"content_hash":        page.ContentHash,
+		"etag":                page.ETag,
+		"last_modified":       page.LastModified,
+		"unchanged":           page.Unchanged,
+		"data": map[string]interface{}{
+			"h1":                 h1,
+			"h2":                 h2,
+			"h3":                 h3,
+			"h4":                 h4,
+			"h5":                 h5,
+			"h6":                 h6,
+			"internal_links":     internalLinks,
+			"external_links":     externalLinks,
+			"links":              page.Links,
+			"images":             images,
+			"render_mode":        page.RenderMode,
+			"raw":                page.Raw,
+			"meta_robots":        page.MetaRobots,
+			"x_robots_tag":       page.XRobotsTag,
+			"redirect_chain":     page.RedirectChain,
+			"redirect_hops":      page.RedirectHops,
+			"redirect_loop":      page.RedirectLoop,
+			"too_many_redirects": page.TooManyRedirects,
+			"device":             page.Device,
+			"desktop":            page.Desktop,
+			"robots_rule":        page.RobotsRule,
+			"sitemap":            page.Sitemap,
+			"headers":            page.Headers,
+			"tls":                page.TLS,
+			"mixed_content":      page.MixedContent,
+			"resources":          page.Resources,
+			"size":               page.Size,
+			"timing":             page.Timing,
+			"structured_data":    page.StructuredData,
+			"hreflang":           page.Hreflang,
+			"html_lang":          page.HTMLLang,
+			"social":             page.Social,
+		},
+	}
+}
+
+// countUnchanged returns how many pages were unchanged since the previous crawl
+func countUnchanged(pages []*models.PageResult) int {
+	count := 0
+	for _, page := range pages {
+		if page.Unchanged {
+			count++
+		}
+	}
+	return count
+}
+
 // updateCrawlPhase sets the current phase (scanning, metadata_review, image_analysis, storing)
 func (s *Server) updateCrawlPhase(crawlID, phase string) {
 	_, _, err := s.serviceRole.From("crawls").Update(map[string]interface{}{"phase": phase}, "", "").Eq("id", crawlID).Execute()
@@ -1850,6 +2038,20 @@ func (s *Server) handleCrawlByID(w http.ResponseWriter, r *http.Request) {
 				s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 			}
 			return
+		case "sitemap":
+			if r.Method == http.MethodGet {
+				s.handleCrawlSitemap(w, r, crawlID, userID)
+			} else {
+				s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			}
+			return
+		case "duplicates":
+			if r.Method == http.MethodGet {
+				s.handleCrawlDuplicates(w, r, crawlID, userID)
+			} else {
+				s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			}
+			return
 		default:
 			s.respondError(w, http.StatusNotFound, fmt.Sprintf("Resource not found: %s", resource))
 			return
@@ -1984,9 +2186,13 @@ func (s *Server) handleGetCrawl(w http.ResponseWriter, r *http.Request, crawlID
 	s.respondJSON(w, http.StatusOK, crawl)
 }
 
-// handleCrawlGraph handles GET /api/v1/crawls/:id/graph - returns link graph data
+// handleCrawlGraph handles GET /api/v1/crawls/:id/graph - returns link graph data.
+// With format=edges it returns every link with its anchor text, rel and position instead.
 func (s *Server) handleCrawlGraph(w http.ResponseWriter, r *http.Request, crawlID string) {
-	_ = r
+	if r.URL.Query().Get("format") == "edges" {
+		s.handleCrawlGraphEdges(w, crawlID)
+		return
+	}
 
 	s.logger.Info("Fetching link graph", zap.String("crawl_id", crawlID))
 
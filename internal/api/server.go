@@ -17,6 +17,7 @@ import (
 	"github.com/dillonlara115/barracudaseo/internal/ga4"
 	"github.com/dillonlara115/barracudaseo/internal/gsc"
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
 	"github.com/supabase-community/supabase-go"
 	"go.uber.org/zap"
 )
@@ -28,6 +29,7 @@ type Config struct {
 	SupabaseAnonKey    string
 	SupabaseJWTSecret  string
 	CronSyncSecret     string
+	CrawlStateDir      string // Directory for crawl checkpoints (default: $TMPDIR/barracuda-crawls)
 	Logger             *zap.Logger
 }
 
@@ -47,6 +49,8 @@ type Server struct {
 	// JWKS for local JWT validation (avoids hitting Supabase for every request)
 	jwks    *keyfunc.JWKS
 	jwksURL string
+	// Identifies this instance as the lease owner of the web crawls it runs
+	instanceID string
 }
 
 // TokenCacheEntry represents a cached token validation result
@@ -82,6 +86,7 @@ func NewServer(cfg Config) (*Server, error) {
 		tokenCache:    make(map[string]TokenCacheEntry),
 		tokenInflight: make(map[string]chan struct{}),
 		jwksURL:       strings.TrimSuffix(cfg.SupabaseURL, "/") + "/auth/v1/keys",
+		instanceID:    uuid.New().String(),
 	}
 
 	// Load JWKS for local token validation; fall back to Supabase API if it fails
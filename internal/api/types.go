@@ -1,6 +1,9 @@
 package api
 
-import "github.com/dillonlara115/barracudaseo/pkg/models"
+import (
+	"github.com/dillonlara115/barracudaseo/internal/utils"
+	"github.com/dillonlara115/barracudaseo/pkg/models"
+)
 
 // CreateCrawlRequest represents a crawl ingestion request
 type CreateCrawlRequest struct {
@@ -27,11 +30,21 @@ type CreateProjectRequest struct {
 
 // TriggerCrawlRequest represents a request to trigger a new crawl
 type TriggerCrawlRequest struct {
-	URL              string `json:"url"`                // Starting URL to crawl
-	MaxDepth         int    `json:"max_depth"`          // Maximum crawl depth (default: 3)
-	MaxPages         int    `json:"max_pages"`          // Maximum pages to crawl (default: 1000)
-	Workers          int    `json:"workers"`            // Number of concurrent workers (default: 10)
-	RespectRobots    *bool  `json:"respect_robots"`     // Respect robots.txt (default: true)
-	ParseSitemap     *bool  `json:"parse_sitemap"`      // Parse sitemap.xml (default: false)
-	CrawlSitemapOnly *bool  `json:"crawl_sitemap_only"` // Crawl only sitemap URLs, no link discovery—like indexed pages (default: false, requires parse_sitemap)
+	URL                 string          `json:"url"`                  // Starting URL to crawl (optional when urls is set)
+	URLs                []string        `json:"urls"`                 // List mode: crawl exactly these URLs, without sitemap or link discovery
+	MaxDepth            int             `json:"max_depth"`            // Maximum crawl depth (default: 3)
+	MaxPages            int             `json:"max_pages"`            // Maximum pages to crawl (default: 1000)
+	Workers             int             `json:"workers"`              // Number of concurrent workers (default: 10)
+	RespectRobots       *bool           `json:"respect_robots"`       // Respect robots.txt (default: true)
+	ParseSitemap        *bool           `json:"parse_sitemap"`        // Parse sitemap.xml (default: false)
+	CrawlSitemapOnly    *bool           `json:"crawl_sitemap_only"`   // Crawl only sitemap URLs, no link discovery—like indexed pages (default: false, requires parse_sitemap)
+	Render              string          `json:"render"`               // Render mode: "static" or "js" (default: "static")
+	CrawlOrder          string          `json:"crawl_order"`          // Crawl order: "bfs", "dfs" or "sitemap-first" (default: "bfs")
+	SubdomainScope      string          `json:"subdomain_scope"`      // Subdomain scope: "none" or "all" (default: "none")
+	URLRules            []utils.URLRule `json:"url_rules"`            // Ordered include/exclude rules on path and query, first match wins
+	StripQueryParams    []string        `json:"strip_query_params"`   // Query parameters stripped before URLs are queued (globs, "*" for all)
+	Incremental         bool            `json:"incremental"`          // Re-crawl against the project's last completed crawl, reusing unchanged pages (default: false)
+	Parity              bool            `json:"parity"`               // Fetch every URL as both a smartphone and a desktop agent and compare them (default: false)
+	PageWeightBudget    int             `json:"page_weight_budget"`   // Report pages whose HTML plus subresources exceed this many KB (default: 3072)
+	DuplicateSimilarity float64         `json:"duplicate_similarity"` // Share of matching content fingerprint bits (0-1) at which pages are near duplicates (default: 0.9)
 }
@@ -0,0 +1,291 @@
+package crawler
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/dillonlara115/barracudaseo/internal/utils"
+)
+
+// ConfigureAuth sets up headers, basic auth and a cookie jar for crawling siteURL.
+// Headers and basic auth are only sent to siteURL's domain (including subdomains).
+func (f *Fetcher) ConfigureAuth(auth utils.AuthConfig, siteURL string) error {
+	site, err := url.Parse(siteURL)
+	if err != nil || site.Hostname() == "" {
+		return fmt.Errorf("invalid site URL for authentication: %s", siteURL)
+	}
+
+	f.authDomain = strings.TrimPrefix(strings.ToLower(site.Hostname()), "www.")
+	f.headers = auth.Headers
+	f.basicUsername = auth.BasicUsername
+	f.basicPassword = auth.BasicPassword
+
+	// Cookies are only kept when the crawl needs a session
+	if auth.CookieFile == "" && auth.Cookies == "" && auth.LoginURL == "" {
+		return nil
+	}
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return fmt.Errorf("failed to create cookie jar: %w", err)
+	}
+	f.client.Jar = jar
+
+	cookies := auth.Cookies
+	if auth.CookieFile != "" {
+		data, err := os.ReadFile(auth.CookieFile)
+		if err != nil {
+			return fmt.Errorf("failed to read cookie file: %w", err)
+		}
+		cookies = string(data)
+	}
+	if cookies != "" {
+		loaded, err := loadCookies(jar, strings.NewReader(cookies))
+		if err != nil {
+			return err
+		}
+		utils.Info("Loaded cookies", utils.NewField("count", loaded))
+	}
+
+	return nil
+}
+
+// sendsCredentials reports whether headers and basic auth should be sent to targetURL
+func (f *Fetcher) sendsCredentials(targetURL string) bool {
+	if f.authDomain == "" {
+		return false
+	}
+	u, err := url.Parse(targetURL)
+	if err != nil {
+		return false
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	return host == f.authDomain || strings.HasSuffix(host, "."+f.authDomain)
+}
+
+// applyCredentials adds configured headers and basic auth to a request for the crawled site
+func (f *Fetcher) applyCredentials(req *http.Request) {
+	if !f.sendsCredentials(req.URL.String()) {
+		return
+	}
+	for name, value := range f.headers {
+		if strings.EqualFold(name, "Host") {
+			req.Host = value
+			continue
+		}
+		req.Header.Set(name, value)
+	}
+	if f.basicUsername != "" || f.basicPassword != "" {
+		req.SetBasicAuth(f.basicUsername, f.basicPassword)
+	}
+}
+
+// browserHeaders adds the configured credentials and the cookie jar's cookies to the headers
+// of a request made by the headless browser. It returns false when nothing applies to targetURL.
+func (f *Fetcher) browserHeaders(targetURL string, headers map[string]string) ([]map[string]string, bool) {
+	req, err := http.NewRequest(http.MethodGet, targetURL, nil)
+	if err != nil {
+		return nil, false
+	}
+	var cookies []*http.Cookie
+	if f.client.Jar != nil {
+		cookies = f.client.Jar.Cookies(req.URL)
+	}
+	if !f.sendsCredentials(targetURL) && len(cookies) == 0 {
+		return nil, false
+	}
+
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	f.applyCredentials(req)
+	// Cookies the page set itself take precedence over the jar's
+	for _, cookie := range cookies {
+		if _, err := req.Cookie(cookie.Name); err != nil {
+			req.AddCookie(cookie)
+		}
+	}
+
+	merged := make([]map[string]string, 0, len(req.Header))
+	for name, values := range req.Header {
+		for _, value := range values {
+			merged = append(merged, map[string]string{"name": name, "value": value})
+		}
+	}
+	return merged, true
+}
+
+// loadCookies adds cookies in Netscape cookies.txt format (as exported by browsers and curl) to a jar
+func loadCookies(jar http.CookieJar, r io.Reader) (int, error) {
+	count := 0
+	scanner := bufio.NewScanner(r)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+
+		httpOnly := false
+		if rest, ok := strings.CutPrefix(line, "#HttpOnly_"); ok {
+			line = rest
+			httpOnly = true
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		if len(fields) != 7 {
+			return count, fmt.Errorf("invalid cookie on line %d: expected 7 tab-separated fields", lineNum)
+		}
+
+		domain := fields[0]
+		secure := strings.EqualFold(fields[3], "TRUE")
+		cookie := &http.Cookie{
+			Name:     fields[5],
+			Value:    fields[6],
+			Path:     fields[2],
+			Secure:   secure,
+			HttpOnly: httpOnly,
+		}
+		// A leading dot (or the include-subdomains flag) makes it a domain cookie
+		if strings.HasPrefix(domain, ".") || strings.EqualFold(fields[1], "TRUE") {
+			cookie.Domain = domain
+		}
+		if expires, err := strconv.ParseInt(fields[4], 10, 64); err == nil && expires > 0 {
+			cookie.Expires = time.Unix(expires, 0)
+		}
+
+		scheme := "http"
+		if secure {
+			scheme = "https"
+		}
+		cookieURL := &url.URL{Scheme: scheme, Host: strings.TrimPrefix(domain, "."), Path: "/"}
+		jar.SetCookies(cookieURL, []*http.Cookie{cookie})
+		count++
+	}
+	if err := scanner.Err(); err != nil {
+		return count, fmt.Errorf("failed to read cookies: %w", err)
+	}
+	return count, nil
+}
+
+// Login submits the login form found at auth.LoginURL with auth.LoginFields filled in.
+// Hidden inputs (such as CSRF tokens) are submitted as found; the session cookies end up in the jar.
+func (f *Fetcher) Login(ctx context.Context, auth utils.AuthConfig) error {
+	if f.client.Jar == nil {
+		return fmt.Errorf("login requires a cookie jar (call ConfigureAuth first)")
+	}
+
+	page := f.FetchContext(ctx, auth.LoginURL)
+	if page.Error != nil {
+		return fmt.Errorf("failed to load login page: %w", page.Error)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(string(page.Body)))
+	if err != nil {
+		return fmt.Errorf("failed to parse login page: %w", err)
+	}
+
+	// Prefer the form with a password field
+	form := doc.Find("form").FilterFunction(func(_ int, s *goquery.Selection) bool {
+		return s.Find("input[type=password]").Length() > 0
+	}).First()
+	if form.Length() == 0 {
+		form = doc.Find("form").First()
+	}
+	if form.Length() == 0 {
+		return fmt.Errorf("no login form found at %s", auth.LoginURL)
+	}
+
+	values := formValues(form)
+	for name, value := range auth.LoginFields {
+		values.Set(name, value)
+	}
+
+	action, _ := form.Attr("action")
+	actionURL, err := utils.ResolveURL(auth.LoginURL, action)
+	if err != nil || action == "" {
+		actionURL = auth.LoginURL
+	}
+	method := strings.ToUpper(strings.TrimSpace(form.AttrOr("method", "GET")))
+
+	var req *http.Request
+	if method == http.MethodPost {
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, actionURL, strings.NewReader(values.Encode()))
+		if err == nil {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+	} else {
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, actionURL+"?"+values.Encode(), nil)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create login request: %w", err)
+	}
+	req.Header.Set("User-Agent", f.userAgent)
+	req.Header.Set("Referer", auth.LoginURL)
+	f.applyCredentials(req)
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("login request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("login failed: HTTP %d", resp.StatusCode)
+	}
+
+	// Landing on a page that still asks for a password means the credentials were rejected
+	if result, err := goquery.NewDocumentFromReader(resp.Body); err == nil && result.Find("input[type=password]").Length() > 0 {
+		return fmt.Errorf("login failed: %s still shows a login form", resp.Request.URL.String())
+	}
+
+	utils.Info("Logged in", utils.NewField("login_url", auth.LoginURL), utils.NewField("landing_url", resp.Request.URL.String()))
+	return nil
+}
+
+// formValues collects the values a browser would submit for a form without user input
+func formValues(form *goquery.Selection) url.Values {
+	values := url.Values{}
+
+	form.Find("input[name]").Each(func(_ int, input *goquery.Selection) {
+		name, _ := input.Attr("name")
+		switch strings.ToLower(input.AttrOr("type", "text")) {
+		case "submit", "button", "image", "file", "reset":
+			return
+		case "checkbox", "radio":
+			if _, checked := input.Attr("checked"); !checked {
+				return
+			}
+			values.Add(name, input.AttrOr("value", "on"))
+		default:
+			values.Add(name, input.AttrOr("value", ""))
+		}
+	})
+
+	form.Find("select[name]").Each(func(_ int, sel *goquery.Selection) {
+		name, _ := sel.Attr("name")
+		option := sel.Find("option[selected]").First()
+		if option.Length() == 0 {
+			option = sel.Find("option").First()
+		}
+		if option.Length() > 0 {
+			values.Add(name, option.AttrOr("value", strings.TrimSpace(option.Text())))
+		}
+	})
+
+	form.Find("textarea[name]").Each(func(_ int, textarea *goquery.Selection) {
+		name, _ := textarea.Attr("name")
+		values.Add(name, textarea.Text())
+	})
+
+	return values
+}
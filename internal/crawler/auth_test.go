@@ -0,0 +1,107 @@
+package crawler
+
+import (
+	"net/http/cookiejar"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/dillonlara115/barracudaseo/internal/utils"
+)
+
+func TestLoadCookies(t *testing.T) {
+	jar, _ := cookiejar.New(nil)
+	data := "# Netscape HTTP Cookie File\n" +
+		".example.com\tTRUE\t/\tFALSE\t0\tsession\tabc123\n" +
+		"#HttpOnly_example.com\tFALSE\t/\tTRUE\t0\ttoken\tsecret\n"
+
+	count, err := loadCookies(jar, strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("loadCookies() error = %v", err)
+	}
+	if count != 2 {
+		t.Fatalf("loadCookies() count = %d, want 2", count)
+	}
+
+	u, _ := url.Parse("https://www.example.com/")
+	if cookies := jar.Cookies(u); len(cookies) != 1 || cookies[0].Name != "session" {
+		t.Errorf("subdomain cookies = %v, want only session", cookies)
+	}
+
+	if _, err := loadCookies(jar, strings.NewReader("not a cookie line")); err == nil {
+		t.Error("loadCookies() expected error for malformed line")
+	}
+}
+
+func TestFormValues(t *testing.T) {
+	html := `<form>
+		<input type="hidden" name="csrf" value="tok">
+		<input type="text" name="username">
+		<input type="checkbox" name="remember" checked>
+		<input type="checkbox" name="newsletter">
+		<input type="submit" name="go" value="Log in">
+		<select name="lang"><option value="en">English</option><option value="de" selected>Deutsch</option></select>
+	</form>`
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values := formValues(doc.Find("form"))
+	want := map[string]string{"csrf": "tok", "username": "", "remember": "on", "lang": "de"}
+	for name, value := range want {
+		if got := values.Get(name); got != value {
+			t.Errorf("%s = %q, want %q", name, got, value)
+		}
+	}
+	for _, name := range []string{"newsletter", "go"} {
+		if values.Has(name) {
+			t.Errorf("unexpected field %s", name)
+		}
+	}
+}
+
+func TestBrowserHeaders(t *testing.T) {
+	fetcher := NewFetcher(time.Second, "test-agent")
+	auth := utils.AuthConfig{
+		Headers:       map[string]string{"X-Staging-Token": "tok"},
+		Cookies:       ".example.com\tTRUE\t/\tFALSE\t0\tsession\tabc123\n",
+		BasicUsername: "user",
+		BasicPassword: "pass",
+	}
+	if err := fetcher.ConfigureAuth(auth, "https://example.com/"); err != nil {
+		t.Fatalf("ConfigureAuth() error = %v", err)
+	}
+
+	headers, ok := fetcher.browserHeaders("https://www.example.com/app", map[string]string{"Accept": "text/html", "Cookie": "session=fromPage"})
+	if !ok {
+		t.Fatal("browserHeaders() = false for the crawled site")
+	}
+	got := make(map[string]string)
+	for _, header := range headers {
+		got[header["name"]] = header["value"]
+	}
+	want := map[string]string{
+		"Accept":          "text/html",
+		"X-Staging-Token": "tok",
+		"Authorization":   "Basic dXNlcjpwYXNz",
+		"Cookie":          "session=fromPage",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("browserHeaders() = %v, want %v", got, want)
+	}
+
+	headers, _ = fetcher.browserHeaders("https://example.com/", nil)
+	for _, header := range headers {
+		if header["name"] == "Cookie" && header["value"] != "session=abc123" {
+			t.Errorf("Cookie = %q, want the jar's session cookie", header["value"])
+		}
+	}
+
+	if _, ok := fetcher.browserHeaders("https://cdn.other.com/app.js", map[string]string{"Accept": "*/*"}); ok {
+		t.Error("browserHeaders() added credentials for another host")
+	}
+}
@@ -0,0 +1,125 @@
+package crawler
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync/atomic"
+)
+
+// cdpMessage is a Chrome DevTools Protocol command response or event
+type cdpMessage struct {
+	ID        int64           `json:"id,omitempty"`
+	Method    string          `json:"method,omitempty"`
+	SessionID string          `json:"sessionId,omitempty"`
+	Params    json.RawMessage `json:"params,omitempty"`
+	Result    json.RawMessage `json:"result,omitempty"`
+	Error     *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// cdpConn talks to a browser started with --remote-debugging-pipe, which reads
+// NUL-terminated JSON commands from its fd 3 and writes responses and events to fd 4.
+// It is used by a single goroutine: events that arrive while a command is pending are
+// passed to onEvent, which may send further commands with notify.
+type cdpConn struct {
+	w        io.Writer
+	messages chan cdpMessage
+	readErr  error
+	nextID   int64
+	onEvent  func(cdpMessage)
+}
+
+// newCDPConn starts reading messages from r
+func newCDPConn(r io.Reader, w io.Writer) *cdpConn {
+	c := &cdpConn{w: w, messages: make(chan cdpMessage, 64)}
+	go func() {
+		defer close(c.messages)
+		reader := bufio.NewReader(r)
+		for {
+			data, err := reader.ReadBytes(0)
+			if err != nil {
+				c.readErr = err
+				return
+			}
+			var msg cdpMessage
+			if err := json.Unmarshal(data[:len(data)-1], &msg); err != nil {
+				continue
+			}
+			c.messages <- msg
+		}
+	}()
+	return c
+}
+
+// notify sends a command without waiting for its response
+func (c *cdpConn) notify(sessionID, method string, params any) (int64, error) {
+	id := atomic.AddInt64(&c.nextID, 1)
+	data, err := json.Marshal(struct {
+		ID        int64  `json:"id"`
+		SessionID string `json:"sessionId,omitempty"`
+		Method    string `json:"method"`
+		Params    any    `json:"params,omitempty"`
+	}{id, sessionID, method, params})
+	if err != nil {
+		return 0, err
+	}
+	if _, err := c.w.Write(append(data, 0)); err != nil {
+		return 0, fmt.Errorf("devtools %s: %w", method, err)
+	}
+	return id, nil
+}
+
+// call sends a command and decodes its result into result (when not nil)
+func (c *cdpConn) call(sessionID, method string, params, result any) error {
+	id, err := c.notify(sessionID, method, params)
+	if err != nil {
+		return err
+	}
+	for {
+		msg, err := c.next()
+		if err != nil {
+			return fmt.Errorf("devtools %s: %w", method, err)
+		}
+		if msg.ID != id {
+			continue
+		}
+		if msg.Error != nil {
+			return fmt.Errorf("devtools %s: %s", method, msg.Error.Message)
+		}
+		if result == nil {
+			return nil
+		}
+		return json.Unmarshal(msg.Result, result)
+	}
+}
+
+// waitEvent waits for an event, handling any other events on the way
+func (c *cdpConn) waitEvent(sessionID, method string) error {
+	for {
+		msg, err := c.next()
+		if err != nil {
+			return fmt.Errorf("waiting for %s: %w", method, err)
+		}
+		if msg.Method == method && msg.SessionID == sessionID {
+			return nil
+		}
+	}
+}
+
+// next returns the next message, passing events to onEvent
+func (c *cdpConn) next() (cdpMessage, error) {
+	msg, ok := <-c.messages
+	if !ok {
+		if c.readErr == nil || c.readErr == io.EOF {
+			return msg, fmt.Errorf("browser closed the connection")
+		}
+		return msg, c.readErr
+	}
+	if msg.Method != "" && c.onEvent != nil {
+		c.onEvent(msg)
+	}
+	return msg, nil
+}
@@ -0,0 +1,250 @@
+package crawler
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/dillonlara115/barracudaseo/internal/utils"
+	"github.com/dillonlara115/barracudaseo/pkg/models"
+)
+
+const (
+	// checkpointFileName is the name of the checkpoint file inside a state directory
+	checkpointFileName = "checkpoint.json"
+
+	// checkpointVersion is bumped whenever the checkpoint format changes incompatibly
+	checkpointVersion = 2
+
+	// DefaultCheckpointInterval is used when Config.CheckpointInterval is not set
+	DefaultCheckpointInterval = 30 * time.Second
+)
+
+// Checkpoint is a snapshot of crawl state that can be written to disk and resumed later
+type Checkpoint struct {
+	Version        int                             `json:"version"`
+	Config         *utils.Config                   `json:"config"`   // Without credentials; supply them again when resuming
+	Frontier       map[string]crawlTask            `json:"frontier"` // Queued and in-flight tasks by URL
+	Visited        []string                        `json:"visited"`
+	Depths         map[string]int                  `json:"depths"` // URL -> depth at which the page was crawled
+	Results        []*models.PageResult            `json:"results"`
+	Edges          map[string][]string             `json:"edges"`
+	RobotsBlocked  []models.RobotsBlockedURL       `json:"robots_blocked,omitempty"`  // Visited URLs robots.txt disallows
+	SitemapEntries map[string]*models.SitemapEntry `json:"sitemap_entries,omitempty"` // Sitemap metadata by URL
+	Completed      bool                            `json:"completed"`
+	Interrupted    bool                            `json:"interrupted"`
+	SavedAt        time.Time                       `json:"saved_at"`
+}
+
+// CheckpointPath returns the checkpoint file path for a state directory
+func CheckpointPath(stateDir string) string {
+	return filepath.Join(stateDir, checkpointFileName)
+}
+
+// LoadCheckpoint reads the checkpoint stored in a state directory
+func LoadCheckpoint(stateDir string) (*Checkpoint, error) {
+	data, err := os.ReadFile(CheckpointPath(stateDir))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	var checkpoint Checkpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint: %w", err)
+	}
+
+	if checkpoint.Version != checkpointVersion {
+		return nil, fmt.Errorf("unsupported checkpoint version %d (expected %d)", checkpoint.Version, checkpointVersion)
+	}
+	if checkpoint.Config == nil {
+		return nil, fmt.Errorf("checkpoint is missing crawl configuration")
+	}
+
+	return &checkpoint, nil
+}
+
+// Resume restores crawl state from a checkpoint. It must be called before Crawl.
+// Tasks that were queued or in flight when the checkpoint was written are crawled again,
+// and any partial result stored for them is discarded so links are rediscovered.
+func (m *Manager) Resume(checkpoint *Checkpoint) {
+	m.resumeFrom = checkpoint
+}
+
+// restoreCheckpoint seeds manager state from m.resumeFrom and returns the tasks to enqueue
+func (m *Manager) restoreCheckpoint() []crawlTask {
+	checkpoint := m.resumeFrom
+
+	for _, url := range checkpoint.Visited {
+		if _, queued := checkpoint.Frontier[url]; !queued {
+			m.visited.Store(url, true)
+			m.frontier.MarkSeen(url)
+		}
+	}
+	for url, depth := range checkpoint.Depths {
+		m.depths.Store(url, depth)
+	}
+	m.sitemapEntries = checkpoint.SitemapEntries
+
+	m.resultsMu.Lock()
+	for _, result := range checkpoint.Results {
+		if _, queued := checkpoint.Frontier[result.URL]; queued {
+			continue
+		}
+		m.results = append(m.results, result)
+	}
+	restored := len(m.results)
+	m.resultsMu.Unlock()
+
+	m.robotsBlockedMu.Lock()
+	for _, blocked := range checkpoint.RobotsBlocked {
+		if _, queued := checkpoint.Frontier[blocked.URL]; queued {
+			continue // Checked again when the task is retried
+		}
+		m.robotsBlocked = append(m.robotsBlocked, blocked)
+	}
+	m.robotsBlockedMu.Unlock()
+
+	for source, targets := range checkpoint.Edges {
+		m.linkGraph.AddEdges(source, targets)
+	}
+	// Link details are not stored twice; rebuild them from the restored pages
+	for _, result := range checkpoint.Results {
+		if _, queued := checkpoint.Frontier[result.URL]; !queued {
+			m.linkGraph.AddLinks(result.URL, models.LinksOrTargets(result.Links, result.InternalLinks, result.ExternalLinks))
+		}
+	}
+
+	tasks := make([]crawlTask, 0, len(checkpoint.Frontier))
+	for _, task := range checkpoint.Frontier {
+		tasks = append(tasks, task)
+	}
+
+	utils.Info("Resuming crawl from checkpoint",
+		utils.NewField("saved_at", checkpoint.SavedAt),
+		utils.NewField("results", restored),
+		utils.NewField("visited", len(checkpoint.Visited)),
+		utils.NewField("frontier", len(tasks)))
+
+	return tasks
+}
+
+// finishTask marks a processed task as done in the frontier.
+// Tasks interrupted by a shutdown signal stay in flight so checkpoints include them and a
+// resumed crawl retries them.
+func (m *Manager) finishTask(task crawlTask) {
+	if atomic.LoadInt32(&m.interrupted) == 1 {
+		return
+	}
+	m.frontier.Done(task)
+}
+
+// snapshot captures the current crawl state
+func (m *Manager) snapshot(completed bool) (*Checkpoint, error) {
+	frontier, err := m.frontier.Snapshot()
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot frontier: %w", err)
+	}
+
+	// Credentials never go to disk - the checkpoint may outlive the crawl in a shared directory
+	config := *m.config
+	config.Auth = utils.AuthConfig{}
+
+	checkpoint := &Checkpoint{
+		Version:        checkpointVersion,
+		Config:         &config,
+		Frontier:       frontier,
+		Visited:        make([]string, 0),
+		Depths:         make(map[string]int),
+		Edges:          m.linkGraph.GetAllEdges(),
+		SitemapEntries: m.sitemapEntries,
+		Completed:      completed,
+		Interrupted:    atomic.LoadInt32(&m.interrupted) == 1,
+		SavedAt:        time.Now(),
+	}
+
+	m.visited.Range(func(key, _ interface{}) bool {
+		checkpoint.Visited = append(checkpoint.Visited, key.(string))
+		return true
+	})
+	m.depths.Range(func(key, value interface{}) bool {
+		checkpoint.Depths[key.(string)] = value.(int)
+		return true
+	})
+
+	m.resultsMu.Lock()
+	checkpoint.Results = make([]*models.PageResult, len(m.results))
+	copy(checkpoint.Results, m.results)
+	m.resultsMu.Unlock()
+
+	m.robotsBlockedMu.Lock()
+	checkpoint.RobotsBlocked = append([]models.RobotsBlockedURL(nil), m.robotsBlocked...)
+	m.robotsBlockedMu.Unlock()
+
+	return checkpoint, nil
+}
+
+// saveCheckpoint writes the current crawl state to the configured state directory.
+// The file is written to a temporary path and renamed so a crash never leaves a partial checkpoint.
+func (m *Manager) saveCheckpoint(completed bool) error {
+	if m.config.StateDir == "" {
+		return nil
+	}
+
+	m.checkpointMu.Lock()
+	defer m.checkpointMu.Unlock()
+
+	if err := os.MkdirAll(m.config.StateDir, 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	checkpoint, err := m.snapshot(completed)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint: %w", err)
+	}
+
+	path := CheckpointPath(m.config.StateDir)
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to finalize checkpoint: %w", err)
+	}
+
+	utils.Debug("Checkpoint saved",
+		utils.NewField("path", path),
+		utils.NewField("results", len(checkpoint.Results)),
+		utils.NewField("frontier", len(checkpoint.Frontier)),
+		utils.NewField("completed", completed))
+
+	return nil
+}
+
+// checkpointLoop periodically saves checkpoints until the crawl finishes
+func (m *Manager) checkpointLoop(done <-chan struct{}) {
+	interval := m.config.CheckpointInterval
+	if interval <= 0 {
+		interval = DefaultCheckpointInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if err := m.saveCheckpoint(false); err != nil {
+				utils.Warn("Failed to save checkpoint", utils.NewField("error", err.Error()))
+			}
+		}
+	}
+}
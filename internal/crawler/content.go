@@ -0,0 +1,160 @@
+package crawler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// hiddenElements never contribute visible text
+const hiddenElements = "script, style, noscript, template, svg, canvas, iframe, object, [hidden], [aria-hidden='true']"
+
+// boilerplateElements are navigation and other site chrome left out of the main content.
+// Headers and footers are handled separately because inside an article they belong to it.
+const boilerplateElements = "nav, aside, form, dialog, [role='navigation'], [role='complementary'], [role='search'], [role='banner'], [role='contentinfo']"
+
+// inlineElements do not break words, so "<b>bold</b>ness" reads as one word
+var inlineElements = map[string]bool{
+	"a": true, "abbr": true, "b": true, "bdi": true, "bdo": true, "cite": true, "code": true, "data": true,
+	"dfn": true, "em": true, "i": true, "kbd": true, "mark": true, "q": true, "s": true, "samp": true,
+	"small": true, "span": true, "strong": true, "sub": true, "sup": true, "time": true, "u": true, "var": true,
+}
+
+// simHashShingleSize is the number of consecutive words hashed together as one SimHash feature
+const simHashShingleSize = 3
+
+// pageText returns the words of a page's visible body text and of its main content.
+// The main content is the <main> element or single <article> when there is one, otherwise the
+// body, without navigation, sidebars, forms and the site header and footer.
+func pageText(doc *goquery.Document) (visible, main []string) {
+	body := doc.Find("body").First().Clone()
+	body.Find(hiddenElements).Remove()
+	visible = textWords(body)
+
+	root := body.Find("main, [role='main']").First()
+	if root.Length() == 0 {
+		if articles := body.Find("article"); articles.Length() == 1 {
+			root = articles
+		} else {
+			root = body
+		}
+	}
+	root.Find(boilerplateElements).Remove()
+	root.Find("header, footer").Each(func(i int, s *goquery.Selection) {
+		if s.ParentsFiltered("article, main, [role='main']").Length() == 0 {
+			s.Remove()
+		}
+	})
+	return visible, textWords(root)
+}
+
+// textWords returns the whitespace-separated words of the text under a selection,
+// breaking words at block-level elements
+func textWords(s *goquery.Selection) []string {
+	var text strings.Builder
+	var walk func(*goquery.Selection)
+	walk = func(s *goquery.Selection) {
+		s.Contents().Each(func(i int, node *goquery.Selection) {
+			name := goquery.NodeName(node)
+			switch {
+			case name == "#text":
+				text.WriteString(node.Text())
+			case inlineElements[name]:
+				walk(node)
+			default:
+				text.WriteByte(' ')
+				walk(node)
+				text.WriteByte(' ')
+			}
+		})
+	}
+	walk(s)
+	return strings.Fields(text.String())
+}
+
+// countWords counts the words that contain a letter or digit, so separators like "|" are ignored
+func countWords(words []string) int {
+	count := 0
+	for _, word := range words {
+		if strings.IndexFunc(word, isWordRune) >= 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// textRatio returns the length of the visible text as a percentage of the HTML size
+func textRatio(visible []string, htmlSize int) float64 {
+	if htmlSize == 0 || len(visible) == 0 {
+		return 0
+	}
+	textSize := len(visible) - 1 // Spaces between words
+	for _, word := range visible {
+		textSize += len(word)
+	}
+	return math.Round(float64(textSize)/float64(htmlSize)*1000) / 10
+}
+
+// contentFingerprints returns the SHA-256 of the normalized text, which matches exact duplicates
+// regardless of markup, and its 64-bit SimHash in hex, which differs in few bits for near duplicates.
+// Both are empty for pages without text.
+func contentFingerprints(words []string) (textHash, simHash string) {
+	normalized := normalizeWords(words)
+	if len(normalized) == 0 {
+		return "", ""
+	}
+	sum := sha256.Sum256([]byte(strings.Join(normalized, " ")))
+	return hex.EncodeToString(sum[:]), fmt.Sprintf("%016x", simHashWords(normalized))
+}
+
+// normalizeWords lowercases words and strips their punctuation, dropping words without letters or digits
+func normalizeWords(words []string) []string {
+	normalized := make([]string, 0, len(words))
+	for _, word := range words {
+		word = strings.ToLower(strings.TrimFunc(word, func(r rune) bool { return !isWordRune(r) }))
+		if word != "" {
+			normalized = append(normalized, word)
+		}
+	}
+	return normalized
+}
+
+// simHashWords computes a 64-bit SimHash over overlapping word shingles
+func simHashWords(words []string) uint64 {
+	size := simHashShingleSize
+	if len(words) < size {
+		size = len(words)
+	}
+
+	var weights [64]int
+	for i := 0; i+size <= len(words); i++ {
+		hash := fnv.New64a()
+		hash.Write([]byte(strings.Join(words[i:i+size], " ")))
+		feature := hash.Sum64()
+		for bit := 0; bit < 64; bit++ {
+			if feature&(1<<bit) != 0 {
+				weights[bit]++
+			} else {
+				weights[bit]--
+			}
+		}
+	}
+
+	var simHash uint64
+	for bit, weight := range weights {
+		if weight > 0 {
+			simHash |= 1 << bit
+		}
+	}
+	return simHash
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
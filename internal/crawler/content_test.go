@@ -0,0 +1,124 @@
+package crawler
+
+import (
+	"fmt"
+	"math/bits"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestPageText(t *testing.T) {
+	tests := []struct {
+		name        string
+		html        string
+		wantMain    string
+		wantVisible string
+	}{
+		{
+			name: "boilerplate removed",
+			html: `<body><header>Site name</header><nav><a href="/">Home</a> | <a href="/about">About</a></nav>
+<div><h1>Title</h1><p>First <b>bold</b>ness paragraph.</p><p>Second one.</p></div>
+<aside>Related posts</aside><footer>Copyright</footer><script>var x = 1;</script></body>`,
+			wantMain:    "Title First boldness paragraph. Second one.",
+			wantVisible: "Site name Home | About Title First boldness paragraph. Second one. Related posts Copyright",
+		},
+		{
+			name: "main element",
+			html: `<body><div>Banner text</div><main><article><header><h1>Post</h1></header><p>Body text.</p>
+<nav>Previous</nav></article></main><div hidden>Hidden</div></body>`,
+			wantMain:    "Post Body text.",
+			wantVisible: "Banner text Post Body text. Previous",
+		},
+		{
+			name:        "single article",
+			html:        `<body><div>Teaser</div><article><p>Story.</p><footer>By author</footer></article></body>`,
+			wantMain:    "Story. By author",
+			wantVisible: "Teaser Story. By author",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader(tt.html))
+			if err != nil {
+				t.Fatal(err)
+			}
+			visible, main := pageText(doc)
+			if got := strings.Join(main, " "); got != tt.wantMain {
+				t.Errorf("main = %q, want %q", got, tt.wantMain)
+			}
+			if got := strings.Join(visible, " "); got != tt.wantVisible {
+				t.Errorf("visible = %q, want %q", got, tt.wantVisible)
+			}
+		})
+	}
+}
+
+func TestContentFingerprints(t *testing.T) {
+	words := strings.Fields(strings.Repeat("the quick brown fox jumps over the lazy dog near the river bank ", 20))
+	hash, simHash := contentFingerprints(words)
+	if len(hash) != 64 || len(simHash) != 16 {
+		t.Fatalf("contentFingerprints() = %q, %q", hash, simHash)
+	}
+
+	// Case, punctuation and spacing do not change the fingerprints
+	shouted := strings.Fields(strings.ToUpper(strings.Join(words, "  ")) + " !")
+	if h, s := contentFingerprints(shouted); h != hash || s != simHash {
+		t.Errorf("fingerprints changed with case and punctuation: %q, %q", h, s)
+	}
+
+	// A small edit keeps the SimHash close but changes the exact hash
+	edited := append([]string{}, words...)
+	edited[10] = "stream"
+	editedHash, editedSimHash := contentFingerprints(edited)
+	if editedHash == hash {
+		t.Error("text hash did not change after an edit")
+	}
+	if distance := hammingDistanceHex(t, simHash, editedSimHash); distance > 3 {
+		t.Errorf("SimHash distance after a small edit = %d, want <= 3", distance)
+	}
+
+	// Unrelated text is far away
+	_, otherSimHash := contentFingerprints(strings.Fields(strings.Repeat("pricing plans for teams include unlimited projects and priority support ", 20)))
+	if distance := hammingDistanceHex(t, simHash, otherSimHash); distance < 10 {
+		t.Errorf("SimHash distance between unrelated texts = %d, want >= 10", distance)
+	}
+
+	if h, s := contentFingerprints([]string{"|", "—"}); h != "" || s != "" {
+		t.Errorf("contentFingerprints() without words = %q, %q, want empty", h, s)
+	}
+}
+
+func TestParserContentMetrics(t *testing.T) {
+	parser, err := NewParser("https://example.com/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := `<html><head><title>T</title><style>body { color: red; }</style></head>
+<body><nav>Menu</nav><p>One two three four five.</p></body></html>`
+	result, err := parser.Parse([]byte(html))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.WordCount != 5 {
+		t.Errorf("WordCount = %d, want 5", result.WordCount)
+	}
+	// "Menu One two three four five." is 29 bytes
+	if want := float64(int(29.0/float64(len(html))*1000+0.5)) / 10; result.TextRatio != want {
+		t.Errorf("TextRatio = %v, want %v", result.TextRatio, want)
+	}
+	if result.TextHash == "" || result.SimHash == "" {
+		t.Errorf("expected fingerprints, got %q and %q", result.TextHash, result.SimHash)
+	}
+}
+
+func hammingDistanceHex(t *testing.T, a, b string) int {
+	t.Helper()
+	var x, y uint64
+	if _, err := fmt.Sscanf(a+" "+b, "%x %x", &x, &y); err != nil {
+		t.Fatal(err)
+	}
+	return bits.OnesCount64(x ^ y)
+}
@@ -1,6 +1,10 @@
 package crawler
 
 import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"net/http"
@@ -15,6 +19,14 @@ import (
 type Fetcher struct {
 	client    *http.Client
 	userAgent string
+	throttle  *HostThrottle // Optional per-host rate limiter
+	tls       *tlsRecorder  // TLS details per host, shared by copies of the fetcher
+
+	// Credentials for the crawled site (see ConfigureAuth)
+	authDomain    string
+	headers       map[string]string
+	basicUsername string
+	basicPassword string
 }
 
 // FetchResult contains the fetched page data
@@ -22,12 +34,34 @@ type FetchResult struct {
 	PageResult *models.PageResult
 	Body       []byte
 	Error      error
+	RetryAfter time.Duration // Parsed Retry-After header, if any
+}
+
+// Validators are cache validators from a previous crawl, sent as conditional request headers
+type Validators struct {
+	ETag         string
+	LastModified string
+}
+
+// IsZero reports whether there is nothing to validate against
+func (v *Validators) IsZero() bool {
+	return v == nil || (v.ETag == "" && v.LastModified == "")
+}
+
+// NotModified reports whether the server answered a conditional request with 304 Not Modified
+func (r *FetchResult) NotModified() bool {
+	return r.Error == nil && r.PageResult.StatusCode == http.StatusNotModified
 }
 
 // NewFetcher creates a new Fetcher instance
 func NewFetcher(timeout time.Duration, userAgent string) *Fetcher {
+	// Accept TLS 1.0 and 1.1 so sites still on them can be crawled and reported
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS10}
+
 	client := &http.Client{
-		Timeout: timeout,
+		Timeout:   timeout,
+		Transport: transport,
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
 			// Follow redirects up to 10 times
 			if len(via) >= 10 {
@@ -40,11 +74,84 @@ func NewFetcher(timeout time.Duration, userAgent string) *Fetcher {
 	return &Fetcher{
 		client:    client,
 		userAgent: userAgent,
+		tls:       newTLSRecorder(),
+	}
+}
+
+// WithUserAgent returns a copy of the fetcher that sends a different User-Agent.
+// The copy shares the HTTP client (and its cookies), throttle and credentials.
+func (f *Fetcher) WithUserAgent(userAgent string) *Fetcher {
+	clone := *f
+	clone.userAgent = userAgent
+	return &clone
+}
+
+// SetThrottle makes every request wait for, and report back to, a per-host throttle
+func (f *Fetcher) SetThrottle(throttle *HostThrottle) {
+	f.throttle = throttle
+}
+
+// Client returns an HTTP client that sends requests the way the fetcher does: with its
+// User-Agent, the credentials and cookies for the crawled site, and the per-host throttle.
+// It is used for requests made outside the crawl, such as subresource checks.
+func (f *Fetcher) Client() *http.Client {
+	client := *f.client
+	client.Transport = &fetcherTransport{fetcher: f, base: f.client.Transport}
+	return &client
+}
+
+// fetcherTransport applies a fetcher's request settings to each request, including redirects
+type fetcherTransport struct {
+	fetcher *Fetcher
+	base    http.RoundTripper
+}
+
+func (t *fetcherTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	targetURL := req.URL.String()
+	if t.fetcher.throttle != nil {
+		if err := t.fetcher.throttle.Wait(req.Context(), targetURL); err != nil {
+			return nil, fmt.Errorf("request cancelled: %w", err)
+		}
+	}
+
+	req = req.Clone(req.Context())
+	if req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", t.fetcher.userAgent)
+	}
+	t.fetcher.applyCredentials(req)
+
+	startTime := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	if err == nil && t.fetcher.throttle != nil {
+		t.fetcher.throttle.Observe(targetURL, resp.StatusCode, time.Since(startTime), parseRetryAfter(resp.Header.Get("Retry-After")))
 	}
+	return resp, err
 }
 
 // Fetch retrieves a URL and returns the response (single attempt, no retry)
 func (f *Fetcher) Fetch(url string) *FetchResult {
+	return f.FetchContext(context.Background(), url)
+}
+
+// FetchContext retrieves a URL (single attempt, no retry), aborting when ctx is done
+func (f *Fetcher) FetchContext(ctx context.Context, url string) *FetchResult {
+	return f.FetchConditional(ctx, url, nil)
+}
+
+// FetchConditional retrieves a URL like FetchContext. When validators are given the request is
+// conditional, and a 304 Not Modified response is returned without an error or body.
+func (f *Fetcher) FetchConditional(ctx context.Context, url string, validators *Validators) *FetchResult {
+	return f.fetch(ctx, url, validators, true)
+}
+
+// FetchResource retrieves a non-page resource such as a sitemap, reading the body whatever its Content-Type
+func (f *Fetcher) FetchResource(ctx context.Context, url string) *FetchResult {
+	return f.fetch(ctx, url, nil, false)
+}
+
+// fetch performs a single request. With pagesOnly set, images, PDFs and other non-HTML
+// responses are skipped without reading the body.
+func (f *Fetcher) fetch(ctx context.Context, url string, validators *Validators, pagesOnly bool) *FetchResult {
 	result := &FetchResult{
 		PageResult: &models.PageResult{
 			URL:       url,
@@ -52,52 +159,20 @@ func (f *Fetcher) Fetch(url string) *FetchResult {
 		},
 	}
 
-	startTime := time.Now()
-
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		result.Error = fmt.Errorf("failed to create request: %w", err)
-		result.PageResult.Error = result.Error.Error()
-		return result
-	}
-
-	req.Header.Set("User-Agent", f.userAgent)
-	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
-
-	// Track redirect chain using CheckRedirect callback
-	// CheckRedirect is called when the HTTP client encounters a redirect response
-	var redirectChain []string
-	originalCheckRedirect := f.client.CheckRedirect
-
-	// Temporarily override CheckRedirect to capture redirect URLs
-	f.client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
-		// When CheckRedirect is called:
-		// - 'via' contains all previous requests (via[0] = original request)
-		// - 'req' is the NEW request about to be made to follow the redirect
-		// - The redirect response came from the last request in 'via'
-		//
-		// We want to capture the redirect destinations (the URLs we're redirecting TO)
-		// Each time CheckRedirect is called, we're following a redirect, so we capture req.URL
-		if len(via) > 0 {
-			// This is a redirect - capture the destination URL
-			redirectChain = append(redirectChain, req.URL.String())
-		}
-
-		// Follow redirects up to 10 times
-		if len(via) >= 10 {
-			return fmt.Errorf("stopped after 10 redirects")
+	if f.throttle != nil {
+		if err := f.throttle.Wait(ctx, url); err != nil {
+			result.Error = fmt.Errorf("request cancelled: %w", err)
+			result.PageResult.Error = result.Error.Error()
+			return result
 		}
-		return nil
 	}
 
-	resp, err := f.client.Do(req)
+	startTime := time.Now()
+	resp, err := f.followRedirects(ctx, url, validators, result.PageResult)
 	responseTime := time.Since(startTime)
 
-	// Restore original CheckRedirect
-	f.client.CheckRedirect = originalCheckRedirect
-
 	if err != nil {
-		result.Error = fmt.Errorf("request failed: %w", err)
+		result.Error = err
 		result.PageResult.Error = result.Error.Error()
 		result.PageResult.ResponseTime = responseTime.Milliseconds()
 		return result
@@ -106,6 +181,11 @@ func (f *Fetcher) Fetch(url string) *FetchResult {
 
 	result.PageResult.StatusCode = resp.StatusCode
 	result.PageResult.ResponseTime = responseTime.Milliseconds()
+	result.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+
+	if f.throttle != nil {
+		f.throttle.Observe(url, resp.StatusCode, responseTime, result.RetryAfter)
+	}
 
 	// Extract x-robots-tag header for indexability detection
 	xRobotsTag := resp.Header.Get("X-Robots-Tag")
@@ -113,16 +193,22 @@ func (f *Fetcher) Fetch(url string) *FetchResult {
 		result.PageResult.XRobotsTag = xRobotsTag
 	}
 
-	// Only add redirect chain if we actually had redirects (status code indicates redirects were followed)
-	// If the final status is 3xx, it means we hit a redirect that wasn't followed, or
-	// if we have redirectChain entries, we followed redirects
-	if len(redirectChain) > 0 {
-		result.PageResult.RedirectChain = redirectChain
+	// Keep validators for conditional requests on the next crawl
+	result.PageResult.ETag = resp.Header.Get("ETag")
+	result.PageResult.LastModified = resp.Header.Get("Last-Modified")
+	result.PageResult.Headers = responseHeaders(resp)
+	if link := result.PageResult.Headers.Link; link != "" {
+		result.PageResult.Hreflang = linkHeaderHreflang(link, resp.Request.URL)
+	}
+
+	// Not modified since the previous crawl - there is no body to read
+	if resp.StatusCode == http.StatusNotModified && !validators.IsZero() {
+		return result
 	}
 
 	// Check Content-Type header - skip non-HTML content (images, PDFs, etc.)
 	contentType := resp.Header.Get("Content-Type")
-	if contentType != "" {
+	if pagesOnly && contentType != "" {
 		contentTypeLower := strings.ToLower(contentType)
 		// Skip image content types
 		if strings.HasPrefix(contentTypeLower, "image/") {
@@ -142,7 +228,11 @@ func (f *Fetcher) Fetch(url string) *FetchResult {
 	}
 
 	// Read body
+	readStart := time.Now()
 	body, err := io.ReadAll(resp.Body)
+	if result.PageResult.Timing != nil {
+		result.PageResult.Timing.DownloadMs = time.Since(readStart).Milliseconds()
+	}
 	if err != nil {
 		result.Error = fmt.Errorf("failed to read response body: %w", err)
 		result.PageResult.Error = result.Error.Error()
@@ -150,6 +240,9 @@ func (f *Fetcher) Fetch(url string) *FetchResult {
 	}
 
 	result.Body = body
+	result.PageResult.Size = int64(len(body))
+	hash := sha256.Sum256(body)
+	result.PageResult.ContentHash = hex.EncodeToString(hash[:])
 
 	// Handle non-2xx status codes
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
@@ -160,15 +253,125 @@ func (f *Fetcher) Fetch(url string) *FetchResult {
 	return result
 }
 
+// maxRedirects is the number of redirects followed before giving up
+const maxRedirects = 10
+
+// newRequest builds a GET request with the crawler's headers, credentials and conditional validators
+func (f *Fetcher) newRequest(ctx context.Context, url string, validators *Validators) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("User-Agent", f.userAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
+	f.applyCredentials(req)
+	if !validators.IsZero() {
+		if validators.ETag != "" {
+			req.Header.Set("If-None-Match", validators.ETag)
+		}
+		if validators.LastModified != "" {
+			req.Header.Set("If-Modified-Since", validators.LastModified)
+		}
+	}
+	return req, nil
+}
+
+// followRedirects requests url and follows redirects one hop at a time, recording each hop
+// (and the redirect chain) and the timing of the last request on page. Redirects are followed by hand with a per-call copy of the
+// client that does not follow them itself, so concurrent fetches never touch shared state.
+// On a loop or too many redirects page.StatusCode is the last redirect status and an error is returned.
+// Validators only apply to url itself, so they are sent with the first request only.
+func (f *Fetcher) followRedirects(ctx context.Context, url string, validators *Validators, page *models.PageResult) (*http.Response, error) {
+	client := *f.client
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	visited := map[string]bool{url: true}
+	current := url
+	for {
+		req, err := f.newRequest(ctx, current, validators)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		trace, traceCtx := newRequestTrace(req.Context())
+		req = req.WithContext(traceCtx)
+		resp, err := client.Do(req)
+		if err != nil {
+			if req.URL.Scheme == "https" && isCertificateError(err) {
+				page.TLS = f.tls.probe(ctx, req.URL.Host, err)
+			}
+			return nil, fmt.Errorf("request failed: %w", err)
+		}
+		if resp.TLS != nil {
+			page.TLS = f.tls.record(req.URL.Hostname(), resp.TLS)
+		} else {
+			page.TLS = nil
+		}
+
+		page.Timing = trace.timing()
+
+		location := resp.Header.Get("Location")
+		if !isRedirectStatus(resp.StatusCode) || location == "" {
+			return resp, nil
+		}
+
+		// Drain a little of the redirect body so the connection can be reused
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+		resp.Body.Close()
+
+		target, err := resp.Request.URL.Parse(location)
+		if err != nil {
+			page.StatusCode = resp.StatusCode
+			return nil, fmt.Errorf("invalid redirect Location %q: %w", location, err)
+		}
+		next := target.String()
+		page.RedirectHops = append(page.RedirectHops, models.RedirectHop{
+			URL:        current,
+			StatusCode: resp.StatusCode,
+			Location:   location,
+			Target:     next,
+			DurationMs: time.Since(trace.start).Milliseconds(),
+		})
+		page.RedirectChain = append(page.RedirectChain, next)
+
+		if visited[next] {
+			page.StatusCode = resp.StatusCode
+			page.RedirectLoop = true
+			return nil, fmt.Errorf("redirect loop: %s -> %s", url, strings.Join(page.RedirectChain, " -> "))
+		}
+		if len(page.RedirectHops) >= maxRedirects {
+			page.StatusCode = resp.StatusCode
+			page.TooManyRedirects = true
+			return nil, fmt.Errorf("stopped after %d redirects", maxRedirects)
+		}
+		visited[next] = true
+		current = next
+		validators = nil
+	}
+}
+
+// isRedirectStatus reports whether a status code is a redirect the crawler follows
+func isRedirectStatus(code int) bool {
+	switch code {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return true
+	}
+	return false
+}
+
 // isRetryableError checks if an error is retryable
 func isRetryableError(result *FetchResult) bool {
 	if result.Error == nil {
 		return false
 	}
 
-	// Retry on 5xx errors, timeouts, and connection errors
+	// Retry on 429, 5xx errors, timeouts, and connection errors
 	statusCode := result.PageResult.StatusCode
-	if statusCode >= 500 && statusCode < 600 {
+	if statusCode == 429 || (statusCode >= 500 && statusCode < 600) {
 		return true
 	}
 
@@ -191,22 +394,39 @@ func containsAny(s string, substrings []string) bool {
 	return false
 }
 
-// FetchWithRetry retrieves a URL with retry logic for transient errors
-func (f *Fetcher) FetchWithRetry(url string, maxRetries int) *FetchResult {
+// FetchWithRetry retrieves a URL with retry logic for transient errors, sending conditional
+// headers when validators are given. Backoff waits honour Retry-After and return early when ctx is done.
+func (f *Fetcher) FetchWithRetry(ctx context.Context, url string, validators *Validators, maxRetries int) *FetchResult {
 	var lastResult *FetchResult
 
 	for attempt := 0; attempt <= maxRetries; attempt++ {
 		if attempt > 0 {
-			// Exponential backoff: wait 2^attempt seconds
+			// Exponential backoff: wait 2^(attempt-1) seconds, or longer if the server asked to
 			backoff := time.Duration(1<<uint(attempt-1)) * time.Second
-			time.Sleep(backoff)
+			if lastResult.RetryAfter > backoff {
+				backoff = lastResult.RetryAfter
+			}
+			if backoff > throttleMaxDelay {
+				utils.Debug("Retry-After too long, giving up",
+					utils.NewField("url", url),
+					utils.NewField("retry_after", lastResult.RetryAfter.String()))
+				return lastResult
+			}
+
+			timer := time.NewTimer(backoff)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return lastResult
+			case <-timer.C:
+			}
 		}
 
-		result := f.Fetch(url)
+		result := f.FetchConditional(ctx, url, validators)
 		lastResult = result
 
 		// If successful or not retryable, return immediately
-		if result.Error == nil || !isRetryableError(result) {
+		if result.Error == nil || !isRetryableError(result) || ctx.Err() != nil {
 			return result
 		}
 
@@ -0,0 +1,166 @@
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dillonlara115/barracudaseo/internal/utils"
+)
+
+func TestFetchRecordsRedirectHops(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/old", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/moved", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/moved", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/final", http.StatusFound)
+	})
+	mux.HandleFunc("/final", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html><title>Final</title></html>"))
+	})
+	mux.HandleFunc("/loop-a", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/loop-b", http.StatusTemporaryRedirect)
+	})
+	mux.HandleFunc("/loop-b", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/loop-a", http.StatusTemporaryRedirect)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	fetcher := NewFetcher(5*time.Second, "test-agent")
+
+	result := fetcher.Fetch(server.URL + "/old")
+	if result.Error != nil {
+		t.Fatalf("Fetch() error = %v", result.Error)
+	}
+	page := result.PageResult
+	if page.StatusCode != 200 || !strings.Contains(string(result.Body), "Final") {
+		t.Errorf("expected the final page, got HTTP %d %q", page.StatusCode, result.Body)
+	}
+	if len(page.RedirectHops) != 2 {
+		t.Fatalf("expected 2 hops, got %+v", page.RedirectHops)
+	}
+	first, second := page.RedirectHops[0], page.RedirectHops[1]
+	if first.URL != server.URL+"/old" || first.StatusCode != 301 || first.Location != "/moved" || first.Target != server.URL+"/moved" {
+		t.Errorf("unexpected first hop %+v", first)
+	}
+	if second.StatusCode != 302 || second.Target != server.URL+"/final" {
+		t.Errorf("unexpected second hop %+v", second)
+	}
+	if strings.Join(page.RedirectChain, ",") != server.URL+"/moved,"+server.URL+"/final" {
+		t.Errorf("unexpected redirect chain %v", page.RedirectChain)
+	}
+
+	result = fetcher.Fetch(server.URL + "/loop-a")
+	if result.Error == nil || !result.PageResult.RedirectLoop {
+		t.Fatalf("expected a redirect loop, got error %v, loop %v", result.Error, result.PageResult.RedirectLoop)
+	}
+	if result.PageResult.StatusCode != 307 || len(result.PageResult.RedirectHops) != 2 {
+		t.Errorf("expected 2 hops ending in 307, got HTTP %d %+v", result.PageResult.StatusCode, result.PageResult.RedirectHops)
+	}
+}
+
+func TestFetchStopsAfterTooManyRedirects(t *testing.T) {
+	var conditional []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") != "" || r.Header.Get("If-Modified-Since") != "" {
+			conditional = append(conditional, r.URL.Path)
+		}
+		var n int
+		fmt.Sscanf(r.URL.Path, "/hop/%d", &n)
+		http.Redirect(w, r, fmt.Sprintf("/hop/%d", n+1), http.StatusMovedPermanently)
+	}))
+	defer server.Close()
+
+	fetcher := NewFetcher(5*time.Second, "test-agent")
+	validators := &Validators{ETag: `"v1"`, LastModified: "Mon, 02 Jan 2006 15:04:05 GMT"}
+	result := fetcher.FetchConditional(context.Background(), server.URL+"/hop/0", validators)
+	page := result.PageResult
+	if result.Error == nil || !page.TooManyRedirects || page.RedirectLoop {
+		t.Fatalf("expected too many redirects, got error %v, too many %v, loop %v", result.Error, page.TooManyRedirects, page.RedirectLoop)
+	}
+	if page.StatusCode != http.StatusMovedPermanently || len(page.RedirectHops) != maxRedirects {
+		t.Errorf("expected %d hops ending in 301, got HTTP %d with %d hops", maxRedirects, page.StatusCode, len(page.RedirectHops))
+	}
+	if len(conditional) != 1 || conditional[0] != "/hop/0" {
+		t.Errorf("validators sent to %v, want only the first request", conditional)
+	}
+}
+
+func TestConcurrentFetchesKeepSeparateRedirectChains(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/r/") {
+			http.Redirect(w, r, "/page/"+strings.TrimPrefix(r.URL.Path, "/r/"), http.StatusMovedPermanently)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html></html>"))
+	}))
+	defer server.Close()
+
+	fetcher := NewFetcher(5*time.Second, "test-agent")
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			result := fetcher.Fetch(server.URL + "/r/" + id)
+			if chain := result.PageResult.RedirectChain; len(chain) != 1 || chain[0] != server.URL+"/page/"+id {
+				t.Errorf("fetch %s got redirect chain %v", id, chain)
+			}
+		}(strings.Repeat("x", i+1))
+	}
+	wg.Wait()
+}
+
+func TestFetcherClientUsesCrawlSettings(t *testing.T) {
+	var mu sync.Mutex
+	var requests []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, time.Now())
+		mu.Unlock()
+		if user, password, _ := r.BasicAuth(); user != "staging" || password != "secret" || r.Header.Get("X-Token") != "abc" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.UserAgent() != "TestBot/1.0" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write([]byte("body { color: red }"))
+	}))
+	defer server.Close()
+
+	fetcher := NewFetcher(5*time.Second, "TestBot/1.0")
+	fetcher.SetThrottle(NewHostThrottle(50 * time.Millisecond))
+	auth := utils.AuthConfig{BasicUsername: "staging", BasicPassword: "secret", Headers: map[string]string{"X-Token": "abc"}}
+	if err := fetcher.ConfigureAuth(auth, server.URL); err != nil {
+		t.Fatalf("ConfigureAuth() error = %v", err)
+	}
+
+	client := fetcher.Client()
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(server.URL + "/site.css")
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Get() status = %d, want 200 with the crawl's credentials and User-Agent", resp.StatusCode)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if gap := requests[1].Sub(requests[0]); gap < 40*time.Millisecond {
+		t.Errorf("requests %v apart, want the throttle's 50ms delay", gap)
+	}
+}
@@ -0,0 +1,409 @@
+package crawler
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/dillonlara115/barracudaseo/internal/utils"
+)
+
+const (
+	// CrawlOrderBFS crawls shallower pages first
+	CrawlOrderBFS = "bfs"
+	// CrawlOrderDFS crawls deeper pages first
+	CrawlOrderDFS = "dfs"
+	// CrawlOrderSitemapFirst crawls every sitemap URL before discovered links, then BFS
+	CrawlOrderSitemapFirst = "sitemap-first"
+
+	// DefaultFrontierMemoryLimit is the number of queued tasks held in memory before spilling to disk
+	DefaultFrontierMemoryLimit = 50000
+
+	// spillReadChunk is how many tasks are loaded back from disk at a time
+	spillReadChunk = 1000
+)
+
+// PriorityFunc maps a task to its priority bucket. Lower values are crawled first.
+type PriorityFunc func(task crawlTask) int
+
+// PriorityFor returns the priority function for a crawl order
+func PriorityFor(order string) (PriorityFunc, error) {
+	switch order {
+	case "", CrawlOrderBFS:
+		return func(task crawlTask) int { return task.Depth }, nil
+	case CrawlOrderDFS:
+		return func(task crawlTask) int { return -task.Depth }, nil
+	case CrawlOrderSitemapFirst:
+		return func(task crawlTask) int {
+			if task.FromSitemap {
+				return 0
+			}
+			return task.Depth + 1
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported crawl order: %s", order)
+	}
+}
+
+// frontierBucket holds tasks of one priority in FIFO order.
+// Tasks beyond the in-memory limit are appended to a spill file and read back in order.
+type frontierBucket struct {
+	mem        []crawlTask
+	spillPath  string
+	spillFile  *os.File      // Append handle
+	readFile   *os.File      // Read handle, opened when tasks are first loaded back
+	spillRead  *bufio.Reader // Buffered reader over readFile
+	spillCount int           // Tasks on disk not yet read back
+}
+
+// Frontier is an unbounded, priority-ordered queue of crawl tasks. It never drops URLs:
+// once the in-memory limit is reached new tasks spill to disk. It also tracks in-flight
+// tasks so Pop can report exactly when the crawl has no work left.
+type Frontier struct {
+	mu       sync.Mutex
+	cond     *sync.Cond
+	priority PriorityFunc
+	buckets  map[int]*frontierBucket
+	seen     map[string]bool      // URLs ever pushed (or marked seen), used for deduplication
+	inFlight map[string]crawlTask // Tasks handed out by Pop and not yet marked Done
+	memCount int
+	memLimit int
+	spillDir string
+	tempDir  bool // spillDir was created by the frontier and is removed on Close
+	closed   bool
+	err      error // Spill read errors; the tasks of the affected buckets were lost
+}
+
+// NewFrontier creates a new Frontier. spillDir may be empty, in which case a temporary
+// directory is created the first time tasks spill to disk.
+func NewFrontier(priority PriorityFunc, memLimit int, spillDir string) *Frontier {
+	if memLimit <= 0 {
+		memLimit = DefaultFrontierMemoryLimit
+	}
+
+	f := &Frontier{
+		priority: priority,
+		buckets:  make(map[int]*frontierBucket),
+		seen:     make(map[string]bool),
+		inFlight: make(map[string]crawlTask),
+		memLimit: memLimit,
+		spillDir: spillDir,
+	}
+	f.cond = sync.NewCond(&f.mu)
+	return f
+}
+
+// Push adds a task unless its URL was already pushed. It returns true if the task was added.
+func (f *Frontier) Push(task crawlTask) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed || f.seen[task.URL] {
+		return false, nil
+	}
+
+	bucket := f.bucket(f.priority(task))
+
+	// Keep FIFO order: once a bucket has spilled, later tasks go to disk behind it
+	if f.memCount >= f.memLimit || bucket.spillCount > 0 {
+		if err := f.spill(bucket, task); err != nil {
+			return false, err
+		}
+	} else {
+		bucket.mem = append(bucket.mem, task)
+		f.memCount++
+	}
+
+	f.seen[task.URL] = true
+	f.cond.Signal()
+	return true, nil
+}
+
+// MarkSeen records URLs that must never be queued (e.g. already crawled before a resume)
+func (f *Frontier) MarkSeen(url string) {
+	f.mu.Lock()
+	f.seen[url] = true
+	f.mu.Unlock()
+}
+
+// Pop returns the highest-priority task, blocking until one is available.
+// It returns false once the frontier is empty with nothing in flight, or when ctx is done.
+func (f *Frontier) Pop(ctx context.Context) (crawlTask, bool) {
+	// Wake waiters when the context is cancelled
+	stop := context.AfterFunc(ctx, func() {
+		f.mu.Lock()
+		f.cond.Broadcast()
+		f.mu.Unlock()
+	})
+	defer stop()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for {
+		if ctx.Err() != nil || f.closed {
+			return crawlTask{}, false
+		}
+
+		task, ok := f.next()
+		if ok {
+			f.inFlight[task.URL] = task
+			return task, true
+		}
+
+		// Nothing queued and nothing in flight means no task can produce more work
+		if len(f.inFlight) == 0 {
+			f.cond.Broadcast()
+			return crawlTask{}, false
+		}
+
+		f.cond.Wait()
+	}
+}
+
+// Done marks a task returned by Pop as fully processed
+func (f *Frontier) Done(task crawlTask) {
+	f.mu.Lock()
+	delete(f.inFlight, task.URL)
+	f.cond.Broadcast()
+	f.mu.Unlock()
+}
+
+// Len returns the number of queued tasks (in memory and on disk), excluding in-flight tasks
+func (f *Frontier) Len() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	count := f.memCount
+	for _, bucket := range f.buckets {
+		count += bucket.spillCount
+	}
+	return count
+}
+
+// Err returns the errors that made the frontier drop queued tasks, if any
+func (f *Frontier) Err() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.err
+}
+
+// Snapshot returns queued and in-flight tasks by URL
+func (f *Frontier) Snapshot() (map[string]crawlTask, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	snapshot := make(map[string]crawlTask, f.memCount+len(f.inFlight))
+	for url, task := range f.inFlight {
+		snapshot[url] = task
+	}
+	for _, bucket := range f.buckets {
+		for _, task := range bucket.mem {
+			snapshot[task.URL] = task
+		}
+		if bucket.spillCount > 0 {
+			spilled, err := f.peekSpill(bucket)
+			if err != nil {
+				return nil, err
+			}
+			for _, task := range spilled {
+				snapshot[task.URL] = task
+			}
+		}
+	}
+	return snapshot, nil
+}
+
+// Close releases spill files and wakes any blocked Pop calls
+func (f *Frontier) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.closed = true
+	f.cond.Broadcast()
+
+	for _, bucket := range f.buckets {
+		bucket.closeSpill()
+	}
+	if f.tempDir {
+		return os.RemoveAll(f.spillDir)
+	}
+	return nil
+}
+
+// closeSpill closes and removes the bucket's spill file, if any
+func (b *frontierBucket) closeSpill() {
+	if b.readFile != nil {
+		b.readFile.Close()
+	}
+	if b.spillFile != nil {
+		b.spillFile.Close()
+		os.Remove(b.spillPath)
+	}
+	b.spillFile = nil
+	b.readFile = nil
+	b.spillRead = nil
+	b.spillPath = ""
+}
+
+// bucket returns the bucket for a priority, creating it if needed. Caller holds f.mu.
+func (f *Frontier) bucket(priority int) *frontierBucket {
+	bucket, ok := f.buckets[priority]
+	if !ok {
+		bucket = &frontierBucket{}
+		f.buckets[priority] = bucket
+	}
+	return bucket
+}
+
+// next removes the next task in priority order. Caller holds f.mu.
+func (f *Frontier) next() (crawlTask, bool) {
+	priorities := make([]int, 0, len(f.buckets))
+	for priority := range f.buckets {
+		priorities = append(priorities, priority)
+	}
+	sort.Ints(priorities)
+
+	for _, priority := range priorities {
+		bucket := f.buckets[priority]
+		if len(bucket.mem) == 0 && bucket.spillCount > 0 {
+			if err := f.loadSpill(bucket); err != nil {
+				f.dropSpill(bucket, err)
+			}
+		}
+		if len(bucket.mem) == 0 {
+			continue
+		}
+
+		task := bucket.mem[0]
+		bucket.mem = bucket.mem[1:]
+		f.memCount--
+		return task, true
+	}
+
+	return crawlTask{}, false
+}
+
+// dropSpill gives up on a bucket's spill file after a read error so the rest of the
+// frontier keeps going. The lost tasks are logged and reported by Err. Caller holds f.mu.
+func (f *Frontier) dropSpill(bucket *frontierBucket, err error) {
+	utils.Error("Dropping unreadable frontier spill file",
+		utils.NewField("path", bucket.spillPath),
+		utils.NewField("lost_tasks", bucket.spillCount),
+		utils.NewField("error", err.Error()))
+
+	f.err = errors.Join(f.err, fmt.Errorf("lost %d queued URLs: %w", bucket.spillCount, err))
+	bucket.spillCount = 0
+	bucket.closeSpill()
+}
+
+// spill appends a task to the bucket's spill file. Caller holds f.mu.
+func (f *Frontier) spill(bucket *frontierBucket, task crawlTask) error {
+	if bucket.spillFile == nil {
+		if f.spillDir == "" {
+			dir, err := os.MkdirTemp("", "barracuda-frontier-")
+			if err != nil {
+				return fmt.Errorf("failed to create frontier spill directory: %w", err)
+			}
+			f.spillDir = dir
+			f.tempDir = true
+		}
+		if err := os.MkdirAll(f.spillDir, 0755); err != nil {
+			return fmt.Errorf("failed to create frontier spill directory: %w", err)
+		}
+
+		file, err := os.CreateTemp(f.spillDir, "frontier-*.jsonl")
+		if err != nil {
+			return fmt.Errorf("failed to create frontier spill file: %w", err)
+		}
+		bucket.spillFile = file
+		bucket.spillPath = file.Name()
+	}
+
+	data, err := json.Marshal(task)
+	if err != nil {
+		return fmt.Errorf("failed to encode frontier task: %w", err)
+	}
+	data = append(data, '\n')
+
+	// Writes always go to the end; reads use a separate handle (readFile)
+	if _, err := bucket.spillFile.Write(data); err != nil {
+		return fmt.Errorf("failed to write frontier spill file: %w", err)
+	}
+	bucket.spillCount++
+	return nil
+}
+
+// loadSpill reads the next chunk of spilled tasks back into memory. Caller holds f.mu.
+func (f *Frontier) loadSpill(bucket *frontierBucket) error {
+	if bucket.spillRead == nil {
+		reader, err := os.Open(bucket.spillPath)
+		if err != nil {
+			return fmt.Errorf("failed to open frontier spill file: %w", err)
+		}
+		bucket.readFile = reader
+		bucket.spillRead = bufio.NewReader(reader)
+	}
+
+	for i := 0; i < spillReadChunk && bucket.spillCount > 0; i++ {
+		line, err := bucket.spillRead.ReadBytes('\n')
+		if err != nil {
+			return fmt.Errorf("failed to read frontier spill file: %w", err)
+		}
+		var task crawlTask
+		if err := json.Unmarshal(line, &task); err != nil {
+			return fmt.Errorf("failed to decode frontier task: %w", err)
+		}
+		bucket.mem = append(bucket.mem, task)
+		bucket.spillCount--
+		f.memCount++
+	}
+
+	// Fully drained: drop the file so the bucket can take in-memory tasks again
+	if bucket.spillCount == 0 {
+		bucket.closeSpill()
+	}
+	return nil
+}
+
+// peekSpill reads the unread spilled tasks of a bucket without consuming them. Caller holds f.mu.
+func (f *Frontier) peekSpill(bucket *frontierBucket) ([]crawlTask, error) {
+	file, err := os.Open(bucket.spillPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open frontier spill file: %w", err)
+	}
+	defer file.Close()
+
+	var all []crawlTask
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var task crawlTask
+		if err := json.Unmarshal(scanner.Bytes(), &task); err != nil {
+			return nil, fmt.Errorf("failed to decode frontier task: %w", err)
+		}
+		all = append(all, task)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read frontier spill file: %w", err)
+	}
+
+	// Only the last spillCount entries are still queued
+	if len(all) > bucket.spillCount {
+		all = all[len(all)-bucket.spillCount:]
+	}
+	return all, nil
+}
+
+// spillDirFor returns the directory used for frontier spill files under a state directory
+func spillDirFor(stateDir string) string {
+	if stateDir == "" {
+		return ""
+	}
+	return filepath.Join(stateDir, "frontier")
+}
@@ -0,0 +1,182 @@
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestFrontierOrder(t *testing.T) {
+	tasks := []crawlTask{
+		{URL: "https://example.com/deep", Depth: 2},
+		{URL: "https://example.com/a", Depth: 1},
+		{URL: "https://example.com/sitemap", Depth: 0, FromSitemap: true},
+		{URL: "https://example.com/b", Depth: 1},
+	}
+
+	tests := []struct {
+		name     string
+		order    string
+		expected []string
+	}{
+		{
+			name:     "BFS",
+			order:    CrawlOrderBFS,
+			expected: []string{"https://example.com/sitemap", "https://example.com/a", "https://example.com/b", "https://example.com/deep"},
+		},
+		{
+			name:     "DFS",
+			order:    CrawlOrderDFS,
+			expected: []string{"https://example.com/deep", "https://example.com/a", "https://example.com/b", "https://example.com/sitemap"},
+		},
+		{
+			name:     "Sitemap first",
+			order:    CrawlOrderSitemapFirst,
+			expected: []string{"https://example.com/sitemap", "https://example.com/a", "https://example.com/b", "https://example.com/deep"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			priority, err := PriorityFor(tt.order)
+			if err != nil {
+				t.Fatalf("PriorityFor() error = %v", err)
+			}
+			frontier := NewFrontier(priority, 0, "")
+			defer frontier.Close()
+
+			for _, task := range tasks {
+				if _, err := frontier.Push(task); err != nil {
+					t.Fatalf("Push() error = %v", err)
+				}
+			}
+
+			for i, want := range tt.expected {
+				task, ok := frontier.Pop(context.Background())
+				if !ok {
+					t.Fatalf("Pop() #%d returned no task", i)
+				}
+				if task.URL != want {
+					t.Errorf("Pop() #%d = %s, expected %s", i, task.URL, want)
+				}
+				frontier.Done(task)
+			}
+		})
+	}
+}
+
+func TestFrontierDeduplicates(t *testing.T) {
+	priority, _ := PriorityFor(CrawlOrderBFS)
+	frontier := NewFrontier(priority, 0, "")
+	defer frontier.Close()
+
+	frontier.MarkSeen("https://example.com/visited")
+
+	for _, url := range []string{"https://example.com/a", "https://example.com/a", "https://example.com/visited"} {
+		if _, err := frontier.Push(crawlTask{URL: url, Depth: 1}); err != nil {
+			t.Fatalf("Push() error = %v", err)
+		}
+	}
+
+	if got := frontier.Len(); got != 1 {
+		t.Errorf("Len() = %d, expected 1", got)
+	}
+}
+
+func TestFrontierSpillsToDisk(t *testing.T) {
+	priority, _ := PriorityFor(CrawlOrderBFS)
+	frontier := NewFrontier(priority, 10, t.TempDir())
+	defer frontier.Close()
+
+	const total = 2500
+	for i := 0; i < total; i++ {
+		if _, err := frontier.Push(crawlTask{URL: fmt.Sprintf("https://example.com/%d", i), Depth: 1, FromSitemap: i%2 == 0}); err != nil {
+			t.Fatalf("Push() error = %v", err)
+		}
+	}
+
+	snapshot, err := frontier.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot() error = %v", err)
+	}
+	if len(snapshot) != total {
+		t.Errorf("Snapshot() has %d tasks, expected %d", len(snapshot), total)
+	}
+	for i := 0; i < total; i++ {
+		url := fmt.Sprintf("https://example.com/%d", i)
+		if task := snapshot[url]; task.Depth != 1 || task.FromSitemap != (i%2 == 0) {
+			t.Fatalf("Snapshot()[%s] = %+v, expected depth 1 and from_sitemap %v", url, task, i%2 == 0)
+		}
+	}
+
+	for i := 0; i < total; i++ {
+		task, ok := frontier.Pop(context.Background())
+		if !ok {
+			t.Fatalf("Pop() #%d returned no task", i)
+		}
+		if want := fmt.Sprintf("https://example.com/%d", i); task.URL != want {
+			t.Fatalf("Pop() #%d = %s, expected %s", i, task.URL, want)
+		}
+		frontier.Done(task)
+	}
+
+	if _, ok := frontier.Pop(context.Background()); ok {
+		t.Error("Pop() returned a task from a drained frontier")
+	}
+}
+
+func TestFrontierWaitsForInFlightTasks(t *testing.T) {
+	priority, _ := PriorityFor(CrawlOrderBFS)
+	frontier := NewFrontier(priority, 0, "")
+	defer frontier.Close()
+
+	frontier.Push(crawlTask{URL: "https://example.com", Depth: 0})
+	first, _ := frontier.Pop(context.Background())
+
+	// A second worker blocks while the first task may still discover links
+	result := make(chan crawlTask)
+	go func() {
+		task, _ := frontier.Pop(context.Background())
+		result <- task
+	}()
+
+	frontier.Push(crawlTask{URL: "https://example.com/child", Depth: 1})
+	frontier.Done(first)
+
+	if task := <-result; task.URL != "https://example.com/child" {
+		t.Errorf("Pop() = %q, expected the discovered child", task.URL)
+	}
+}
+
+func TestFrontierSkipsUnreadableSpill(t *testing.T) {
+	priority, _ := PriorityFor(CrawlOrderBFS)
+	frontier := NewFrontier(priority, 1, t.TempDir())
+	defer frontier.Close()
+
+	frontier.Push(crawlTask{URL: "https://example.com", Depth: 0})
+	frontier.Push(crawlTask{URL: "https://example.com/a", Depth: 1})
+	frontier.Push(crawlTask{URL: "https://example.com/b", Depth: 2})
+
+	// Corrupt the depth 1 spill file
+	if err := os.WriteFile(frontier.buckets[1].spillPath, []byte("not json\n"), 0644); err != nil {
+		t.Fatalf("corrupting spill file: %v", err)
+	}
+
+	var popped []string
+	for {
+		task, ok := frontier.Pop(context.Background())
+		if !ok {
+			break
+		}
+		popped = append(popped, task.URL)
+		frontier.Done(task)
+	}
+
+	if len(popped) != 2 || popped[0] != "https://example.com" || popped[1] != "https://example.com/b" {
+		t.Errorf("Pop() returned %v, expected the tasks outside the corrupt bucket", popped)
+	}
+	if frontier.Err() == nil {
+		t.Error("Err() = nil, expected the spill read error")
+	}
+}
@@ -0,0 +1,136 @@
+package crawler
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/dillonlara115/barracudaseo/pkg/models"
+)
+
+// responseHeaders normalizes the headers of the final response.
+// Go's transport removes Content-Encoding when it transparently decompresses a gzip
+// response, so resp.Uncompressed is what tells us the server compressed the page.
+func responseHeaders(resp *http.Response) *models.ResponseHeaders {
+	header := func(name string) string {
+		values := resp.Header.Values(name)
+		for i := range values {
+			values[i] = strings.TrimSpace(values[i])
+		}
+		return strings.Join(values, ", ")
+	}
+
+	headers := &models.ResponseHeaders{
+		ContentType:             header("Content-Type"),
+		ContentEncoding:         strings.ToLower(header("Content-Encoding")),
+		CacheControl:            header("Cache-Control"),
+		Expires:                 header("Expires"),
+		Vary:                    header("Vary"),
+		Link:                    header("Link"),
+		StrictTransportSecurity: header("Strict-Transport-Security"),
+		ContentSecurityPolicy:   header("Content-Security-Policy"),
+		XFrameOptions:           header("X-Frame-Options"),
+		XContentTypeOptions:     header("X-Content-Type-Options"),
+		ReferrerPolicy:          header("Referrer-Policy"),
+		PermissionsPolicy:       header("Permissions-Policy"),
+		Server:                  header("Server"),
+	}
+	if resp.Uncompressed && headers.ContentEncoding == "" {
+		headers.ContentEncoding = "gzip"
+	}
+	if resp.ContentLength > 0 && !resp.Uncompressed {
+		headers.ContentLength = resp.ContentLength
+	}
+	if headers.Link != "" {
+		headers.LinkCanonical = linkHeaderCanonical(headers.Link, resp.Request.URL)
+	}
+	return headers
+}
+
+// linkHeaderCanonical returns the rel="canonical" target of a Link header, resolved against base
+func linkHeaderCanonical(value string, base *url.URL) string {
+	for _, link := range splitLinkHeader(value) {
+		target, params, ok := parseHeaderLink(link, base)
+		if ok && hasLinkRel(params["rel"], "canonical") {
+			return target
+		}
+	}
+	return ""
+}
+
+// linkHeaderHreflang returns the rel="alternate" links with an hreflang of a Link header,
+// resolved against base
+func linkHeaderHreflang(value string, base *url.URL) []models.Hreflang {
+	var alternates []models.Hreflang
+	for _, link := range splitLinkHeader(value) {
+		target, params, ok := parseHeaderLink(link, base)
+		if !ok || !hasLinkRel(params["rel"], "alternate") || params["hreflang"] == "" {
+			continue
+		}
+		alternates = append(alternates, models.Hreflang{
+			Lang:   params["hreflang"],
+			URL:    target,
+			Source: models.HreflangHeader,
+		})
+	}
+	return alternates
+}
+
+// parseHeaderLink splits one Link header link into its target, resolved against base, and its
+// parameters (lower-cased names, unquoted values)
+func parseHeaderLink(link string, base *url.URL) (string, map[string]string, bool) {
+	target, rest, ok := strings.Cut(link, ";")
+	target = strings.TrimSpace(target)
+	if !ok || !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+		return "", nil, false
+	}
+	ref, err := url.Parse(strings.TrimSpace(target[1 : len(target)-1]))
+	if err != nil {
+		return "", nil, false
+	}
+	if base != nil {
+		ref = base.ResolveReference(ref)
+	}
+
+	params := make(map[string]string)
+	for _, param := range strings.Split(rest, ";") {
+		name, value, _ := strings.Cut(param, "=")
+		params[strings.ToLower(strings.TrimSpace(name))] = strings.Trim(strings.TrimSpace(value), `"`)
+	}
+	return ref.String(), params, true
+}
+
+// hasLinkRel reports whether a rel parameter contains the given relation
+func hasLinkRel(rel, want string) bool {
+	for _, token := range strings.Fields(strings.ToLower(rel)) {
+		if token == want {
+			return true
+		}
+	}
+	return false
+}
+
+// splitLinkHeader splits a Link header into its comma-separated links, ignoring commas
+// inside <URI references> and quoted parameter values
+func splitLinkHeader(value string) []string {
+	var links []string
+	inURI, inQuotes := false, false
+	start := 0
+	for i, c := range value {
+		switch {
+		case c == '<' && !inQuotes:
+			inURI = true
+		case c == '>' && !inQuotes:
+			inURI = false
+		case c == '"' && !inURI:
+			inQuotes = !inQuotes
+		case c == ',' && !inURI && !inQuotes:
+			links = append(links, strings.TrimSpace(value[start:i]))
+			start = i + 1
+		}
+	}
+	if rest := strings.TrimSpace(value[start:]); rest != "" {
+		links = append(links, rest)
+	}
+	return links
+}
@@ -0,0 +1,89 @@
+package crawler
+
+import (
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestFetchCapturesResponseHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Cache-Control", " max-age=60 ")
+		w.Header().Add("Vary", "Accept-Encoding")
+		w.Header().Add("Vary", "User-Agent")
+		w.Header().Set("Link", `</style.css>; rel=preload; as=style, </canonical?a=1,2>; rel="canonical"`)
+		w.Header().Set("X-Frame-Options", "DENY")
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		gz.Write([]byte("<html><head><title>Compressed</title></head></html>"))
+		gz.Close()
+	}))
+	defer server.Close()
+
+	result := NewFetcher(5*time.Second, "test-agent").Fetch(server.URL + "/page")
+	if result.Error != nil {
+		t.Fatalf("Fetch() error = %v", result.Error)
+	}
+	headers := result.PageResult.Headers
+	if headers == nil {
+		t.Fatal("expected response headers to be captured")
+	}
+	if string(result.Body) != "<html><head><title>Compressed</title></head></html>" {
+		t.Errorf("body was not decompressed: %q", result.Body)
+	}
+	if headers.ContentEncoding != "gzip" {
+		t.Errorf("ContentEncoding = %q, want gzip", headers.ContentEncoding)
+	}
+	if headers.CacheControl != "max-age=60" {
+		t.Errorf("CacheControl = %q, want max-age=60", headers.CacheControl)
+	}
+	if headers.Vary != "Accept-Encoding, User-Agent" {
+		t.Errorf("Vary = %q, want joined values", headers.Vary)
+	}
+	if headers.XFrameOptions != "DENY" {
+		t.Errorf("XFrameOptions = %q, want DENY", headers.XFrameOptions)
+	}
+	if want := server.URL + "/canonical?a=1,2"; headers.LinkCanonical != want {
+		t.Errorf("LinkCanonical = %q, want %q", headers.LinkCanonical, want)
+	}
+}
+
+func TestLinkHeaderCanonical(t *testing.T) {
+	base, _ := url.Parse("https://example.com/a/page")
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{`<https://example.com/canonical>; rel="canonical"`, "https://example.com/canonical"},
+		{`<other>; rel=canonical`, "https://example.com/a/other"},
+		{`<https://example.com/a>; rel="alternate"; hreflang="en", <https://example.com/b>; rel="CANONICAL"`, "https://example.com/b"},
+		{`<https://example.com/next>; rel="next"`, ""},
+		{`https://example.com/bad; rel="canonical"`, ""},
+	}
+	for _, tt := range tests {
+		if got := linkHeaderCanonical(tt.header, base); got != tt.want {
+			t.Errorf("linkHeaderCanonical(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestLinkHeaderHreflang(t *testing.T) {
+	base, _ := url.Parse("https://example.com/en/")
+	header := `<https://example.com/de/>; rel="alternate"; hreflang="de", </fr/>; rel=alternate; hreflang=fr-FR, ` +
+		`<https://example.com/en/>; rel="canonical", <https://example.com/>; rel="alternate"; hreflang="x-default"`
+
+	got := linkHeaderHreflang(header, base)
+	want := []string{"de https://example.com/de/", "fr-FR https://example.com/fr/", "x-default https://example.com/"}
+	if len(got) != len(want) {
+		t.Fatalf("linkHeaderHreflang() = %+v, want %v", got, want)
+	}
+	for i, alternate := range got {
+		if alternate.Lang+" "+alternate.URL != want[i] || alternate.Source != "header" {
+			t.Errorf("alternate %d = %+v, want %s from the header", i, alternate, want[i])
+		}
+	}
+}
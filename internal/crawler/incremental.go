@@ -0,0 +1,99 @@
+package crawler
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/dillonlara115/barracudaseo/internal/utils"
+	"github.com/dillonlara115/barracudaseo/pkg/models"
+)
+
+// Baseline holds the pages of a previous crawl. Their validators turn requests into conditional
+// GETs, and a page answered with 304 Not Modified is reused instead of being downloaded and parsed again.
+type Baseline struct {
+	pages map[string]*models.PageResult
+}
+
+// NewBaseline builds a baseline from previous crawl results. Only successfully fetched pages are
+// kept, since those are the only ones that can be reused.
+func NewBaseline(pages []*models.PageResult) *Baseline {
+	baseline := &Baseline{pages: make(map[string]*models.PageResult, len(pages))}
+	for _, page := range pages {
+		if page == nil || page.StatusCode != 200 || page.Error != "" {
+			continue
+		}
+		url, err := utils.NormalizeURL(page.URL)
+		if err != nil {
+			continue
+		}
+		baseline.pages[url] = page
+	}
+	return baseline
+}
+
+// LoadBaseline reads a baseline from a JSON results export of a previous crawl
+func LoadBaseline(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read previous results: %w", err)
+	}
+
+	var pages []*models.PageResult
+	if err := json.Unmarshal(data, &pages); err != nil {
+		return nil, fmt.Errorf("failed to parse previous results (JSON export expected): %w", err)
+	}
+	return NewBaseline(pages), nil
+}
+
+// Len returns the number of reusable pages
+func (b *Baseline) Len() int {
+	if b == nil {
+		return 0
+	}
+	return len(b.pages)
+}
+
+// Get returns the previous result for a normalized URL, or nil
+func (b *Baseline) Get(url string) *models.PageResult {
+	if b == nil {
+		return nil
+	}
+	return b.pages[url]
+}
+
+// validatorsFor returns the conditional request headers for a previous result, or nil if it has none
+func validatorsFor(previous *models.PageResult) *Validators {
+	if previous == nil || (previous.ETag == "" && previous.LastModified == "") {
+		return nil
+	}
+	return &Validators{ETag: previous.ETag, LastModified: previous.LastModified}
+}
+
+// reusePage builds the result for a page that was not modified since the previous crawl.
+// Parsed content comes from the previous result; timing and fresh validators come from the 304 response.
+func reusePage(previous, current *models.PageResult) *models.PageResult {
+	page := *previous
+	page.URL = current.URL
+	page.ResponseTime = current.ResponseTime
+	page.Timing = current.Timing
+	page.CrawledAt = current.CrawledAt
+	page.Unchanged = true
+	if current.ETag != "" {
+		page.ETag = current.ETag
+	}
+	if current.LastModified != "" {
+		page.LastModified = current.LastModified
+	}
+	if len(current.RedirectChain) > 0 {
+		page.RedirectChain = current.RedirectChain
+		page.RedirectHops = current.RedirectHops
+	}
+	if current.XRobotsTag != "" {
+		page.XRobotsTag = current.XRobotsTag
+	}
+	if current.TLS != nil {
+		page.TLS = current.TLS
+	}
+	return &page
+}
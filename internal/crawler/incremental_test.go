@@ -0,0 +1,99 @@
+package crawler
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dillonlara115/barracudaseo/internal/utils"
+	"github.com/dillonlara115/barracudaseo/pkg/models"
+)
+
+func TestIncrementalCrawlReusesUnchangedPages(t *testing.T) {
+	var fullResponses int32
+	pages := map[string]string{
+		"/":       `<html><head><title>Home</title></head><body><h1>Home</h1><a href="/a">A</a><a href="/b">B</a></body></html>`,
+		"/a":      `<html><head><title>A</title></head><body><h1>A</h1><a href="/a/deep">Deep</a></body></html>`,
+		"/b":      `<html><head><title>B</title></head><body><h1>B</h1></body></html>`,
+		"/a/deep": `<html><head><title>Deep</title></head><body><h1>Deep</h1></body></html>`,
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := pages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		etag := fmt.Sprintf(`"%d"`, len(body))
+		w.Header().Set("ETag", etag)
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		atomic.AddInt32(&fullResponses, 1)
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, body)
+	}))
+	defer server.Close()
+
+	crawl := func(baseline *Baseline) ([]*models.PageResult, *Manager) {
+		config := utils.DefaultConfig()
+		config.StartURL = server.URL + "/"
+		config.RespectRobots = false
+		config.Workers = 2
+		config.Timeout = 5 * time.Second
+		config.Incremental = baseline != nil
+		manager := NewManager(config)
+		manager.SetBaseline(baseline)
+		results, err := manager.Crawl()
+		if err != nil {
+			t.Fatalf("Crawl() error = %v", err)
+		}
+		return results, manager
+	}
+
+	first, _ := crawl(nil)
+	if len(first) != len(pages) || fullResponses != int32(len(pages)) {
+		t.Fatalf("first crawl: %d results, %d full responses, want %d", len(first), fullResponses, len(pages))
+	}
+
+	// Change one page between crawls
+	pages["/b"] = strings.Replace(pages["/b"], "<h1>B</h1>", "<h1>B, updated</h1>", 1)
+	atomic.StoreInt32(&fullResponses, 0)
+
+	second, _ := crawl(NewBaseline(first))
+	if len(second) != len(pages) {
+		t.Fatalf("second crawl: %d results, want %d (links of reused pages must still be followed)", len(second), len(pages))
+	}
+	if fullResponses != 1 {
+		t.Errorf("second crawl: %d full responses, want 1", fullResponses)
+	}
+
+	for _, page := range second {
+		changed := strings.HasSuffix(page.URL, "/b")
+		if page.Unchanged == changed {
+			t.Errorf("%s: Unchanged = %v", page.URL, page.Unchanged)
+		}
+		if page.StatusCode != http.StatusOK || page.Title == "" || len(page.H1) == 0 {
+			t.Errorf("%s: reused page lost its data: status %d, title %q, h1 %v", page.URL, page.StatusCode, page.Title, page.H1)
+		}
+	}
+
+	// Pages reused from a baseline without link details still add their edges to the graph
+	for _, page := range first {
+		page.Links = nil
+	}
+	atomic.StoreInt32(&fullResponses, 0)
+	_, manager := crawl(NewBaseline(first))
+	home := server.URL // Normalized start URL
+	if edges := manager.GetLinkGraph().GetEdges(home); len(edges) != 2 {
+		t.Errorf("edges from reused home page = %v, want /a and /b", edges)
+	}
+	if links := manager.GetLinkGraph().GetLinks(home); len(links) != 2 || !links[0].Internal || links[0].Source != home {
+		t.Errorf("links from reused home page = %+v, want /a and /b from their URLs", links)
+	}
+}
@@ -3,13 +3,13 @@ package crawler
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"syscall"
-	"time"
 
 	"github.com/dillonlara115/barracudaseo/internal/graph"
 	"github.com/dillonlara115/barracudaseo/internal/utils"
@@ -23,41 +23,63 @@ type ProgressCallback func(page *models.PageResult, totalPages int)
 type Manager struct {
 	config             *utils.Config
 	fetcher            *Fetcher
+	throttle           *HostThrottle
+	renderer           Renderer
+	desktopFetcher     *Fetcher // Desktop agent fetcher in parity mode
+	desktopRenderer    Renderer // Desktop agent renderer in parity mode
 	robotsChecker      *RobotsChecker
 	sitemapParser      *SitemapParser
 	linkGraph          *graph.Graph
-	visited            sync.Map // map[string]bool for visited URLs
-	queue              chan crawlTask
+	scope              *utils.URLScope // Include/exclude rules, subdomain scope and query stripping
+	visited            sync.Map        // map[string]bool for visited URLs
+	frontier           *Frontier       // Queued and in-flight crawl tasks
 	results            []*models.PageResult
 	resultsMu          sync.Mutex
 	wg                 sync.WaitGroup
 	ctx                context.Context
 	cancel             context.CancelFunc
-	pending            int32            // Track pending tasks (atomic)
-	queueClosed        int32            // Atomic flag to track if queue is closed
-	progressCallback   ProgressCallback // Optional callback for progress updates
-	normalizedStartURL string           // Store normalized start URL for domain comparison
+	progressCallback   ProgressCallback                // Optional callback for progress updates
+	normalizedStartURL string                          // Store normalized start URL for domain comparison
+	depths             sync.Map                        // map[string]int of visited URLs to crawl depth (for checkpoints)
+	interrupted        int32                           // Atomic flag set when a shutdown signal was received
+	checkpointMu       sync.Mutex                      // Serializes checkpoint writes
+	resumeFrom         *Checkpoint                     // Checkpoint to restore state from, if resuming
+	sitemapEntries     map[string]*models.SitemapEntry // Sitemap metadata by URL (read-only once workers start)
+	baseline           *Baseline                       // Previous crawl for conditional requests, if incremental
+	unchangedCount     int32                           // Pages unchanged since the baseline crawl
+	robotsBlocked      []models.RobotsBlockedURL
+	robotsBlockedMu    sync.Mutex
 }
 
 // crawlTask represents a URL to be crawled with its depth
 type crawlTask struct {
-	URL   string
-	Depth int
+	URL         string `json:"url"`
+	Depth       int    `json:"depth"`
+	FromSitemap bool   `json:"from_sitemap,omitempty"`
 }
 
 // NewManager creates a new Manager instance
 func NewManager(config *utils.Config) *Manager {
 	ctx, cancel := context.WithCancel(context.Background())
 
+	// In parity mode the primary crawl is the smartphone version; the desktop version is fetched alongside
+	userAgent := config.UserAgent
+	if config.Parity {
+		userAgent, _ = parityUserAgents(config)
+	}
+
 	manager := &Manager{
 		config:  config,
-		fetcher: NewFetcher(config.Timeout, config.UserAgent),
-		queue:   make(chan crawlTask, config.MaxPages*2), // Buffer for queue
+		fetcher: NewFetcher(config.Timeout, userAgent),
 		results: make([]*models.PageResult, 0, config.MaxPages),
 		ctx:     ctx,
 		cancel:  cancel,
 	}
 
+	// Space out requests per host, starting from the configured delay
+	manager.throttle = NewHostThrottle(config.Delay)
+	manager.fetcher.SetThrottle(manager.throttle)
+
 	// Initialize robots checker
 	manager.robotsChecker = NewRobotsChecker(manager.fetcher, config.UserAgent, config.RespectRobots)
 
@@ -78,6 +100,22 @@ func (m *Manager) SetProgressCallback(callback ProgressCallback) {
 	m.progressCallback = callback
 }
 
+// SetRenderer overrides the renderer selected from Config.RenderMode
+func (m *Manager) SetRenderer(renderer Renderer) {
+	m.renderer = renderer
+}
+
+// SetBaseline makes the crawl incremental: pages from the previous crawl are requested
+// conditionally and reused when the server reports them unchanged
+func (m *Manager) SetBaseline(baseline *Baseline) {
+	m.baseline = baseline
+}
+
+// UnchangedPages returns the number of pages unchanged since the baseline crawl
+func (m *Manager) UnchangedPages() int {
+	return int(atomic.LoadInt32(&m.unchangedCount))
+}
+
 // Crawl starts the crawling process
 func (m *Manager) Crawl() ([]*models.PageResult, error) {
 	// Normalize start URL
@@ -86,28 +124,123 @@ func (m *Manager) Crawl() ([]*models.PageResult, error) {
 		return nil, fmt.Errorf("invalid start URL: %w", err)
 	}
 
+	// Initialize renderer unless one was injected
+	if m.renderer == nil {
+		userAgent := m.config.UserAgent
+		if m.config.Parity {
+			userAgent, _ = parityUserAgents(m.config)
+		}
+		renderer, err := NewRenderer(m.config.RenderMode, userAgent, m.config.Timeout, m.config.Workers)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize renderer: %w", err)
+		}
+		m.renderer = renderer
+	}
+	defer m.renderer.Close()
+	utils.Info("Render mode", utils.NewField("mode", m.renderer.Mode()))
+
+	// Set up credentials and log in before anything is fetched from the site
+	if !m.config.Auth.IsZero() {
+		if err := m.fetcher.ConfigureAuth(m.config.Auth, startURL); err != nil {
+			return nil, fmt.Errorf("failed to configure authentication: %w", err)
+		}
+		if m.config.Auth.LoginURL != "" {
+			if err := m.fetcher.Login(m.ctx, m.config.Auth); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// The headless browser requests pages with the fetcher's credentials, cookies and throttle
+	if chrome, ok := m.renderer.(*ChromeRenderer); ok {
+		chrome.UseFetcher(m.fetcher)
+	}
+
+	// Share credentials and throttling with a desktop agent fetcher for parity crawls
+	if m.config.Parity {
+		if err := m.setupParity(); err != nil {
+			return nil, err
+		}
+		defer func() {
+			if m.desktopRenderer != m.renderer {
+				m.desktopRenderer.Close()
+			}
+		}()
+	}
+
+	if m.config.Incremental {
+		if m.baseline.Len() == 0 {
+			utils.Warn("Incremental crawl has no previous pages to compare against; all pages are fetched in full")
+		} else {
+			utils.Info("Incremental crawl", utils.NewField("previous_pages", m.baseline.Len()))
+		}
+	}
+
+	// Initialize the frontier. Tasks beyond the memory limit spill to disk, under the
+	// state directory when one is configured.
+	priority, err := PriorityFor(m.config.CrawlOrder)
+	if err != nil {
+		return nil, err
+	}
+	m.frontier = NewFrontier(priority, m.config.FrontierMemoryLimit, spillDirFor(m.config.StateDir))
+	defer m.frontier.Close()
+
 	// Store normalized start URL for domain comparison
 	m.normalizedStartURL = startURL
 
-	// Parse sitemap if enabled
+	// Build the URL scope used to filter sitemap seeds and discovered links
+	scope, err := utils.NewURLScope(m.config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL scope: %w", err)
+	}
+	m.scope = scope
+
+	// Parse sitemap if enabled (skipped when resuming - the frontier and sitemap metadata come
+	// from the checkpoint, and in list mode - the seeds are exactly the listed URLs)
 	var seedURLs []string
-	if m.config.ParseSitemap {
-		sitemapURL := m.sitemapParser.DiscoverSitemapURL(startURL)
-		utils.Info("Parsing sitemap", utils.NewField("url", sitemapURL))
+	fromSitemap := false
+	if m.ListMode() {
+		seedURLs = m.config.URLList
+		utils.Info("List mode", utils.NewField("urls", len(seedURLs)))
+	} else if m.config.ParseSitemap && m.resumeFrom == nil {
+		// Sitemaps declared in robots.txt, falling back to /sitemap.xml
+		sitemapURLs := m.robotsChecker.Sitemaps(startURL)
+		if len(sitemapURLs) == 0 {
+			sitemapURLs = []string{m.sitemapParser.DiscoverSitemapURL(startURL)}
+		}
+		utils.Info("Parsing sitemap", utils.NewField("urls", sitemapURLs))
 
-		urls, err := m.sitemapParser.ParseSitemap(sitemapURL)
+		entries, err := m.sitemapParser.ParseSitemaps(sitemapURLs)
 		if err != nil {
-			utils.Debug("Failed to parse sitemap", utils.NewField("url", sitemapURL), utils.NewField("error", err.Error()))
+			utils.Debug("Failed to parse sitemap", utils.NewField("urls", sitemapURLs), utils.NewField("error", err.Error()))
 		} else {
-			// Filter out image URLs from sitemap
-			for _, url := range urls {
-				if !utils.IsImageURL(url) {
-					seedURLs = append(seedURLs, url)
-				} else {
+			// Filter out image URLs and out-of-scope URLs from sitemap
+			filteredImages := 0
+			filteredScope := 0
+			m.sitemapEntries = make(map[string]*models.SitemapEntry, len(entries))
+			for _, entry := range entries {
+				url := entry.Loc
+				if utils.IsImageURL(url) {
+					filteredImages++
 					utils.Debug("Skipping image URL from sitemap", utils.NewField("url", url))
+					continue
+				}
+				url = m.scope.Clean(url)
+				if allowed, reason := m.scope.Allows(url); !allowed {
+					filteredScope++
+					utils.Debug("Skipping out-of-scope URL from sitemap", utils.NewField("url", url), utils.NewField("reason", reason))
+					continue
+				}
+				seedURLs = append(seedURLs, url)
+				if key, err := utils.NormalizeURL(url); err == nil {
+					m.sitemapEntries[key] = entry
 				}
 			}
-			utils.Info("Found URLs in sitemap", utils.NewField("count", len(seedURLs)), utils.NewField("filtered_images", len(urls)-len(seedURLs)))
+			utils.Info("Found URLs in sitemap",
+				utils.NewField("count", len(seedURLs)),
+				utils.NewField("filtered_images", filteredImages),
+				utils.NewField("filtered_scope", filteredScope))
+			fromSitemap = len(seedURLs) > 0
 		}
 	}
 
@@ -116,16 +249,11 @@ func (m *Manager) Crawl() ([]*models.PageResult, error) {
 		seedURLs = []string{startURL}
 	}
 
-	// Start worker pool
-	for i := 0; i < m.config.Workers; i++ {
-		m.wg.Add(1)
-		go m.worker(i)
-	}
-
-	// Enqueue initial tasks (don't mark as visited yet - workers will do that)
-	enqueueDone := make(chan bool)
-	go func() {
-		defer close(enqueueDone)
+	// Build initial tasks from the checkpoint frontier or the seed URLs
+	var seedTasks []crawlTask
+	if m.resumeFrom != nil {
+		seedTasks = m.restoreCheckpoint()
+	} else {
 		for _, url := range seedURLs {
 			// Normalize URL
 			normalized, err := utils.NormalizeURL(url)
@@ -133,25 +261,45 @@ func (m *Manager) Crawl() ([]*models.PageResult, error) {
 				utils.Debug("Failed to normalize seed URL", utils.NewField("url", url), utils.NewField("error", err.Error()))
 				continue
 			}
-
-			atomic.AddInt32(&m.pending, 1)
-			m.queue <- crawlTask{
-				URL:   normalized,
-				Depth: 0,
-			}
+			seedTasks = append(seedTasks, crawlTask{URL: normalized, Depth: 0, FromSitemap: fromSitemap})
 		}
-	}()
+	}
 
-	// Wait for initial enqueueing to complete
-	<-enqueueDone
-	utils.Debug("Initial tasks enqueued", utils.NewField("count", len(seedURLs)))
+	// Periodically checkpoint crawl state if a state directory is configured
+	checkpointDone := make(chan struct{})
+	if m.config.StateDir != "" {
+		go m.checkpointLoop(checkpointDone)
+	}
 
-	// Monitor queue and close when done
-	go m.monitorQueue()
+	// Enqueue initial tasks before starting workers (don't mark as visited yet - workers will do that).
+	// Workers stop as soon as the frontier is empty, so it must not start out empty.
+	for _, task := range seedTasks {
+		if _, err := m.frontier.Push(task); err != nil {
+			return nil, fmt.Errorf("failed to enqueue seed URL: %w", err)
+		}
+	}
+	utils.Debug("Initial tasks enqueued", utils.NewField("count", m.frontier.Len()))
+
+	// Start worker pool
+	for i := 0; i < m.config.Workers; i++ {
+		m.wg.Add(1)
+		go m.worker(i)
+	}
 
 	// Wait for all workers to finish
 	m.wg.Wait()
 
+	// Write the final checkpoint. An interrupted crawl keeps its frontier so it can be resumed.
+	close(checkpointDone)
+	if err := m.saveCheckpoint(!m.Interrupted()); err != nil {
+		utils.Warn("Failed to save final checkpoint", utils.NewField("error", err.Error()))
+	}
+
+	// Queued URLs lost to an unreadable spill file were never crawled
+	if err := m.frontier.Err(); err != nil {
+		return m.results, fmt.Errorf("crawl incomplete: %w", err)
+	}
+
 	// Return results - don't treat cancellation as error if we got results
 	// (cancellation might be due to reaching max-pages, which is success)
 	if m.ctx.Err() != nil && len(m.results) == 0 {
@@ -161,341 +309,420 @@ func (m *Manager) Crawl() ([]*models.PageResult, error) {
 	return m.results, nil
 }
 
+// ListMode reports whether the crawl only visits an explicit list of URLs
+func (m *Manager) ListMode() bool {
+	return len(m.config.URLList) > 0
+}
+
+// Interrupted reports whether the crawl was stopped by a shutdown signal
+func (m *Manager) Interrupted() bool {
+	return atomic.LoadInt32(&m.interrupted) == 1
+}
+
+// EffectiveRate returns the current request rate in requests per second, after throttling
+func (m *Manager) EffectiveRate() float64 {
+	return m.throttle.Rate()
+}
+
+// HTTPClient returns a client that makes requests with the crawl's User-Agent, credentials,
+// cookies and per-host throttle, for checks run on the results after the crawl
+func (m *Manager) HTTPClient() *http.Client {
+	return m.fetcher.Client()
+}
+
 // GetLinkGraph returns the link graph
 func (m *Manager) GetLinkGraph() *graph.Graph {
 	return m.linkGraph
 }
 
-// worker processes crawl tasks from the queue
+// worker processes crawl tasks from the frontier until it is drained or the crawl is cancelled
 func (m *Manager) worker(id int) {
 	defer m.wg.Done()
 
 	for {
-		select {
-		case <-m.ctx.Done():
+		task, ok := m.frontier.Pop(m.ctx)
+		if !ok {
 			utils.Debug("Worker stopping", utils.NewField("worker_id", id))
 			return
-		case task, ok := <-m.queue:
-			if !ok {
-				utils.Debug("Worker queue closed", utils.NewField("worker_id", id))
-				return
-			}
+		}
 
-			// Decrement pending counter (but don't let it go negative)
-			currentPending := atomic.AddInt32(&m.pending, -1)
-			if currentPending < 0 {
-				// Reset if it went negative (shouldn't happen, but safety check)
-				atomic.StoreInt32(&m.pending, 0)
-			}
+		if stop := m.processTask(task); stop {
+			return
+		}
+	}
+}
 
-			// Check if we've reached max pages BEFORE processing
-			m.resultsMu.Lock()
-			if len(m.results) >= m.config.MaxPages {
-				m.resultsMu.Unlock()
-				// Cancel to signal other workers to stop
-				m.cancel()
-				return
-			}
-			m.resultsMu.Unlock()
+// processTask fetches, parses and records a single crawl task and enqueues the links it discovers.
+// It returns true when the worker should stop (crawl cancelled or max pages reached).
+func (m *Manager) processTask(task crawlTask) bool {
+	defer m.finishTask(task)
+
+	// Check if we've reached max pages BEFORE processing
+	m.resultsMu.Lock()
+	if len(m.results) >= m.config.MaxPages {
+		m.resultsMu.Unlock()
+		// Cancel to signal other workers to stop
+		m.cancel()
+		return true
+	}
+	m.resultsMu.Unlock()
+
+	// Check depth limit - pages at max depth should still be crawled,
+	// but we won't discover links from them (handled later)
+	// Only skip if depth exceeds max depth
+	if task.Depth > m.config.MaxDepth {
+		utils.Debug("Skipping task - depth exceeds max", utils.NewField("url", task.URL), utils.NewField("depth", task.Depth), utils.NewField("max_depth", m.config.MaxDepth))
+		return false
+	}
 
-			// Check depth limit - pages at max depth should still be crawled,
-			// but we won't discover links from them (handled later)
-			// Only skip if depth exceeds max depth
-			if task.Depth > m.config.MaxDepth {
-				utils.Debug("Skipping task - depth exceeds max", utils.NewField("url", task.URL), utils.NewField("depth", task.Depth), utils.NewField("max_depth", m.config.MaxDepth))
-				continue
-			}
+	// Check if already visited (before marking to avoid race condition)
+	if _, visited := m.visited.LoadOrStore(task.URL, true); visited {
+		return false
+	}
+	m.depths.Store(task.URL, task.Depth)
 
-			// Check if already visited (before marking to avoid race condition)
-			if _, visited := m.visited.LoadOrStore(task.URL, true); visited {
-				continue
-			}
+	// Skip image URLs - they should never be crawled as pages
+	if utils.IsImageURL(task.URL) {
+		utils.Debug("Skipping image URL - not a crawlable page", utils.NewField("url", task.URL))
+		return false
+	}
 
-			// Skip image URLs - they should never be crawled as pages
-			if utils.IsImageURL(task.URL) {
-				utils.Debug("Skipping image URL - not a crawlable page", utils.NewField("url", task.URL))
-				continue
+	// Check robots.txt before fetching
+	isBlockedByRobots := false
+	robotsRule := ""
+	if decision, err := m.robotsChecker.Check(task.URL); err != nil {
+		utils.Debug("Robots check error", utils.NewField("url", task.URL), utils.NewField("error", err.Error()))
+	} else {
+		robotsRule = decision.Reason()
+		if !decision.Allowed {
+			utils.Debug("URL disallowed by robots.txt", utils.NewField("url", task.URL), utils.NewField("rule", robotsRule))
+			// If respect_robots is true, skip the page entirely
+			// If respect_robots is false, we'll crawl it but mark it as blocked
+			m.recordBlocked(task.URL, robotsRule)
+			if m.config.RespectRobots {
+				return false
 			}
+			isBlockedByRobots = true
+		}
+	}
 
-			// Check robots.txt before fetching
-			isBlockedByRobots := false
-			if allowed, err := m.robotsChecker.IsAllowed(task.URL); err != nil {
-				utils.Debug("Robots check error", utils.NewField("url", task.URL), utils.NewField("error", err.Error()))
-			} else if !allowed {
-				utils.Debug("URL disallowed by robots.txt", utils.NewField("url", task.URL))
-				// If respect_robots is true, skip the page entirely
-				// If respect_robots is false, we'll crawl it but mark it as blocked
-				if m.config.RespectRobots {
-					continue
-				}
-				isBlockedByRobots = true
-			}
+	// Honour the host's Crawl-delay (the throttle applies it before the fetch)
+	if crawlDelay := m.robotsChecker.CrawlDelay(task.URL); crawlDelay > 0 {
+		m.throttle.SetCrawlDelay(task.URL, crawlDelay)
+	}
+
+	// Fetch the URL with retry logic, conditionally if the previous crawl saw it
+	previous := m.baseline.Get(task.URL)
+	result := m.fetcher.FetchWithRetry(m.ctx, task.URL, validatorsFor(previous), 3)
+
+	// A fetch aborted by cancellation is not a real result
+	if m.ctx.Err() != nil {
+		return true
+	}
+
+	// Reuse the previously parsed page when the server reports it unchanged
+	reused := false
+	if previous != nil {
+		if result.NotModified() {
+			result.PageResult = reusePage(previous, result.PageResult)
+			reused = true
+		} else if result.PageResult.ContentHash != "" && result.PageResult.ContentHash == previous.ContentHash {
+			result.PageResult.Unchanged = true
+		}
+		if result.PageResult.Unchanged {
+			atomic.AddInt32(&m.unchangedCount, 1)
+		}
+	}
+
+	result.PageResult.RobotsRule = robotsRule
+	result.PageResult.Sitemap = m.sitemapEntries[task.URL]
+
+	// Skip non-HTML content (images, PDFs, etc.) - don't add to results
+	if result.Error != nil && strings.Contains(result.Error.Error(), "skipped non-HTML") {
+		utils.Debug("Skipping non-HTML content", utils.NewField("url", task.URL), utils.NewField("error", result.Error.Error()))
+		return false
+	}
+
+	// Fetch the desktop version alongside the smartphone version in parity mode
+	if m.config.Parity {
+		result.PageResult.Device = DeviceMobile
+		result.PageResult.Desktop = m.fetchDesktop(task, isBlockedByRobots)
+		if m.ctx.Err() != nil {
+			return true
+		}
+	}
+
+	// Determine indexability status even for non-200 pages (based on x-robots-tag and robots.txt)
+	// Meta robots will be empty for these pages since we can't parse HTML
+	result.PageResult.DetermineIndexabilityStatus(isBlockedByRobots)
+
+	// Parse the fetched page, unless it failed, is not a 200 or was reused from the previous crawl
+	fetched := result.Error == nil && result.PageResult.StatusCode == 200
+	parsed := fetched
+	parsedData := result.PageResult
+	if fetched && !reused {
+		parsedData, parsed = m.parseFetched(m.renderer, task, result)
+	}
+	if parsed {
+		// Determine indexability status based on robots.txt, x-robots-tag, and meta robots
+		result.PageResult.DetermineIndexabilityStatus(isBlockedByRobots)
+	}
+
+	// Store result once it is complete - checkpoints encode stored results concurrently
+	// (check limit again before storing)
+	m.resultsMu.Lock()
+	resultCount := len(m.results)
+	if resultCount >= m.config.MaxPages {
+		m.resultsMu.Unlock()
+		m.cancel()
+		return true
+	}
+	m.results = append(m.results, result.PageResult)
+	resultCount = len(m.results)
+	m.resultsMu.Unlock()
+
+	utils.Info("Crawled page",
+		utils.NewField("url", task.URL),
+		utils.NewField("status", result.PageResult.StatusCode),
+		utils.NewField("depth", task.Depth),
+		utils.NewField("total", resultCount),
+		utils.NewField("rate", m.EffectiveRate()),
+		utils.NewField("unchanged", result.PageResult.Unchanged),
+	)
+
+	// Call progress callback after parsing and merging data, even for failed pages.
+	// This ensures the stored page has all the parsed SEO data (H1, links, etc.)
+	if m.progressCallback != nil {
+		m.progressCallback(result.PageResult, resultCount)
+	}
+
+	// If fetch failed or not HTML, continue without discovering links
+	if !fetched {
+		utils.Info("Skipping link discovery - fetch failed or non-200",
+			utils.NewField("url", task.URL),
+			utils.NewField("error", result.Error),
+			utils.NewField("status", result.PageResult.StatusCode))
+		return false
+	}
+	if !parsed {
+		return false
+	}
+
+	// Check if we've reached max pages after storing
+	if resultCount >= m.config.MaxPages {
+		m.cancel()
+		return true
+	}
 
-			// Apply delay if configured
-			if m.config.Delay > 0 {
-				select {
-				case <-m.ctx.Done():
-					return
-				case <-time.After(m.config.Delay):
+	// Add edges to link graph, keeping each link's anchor text, rel and position
+	// (reused pages from an older baseline may only have their link URLs)
+	m.linkGraph.AddLinks(task.URL, models.LinksOrTargets(parsedData.Links, parsedData.InternalLinks, parsedData.ExternalLinks))
+
+	// Enqueue discovered internal links for crawling
+	// Skip link discovery when CrawlSitemapOnly: crawl only sitemap URLs (like indexed pages)
+	if m.config.CrawlSitemapOnly {
+		utils.Debug("Sitemap-only mode: skipping link discovery", utils.NewField("url", task.URL))
+	} else if m.ListMode() {
+		utils.Debug("List mode: skipping link discovery", utils.NewField("url", task.URL))
+	} else if task.Depth < m.config.MaxDepth {
+		enqueuedCount := 0
+		skippedCount := 0
+		queuedSkippedCount := 0
+		domainSkippedCount := 0
+		ruleSkippedCount := 0
+		visitedSkippedCount := 0
+
+		utils.Info("Discovering links",
+			utils.NewField("url", task.URL),
+			utils.NewField("depth", task.Depth),
+			utils.NewField("max_depth", m.config.MaxDepth),
+			utils.NewField("total_internal_links", len(parsedData.InternalLinks)))
+
+		// Links to other subdomains of the start domain are external to the page but may be in scope
+		candidates := parsedData.InternalLinks
+		if m.scope.IncludesSubdomains() {
+			candidates = append([]string{}, parsedData.InternalLinks...)
+			for _, linkURL := range parsedData.ExternalLinks {
+				if m.scope.InDomain(linkURL) {
+					candidates = append(candidates, linkURL)
 				}
 			}
+		}
 
-			// Fetch the URL with retry logic
-			result := m.fetcher.FetchWithRetry(task.URL, 3)
-
-			// Skip non-HTML content (images, PDFs, etc.) - don't add to results
-			if result.Error != nil && strings.Contains(result.Error.Error(), "skipped non-HTML") {
-				utils.Debug("Skipping non-HTML content", utils.NewField("url", task.URL), utils.NewField("error", result.Error.Error()))
+		for _, linkURL := range candidates {
+			// Skip image URLs - they should not be crawled as pages
+			if utils.IsImageURL(linkURL) {
+				skippedCount++
+				utils.Debug("Skipping image URL", utils.NewField("link", linkURL))
 				continue
 			}
 
-			// Store result (check limit again before storing)
-			m.resultsMu.Lock()
-			resultCount := len(m.results)
-			if resultCount >= m.config.MaxPages {
-				m.resultsMu.Unlock()
-				m.cancel()
-				return
-			}
-			m.results = append(m.results, result.PageResult)
-			resultCount = len(m.results)
-			m.resultsMu.Unlock()
-
-			utils.Info("Crawled page",
-				utils.NewField("url", task.URL),
-				utils.NewField("status", result.PageResult.StatusCode),
-				utils.NewField("depth", task.Depth),
-				utils.NewField("total", resultCount),
-			)
-
-			// Determine indexability status even for non-200 pages (based on x-robots-tag and robots.txt)
-			// Meta robots will be empty for these pages since we can't parse HTML
-			result.PageResult.DetermineIndexabilityStatus(isBlockedByRobots)
-
-			// If fetch failed or not HTML, call progress callback and continue
-			if result.Error != nil || result.PageResult.StatusCode != 200 {
-				utils.Info("Skipping link discovery - fetch failed or non-200",
-					utils.NewField("url", task.URL),
-					utils.NewField("error", result.Error),
-					utils.NewField("status", result.PageResult.StatusCode))
-				// Call progress callback even for failed pages
-				if m.progressCallback != nil {
-					m.progressCallback(result.PageResult, resultCount)
+			// Strip ignored query parameters before scope and visited checks
+			linkURL = m.scope.Clean(linkURL)
+
+			// Check domain filter and include/exclude rules (use normalized start URL for comparison)
+			if allowed, reason := m.scope.Allows(linkURL); !allowed {
+				if reason == "domain" {
+					domainSkippedCount++
+					utils.Info("Skipping link - different domain",
+						utils.NewField("link", linkURL),
+						utils.NewField("start_url", m.normalizedStartURL))
+				} else {
+					ruleSkippedCount++
+					utils.Debug("Skipping link - excluded by URL rules", utils.NewField("link", linkURL))
 				}
 				continue
 			}
 
-			// Check if we have body content
-			if len(result.Body) == 0 {
-				utils.Warn("No body content to parse", utils.NewField("url", task.URL))
-				// Call progress callback even if no body
-				if m.progressCallback != nil {
-					m.progressCallback(result.PageResult, resultCount)
-				}
+			// Check if already visited
+			if _, visited := m.visited.Load(linkURL); visited {
+				visitedSkippedCount++
+				utils.Info("Skipping link - already visited", utils.NewField("link", linkURL))
 				continue
 			}
 
-			// Parse HTML and discover links
-			parser, err := NewParser(task.URL)
-			if err != nil {
-				utils.Error("Failed to create parser", utils.NewField("url", task.URL), utils.NewField("error", err.Error()))
-				// Call progress callback even if parser creation failed
-				if m.progressCallback != nil {
-					m.progressCallback(result.PageResult, resultCount)
-				}
-				continue
+			if m.ctx.Err() != nil {
+				utils.Info("Context cancelled, stopping link discovery")
+				return true
 			}
 
-			// Merge parsed SEO data into result
-			parsedData, err := parser.Parse(result.Body)
+			// Enqueue new task (the frontier ignores URLs that were already queued)
+			added, err := m.frontier.Push(crawlTask{URL: linkURL, Depth: task.Depth + 1})
 			if err != nil {
-				utils.Error("Failed to parse HTML", utils.NewField("url", task.URL), utils.NewField("error", err.Error()))
-				// Call progress callback even if parsing failed
-				if m.progressCallback != nil {
-					m.progressCallback(result.PageResult, resultCount)
-				}
+				utils.Error("Failed to enqueue link", utils.NewField("link", linkURL), utils.NewField("error", err.Error()))
+				skippedCount++
 				continue
 			}
-
-			utils.Info("Parsed page",
-				utils.NewField("url", task.URL),
-				utils.NewField("depth", task.Depth),
-				utils.NewField("h1_count", len(parsedData.H1)),
-				utils.NewField("h1_values", parsedData.H1),
-				utils.NewField("internal_links", len(parsedData.InternalLinks)),
-				utils.NewField("external_links", len(parsedData.ExternalLinks)),
-				utils.NewField("images", len(parsedData.Images)),
-				utils.NewField("body_size", len(result.Body)))
-
-			// Merge parsed data into page result
-			result.PageResult.Title = parsedData.Title
-			result.PageResult.MetaDesc = parsedData.MetaDesc
-			result.PageResult.Canonical = parsedData.Canonical
-			result.PageResult.MetaRobots = parsedData.MetaRobots
-			result.PageResult.H1 = parsedData.H1
-			result.PageResult.H2 = parsedData.H2
-			result.PageResult.H3 = parsedData.H3
-			result.PageResult.H4 = parsedData.H4
-			result.PageResult.H5 = parsedData.H5
-			result.PageResult.H6 = parsedData.H6
-			result.PageResult.InternalLinks = parsedData.InternalLinks
-			result.PageResult.ExternalLinks = parsedData.ExternalLinks
-			result.PageResult.Images = parsedData.Images
-
-			// Determine indexability status based on robots.txt, x-robots-tag, and meta robots
-			result.PageResult.DetermineIndexabilityStatus(isBlockedByRobots)
-
-			// Call progress callback AFTER parsing and merging data
-			// This ensures the stored page has all the parsed SEO data (H1, links, etc.)
-			if m.progressCallback != nil {
-				m.progressCallback(result.PageResult, resultCount)
+			if !added {
+				queuedSkippedCount++
+				continue
 			}
+			enqueuedCount++
+			utils.Info("Enqueued link", utils.NewField("link", linkURL), utils.NewField("new_depth", task.Depth+1))
+		}
+		utils.Info("Link discovery complete",
+			utils.NewField("url", task.URL),
+			utils.NewField("enqueued", enqueuedCount),
+			utils.NewField("skipped_domain", domainSkippedCount),
+			utils.NewField("skipped_rules", ruleSkippedCount),
+			utils.NewField("skipped_visited", visitedSkippedCount),
+			utils.NewField("skipped_queued", queuedSkippedCount),
+			utils.NewField("skipped_other", skippedCount),
+			utils.NewField("frontier_size", m.frontier.Len()),
+			utils.NewField("total_internal", len(parsedData.InternalLinks)))
+	} else {
+		utils.Info("Max depth reached, not discovering links",
+			utils.NewField("url", task.URL),
+			utils.NewField("depth", task.Depth),
+			utils.NewField("max_depth", m.config.MaxDepth))
+	}
 
-			// Check if we've reached max pages after storing
-			if resultCount >= m.config.MaxPages {
-				m.cancel()
-				return
-			}
+	// Check if we've reached max pages
+	if resultCount >= m.config.MaxPages {
+		m.cancel()
+		return true
+	}
+	return false
+}
 
-			// Add edges to link graph
-			m.linkGraph.AddEdges(task.URL, parsedData.InternalLinks)
-			m.linkGraph.AddEdges(task.URL, parsedData.ExternalLinks)
-
-			// Enqueue discovered internal links for crawling
-			// Skip link discovery when CrawlSitemapOnly: crawl only sitemap URLs (like indexed pages)
-			if m.config.CrawlSitemapOnly {
-				utils.Debug("Sitemap-only mode: skipping link discovery", utils.NewField("url", task.URL))
-			} else if task.Depth < m.config.MaxDepth {
-				enqueuedCount := 0
-				skippedCount := 0
-				domainSkippedCount := 0
-				visitedSkippedCount := 0
-
-				utils.Info("Discovering links",
-					utils.NewField("url", task.URL),
-					utils.NewField("depth", task.Depth),
-					utils.NewField("max_depth", m.config.MaxDepth),
-					utils.NewField("total_internal_links", len(parsedData.InternalLinks)))
-
-				for _, linkURL := range parsedData.InternalLinks {
-					// Skip image URLs - they should not be crawled as pages
-					if utils.IsImageURL(linkURL) {
-						skippedCount++
-						utils.Debug("Skipping image URL", utils.NewField("link", linkURL))
-						continue
-					}
-
-					// Check domain filter (use normalized start URL for comparison)
-					if m.config.DomainFilter == "same" && !utils.IsSameDomain(linkURL, m.normalizedStartURL) {
-						domainSkippedCount++
-						utils.Info("Skipping link - different domain",
-							utils.NewField("link", linkURL),
-							utils.NewField("start_url", m.normalizedStartURL))
-						continue
-					}
-
-					// Check if already visited
-					if _, visited := m.visited.Load(linkURL); visited {
-						visitedSkippedCount++
-						utils.Info("Skipping link - already visited", utils.NewField("link", linkURL))
-						continue
-					}
-
-					// Enqueue new task (check if queue is still open)
-					// Check if queue is closed before attempting to send
-					if atomic.LoadInt32(&m.queueClosed) == 1 {
-						utils.Warn("Queue closed, stopping link discovery", utils.NewField("url", task.URL))
-						return
-					}
-
-					select {
-					case <-m.ctx.Done():
-						utils.Info("Context cancelled, stopping link discovery")
-						return
-					case m.queue <- crawlTask{URL: linkURL, Depth: task.Depth + 1}:
-						// Successfully enqueued
-						atomic.AddInt32(&m.pending, 1)
-						enqueuedCount++
-						utils.Info("Enqueued link", utils.NewField("link", linkURL), utils.NewField("new_depth", task.Depth+1))
-					default:
-						// Queue full, skip (but don't panic)
-						utils.Warn("Queue full, skipping link", utils.NewField("url", linkURL))
-						skippedCount++
-					}
-				}
-				utils.Info("Link discovery complete",
-					utils.NewField("url", task.URL),
-					utils.NewField("enqueued", enqueuedCount),
-					utils.NewField("skipped_domain", domainSkippedCount),
-					utils.NewField("skipped_visited", visitedSkippedCount),
-					utils.NewField("skipped_queue_full", skippedCount),
-					utils.NewField("total_internal", len(parsedData.InternalLinks)))
-			} else {
-				utils.Info("Max depth reached, not discovering links",
-					utils.NewField("url", task.URL),
-					utils.NewField("depth", task.Depth),
-					utils.NewField("max_depth", m.config.MaxDepth))
-			}
+// parseFetched renders and parses a fetched page and merges the parsed SEO data into its result.
+// It returns false when the page has no body or cannot be parsed.
+func (m *Manager) parseFetched(renderer Renderer, task crawlTask, result *FetchResult) (*models.PageResult, bool) {
+	// Check if we have body content
+	if len(result.Body) == 0 {
+		utils.Warn("No body content to parse", utils.NewField("url", task.URL))
+		return nil, false
+	}
 
-			// Check if we've reached max pages
-			if resultCount >= m.config.MaxPages {
-				m.cancel()
-				return
-			}
-		}
+	// Parse HTML and discover links
+	parser, err := NewParser(task.URL)
+	if err != nil {
+		utils.Error("Failed to create parser", utils.NewField("url", task.URL), utils.NewField("error", err.Error()))
+		return nil, false
+	}
+	// Judge mixed content by how the page was actually served, after any redirects
+	parser.secure = result.PageResult.TLS != nil
+
+	// Render the page (no-op for static mode) and parse the resulting HTML
+	parsedData, err := m.renderAndParse(renderer, parser, task.URL, result)
+	if err != nil {
+		utils.Error("Failed to parse HTML", utils.NewField("url", task.URL), utils.NewField("error", err.Error()))
+		return nil, false
 	}
+
+	utils.Info("Parsed page",
+		utils.NewField("url", task.URL),
+		utils.NewField("depth", task.Depth),
+		utils.NewField("h1_count", len(parsedData.H1)),
+		utils.NewField("h1_values", parsedData.H1),
+		utils.NewField("internal_links", len(parsedData.InternalLinks)),
+		utils.NewField("external_links", len(parsedData.ExternalLinks)),
+		utils.NewField("images", len(parsedData.Images)),
+		utils.NewField("body_size", len(result.Body)))
+
+	// Merge parsed data into page result
+	result.PageResult.Title = parsedData.Title
+	result.PageResult.MetaDesc = parsedData.MetaDesc
+	result.PageResult.Canonical = parsedData.Canonical
+	result.PageResult.MetaRobots = parsedData.MetaRobots
+	result.PageResult.H1 = parsedData.H1
+	result.PageResult.H2 = parsedData.H2
+	result.PageResult.H3 = parsedData.H3
+	result.PageResult.H4 = parsedData.H4
+	result.PageResult.H5 = parsedData.H5
+	result.PageResult.H6 = parsedData.H6
+	result.PageResult.InternalLinks = parsedData.InternalLinks
+	result.PageResult.ExternalLinks = parsedData.ExternalLinks
+	result.PageResult.Links = parsedData.Links
+	result.PageResult.Images = parsedData.Images
+	result.PageResult.MixedContent = parsedData.MixedContent
+	result.PageResult.Resources = parsedData.Resources
+	result.PageResult.StructuredData = parsedData.StructuredData
+	// Hreflang from the Link header was recorded by the fetcher
+	result.PageResult.Hreflang = append(result.PageResult.Hreflang, parsedData.Hreflang...)
+	result.PageResult.HTMLLang = parsedData.HTMLLang
+	result.PageResult.Social = parsedData.Social
+	result.PageResult.WordCount = parsedData.WordCount
+	result.PageResult.TextRatio = parsedData.TextRatio
+	result.PageResult.TextHash = parsedData.TextHash
+	result.PageResult.SimHash = parsedData.SimHash
+
+	return parsedData, true
 }
 
-// monitorQueue closes the queue when all tasks are processed
-func (m *Manager) monitorQueue() {
-	ticker := time.NewTicker(2 * time.Second) // Check every 2 seconds (less frequent)
-	defer ticker.Stop()
+// renderAndParse runs the configured renderer over a fetched page and parses the output.
+// In JS mode the raw server HTML is parsed as well and kept on the result as a RawSnapshot,
+// so content that only appears after rendering can be flagged. If rendering fails the raw
+// HTML is used instead and the render error is recorded on the snapshot.
+func (m *Manager) renderAndParse(renderer Renderer, parser *Parser, pageURL string, result *FetchResult) (*models.PageResult, error) {
+	result.PageResult.RenderMode = renderer.Mode()
+	if renderer.Mode() == RenderModeStatic {
+		return parser.Parse(result.Body)
+	}
 
-	emptyCount := 0          // Count consecutive empty checks
-	const maxEmptyChecks = 5 // Close after 5 consecutive empty checks (10 seconds total)
-	// Increased timeout to allow workers time to discover and enqueue links
+	rawData, err := parser.Parse(result.Body)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &models.RawSnapshot{
+		Title:              rawData.Title,
+		MetaDesc:           rawData.MetaDesc,
+		Canonical:          rawData.Canonical,
+		MetaRobots:         rawData.MetaRobots,
+		H1:                 rawData.H1,
+		InternalLinksCount: len(rawData.InternalLinks),
+		ExternalLinksCount: len(rawData.ExternalLinks),
+	}
+	result.PageResult.Raw = snapshot
 
-	for {
-		select {
-		case <-m.ctx.Done():
-			utils.Info("Monitor queue: context cancelled, closing queue")
-			atomic.StoreInt32(&m.queueClosed, 1)
-			close(m.queue)
-			return
-		case <-ticker.C:
-			// Check if queue is empty and no pending tasks
-			pending := atomic.LoadInt32(&m.pending)
-			queueLen := len(m.queue)
-			resultCount := len(m.results)
-
-			utils.Info("Monitor queue check",
-				utils.NewField("pending", pending),
-				utils.NewField("queue_len", queueLen),
-				utils.NewField("results", resultCount),
-				utils.NewField("empty_count", emptyCount),
-				utils.NewField("max_pages", m.config.MaxPages))
-
-			// Only close if:
-			// 1. Queue is empty AND no pending tasks
-			// 2. We haven't reached max pages (if we have, workers will cancel)
-			// 3. We've had multiple consecutive empty checks
-			if pending <= 0 && queueLen == 0 && resultCount < m.config.MaxPages {
-				emptyCount++
-				if emptyCount >= maxEmptyChecks {
-					utils.Info("Closing queue - no pending tasks after multiple checks",
-						utils.NewField("empty_checks", emptyCount),
-						utils.NewField("total_results", resultCount),
-						utils.NewField("max_pages", m.config.MaxPages))
-					atomic.StoreInt32(&m.queueClosed, 1)
-					close(m.queue)
-					return
-				}
-			} else {
-				// Reset counter if we have pending work or reached max pages
-				emptyCount = 0
-			}
-		}
+	rendered, err := renderer.Render(m.ctx, pageURL, result.Body)
+	if err != nil {
+		utils.Warn("Render failed, using raw HTML", utils.NewField("url", pageURL), utils.NewField("error", err.Error()))
+		snapshot.RenderError = err.Error()
+		return rawData, nil
 	}
+
+	return parser.Parse(rendered)
 }
 
 // handleSignals sets up graceful shutdown on interrupt signals
@@ -505,5 +732,6 @@ func (m *Manager) handleSignals() {
 
 	<-sigChan
 	utils.Info("Received interrupt signal, shutting down gracefully...")
+	atomic.StoreInt32(&m.interrupted, 1)
 	m.cancel()
 }
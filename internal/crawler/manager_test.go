@@ -0,0 +1,328 @@
+package crawler
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dillonlara115/barracudaseo/internal/analyzer"
+	"github.com/dillonlara115/barracudaseo/internal/utils"
+)
+
+func TestListModeCrawlsOnlyListedURLs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprintf(w, `<html><head><title>%s</title></head><body><a href="/other">Other</a><a href="/more">More</a></body></html>`, r.URL.Path)
+	}))
+	defer server.Close()
+
+	config := utils.DefaultConfig()
+	config.URLList = []string{server.URL + "/a", server.URL + "/b"}
+	config.StartURL = config.URLList[0]
+	config.RespectRobots = false
+	config.ParseSitemap = true
+	config.Timeout = 5 * time.Second
+
+	results, err := NewManager(config).Crawl()
+	if err != nil {
+		t.Fatalf("Crawl() error = %v", err)
+	}
+
+	var crawled []string
+	for _, page := range results {
+		crawled = append(crawled, page.URL)
+	}
+	sort.Strings(crawled)
+	if len(crawled) != 2 || crawled[0] != config.URLList[0] || crawled[1] != config.URLList[1] {
+		t.Errorf("crawled %v, want exactly %v", crawled, config.URLList)
+	}
+}
+
+func TestParityFetchesMobileAndDesktopVersions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		title := "Desktop"
+		if strings.Contains(r.UserAgent(), "Mobile") {
+			title = "Mobile"
+		}
+		fmt.Fprintf(w, `<html><head><title>%s</title></head><body><h1>Heading</h1></body></html>`, title)
+	}))
+	defer server.Close()
+
+	config := utils.DefaultConfig()
+	config.StartURL = server.URL + "/"
+	config.RespectRobots = false
+	config.Parity = true
+	config.Timeout = 5 * time.Second
+
+	results, err := NewManager(config).Crawl()
+	if err != nil {
+		t.Fatalf("Crawl() error = %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("got %d results, want 1", len(results))
+	}
+
+	page := results[0]
+	if page.Device != DeviceMobile || page.Title != "Mobile" {
+		t.Errorf("primary page device = %q, title = %q, want mobile version", page.Device, page.Title)
+	}
+	if page.Desktop == nil {
+		t.Fatal("desktop version missing")
+	}
+	if page.Desktop.Device != DeviceDesktop || page.Desktop.Title != "Desktop" || len(page.Desktop.H1) != 1 {
+		t.Errorf("desktop version device = %q, title = %q, h1 = %v", page.Desktop.Device, page.Desktop.Title, page.Desktop.H1)
+	}
+}
+
+// TestCheckpointDuringCrawl saves checkpoints while workers are storing pages. Run with -race.
+func TestCheckpointDuringCrawl(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(2 * time.Millisecond)
+		var n int
+		fmt.Sscanf(r.URL.Path, "/page/%d", &n)
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprintf(w, `<html><head><title>Page %d</title></head><body><h1>Page %d</h1>`, n, n)
+		// Enough text that parsing overlaps the checkpoints
+		fmt.Fprintf(w, `<p>%s</p>`, strings.Repeat("words on a page ", 2000))
+		for next := n + 1; next <= n+3 && next < 20; next++ {
+			fmt.Fprintf(w, `<a href="/page/%d">Page %d</a>`, next, next)
+		}
+		fmt.Fprint(w, `</body></html>`)
+	}))
+	defer server.Close()
+
+	config := utils.DefaultConfig()
+	config.StartURL = server.URL + "/page/0"
+	config.RespectRobots = false
+	config.MaxDepth = 20
+	config.Workers = 4
+	config.Timeout = 5 * time.Second
+	config.StateDir = t.TempDir()
+	config.CheckpointInterval = time.Millisecond
+
+	results, err := NewManager(config).Crawl()
+	if err != nil {
+		t.Fatalf("Crawl() error = %v", err)
+	}
+	if len(results) != 20 {
+		t.Errorf("got %d results, want 20", len(results))
+	}
+
+	checkpoint, err := LoadCheckpoint(config.StateDir)
+	if err != nil {
+		t.Fatalf("LoadCheckpoint() error = %v", err)
+	}
+	if !checkpoint.Completed || len(checkpoint.Results) != len(results) {
+		t.Errorf("checkpoint completed = %v with %d results, want completed with %d", checkpoint.Completed, len(checkpoint.Results), len(results))
+	}
+	for _, page := range checkpoint.Results {
+		if page.Title == "" || page.IndexabilityStatus == "" {
+			t.Errorf("checkpointed page %s is incomplete: title %q, indexability %q", page.URL, page.Title, page.IndexabilityStatus)
+		}
+	}
+}
+
+func TestCheckpointOmitsCredentials(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, password, _ := r.BasicAuth(); password != "s3cret-password" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<html><head><title>Staging</title></head><body></body></html>`)
+	}))
+	defer server.Close()
+
+	config := utils.DefaultConfig()
+	config.StartURL = server.URL + "/"
+	config.RespectRobots = false
+	config.Timeout = 5 * time.Second
+	config.StateDir = t.TempDir()
+	config.Auth = utils.AuthConfig{
+		BasicUsername: "staging",
+		BasicPassword: "s3cret-password",
+		Headers:       map[string]string{"X-Staging-Token": "s3cret-token"},
+	}
+
+	results, err := NewManager(config).Crawl()
+	if err != nil {
+		t.Fatalf("Crawl() error = %v", err)
+	}
+	if len(results) != 1 || results[0].StatusCode != http.StatusOK {
+		t.Fatalf("crawl did not authenticate: %+v", results)
+	}
+
+	data, err := os.ReadFile(CheckpointPath(config.StateDir))
+	if err != nil {
+		t.Fatalf("reading checkpoint: %v", err)
+	}
+	for _, secret := range []string{"s3cret-password", "s3cret-token"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("checkpoint contains credential %q", secret)
+		}
+	}
+	if config.Auth.BasicPassword == "" {
+		t.Error("saving a checkpoint cleared the crawl's own credentials")
+	}
+}
+
+// interruptCheckpoint turns a finished crawl's checkpoint into one written before the
+// pages at queued were crawled, as if the crawl had been stopped at that point
+func interruptCheckpoint(t *testing.T, stateDir string, queued ...string) *Checkpoint {
+	t.Helper()
+	checkpoint, err := LoadCheckpoint(stateDir)
+	if err != nil {
+		t.Fatalf("LoadCheckpoint() error = %v", err)
+	}
+
+	checkpoint.Completed = false
+	checkpoint.Interrupted = true
+	checkpoint.Frontier = make(map[string]crawlTask)
+	for _, url := range queued {
+		checkpoint.Frontier[url] = crawlTask{URL: url, Depth: checkpoint.Depths[url], FromSitemap: checkpoint.SitemapEntries[url] != nil}
+	}
+	return checkpoint
+}
+
+func TestResumeKeepsRobotsBlockedURLs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			fmt.Fprint(w, "User-agent: *\nDisallow: /private\n")
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<html><head><title>Page</title></head><body><a href="/private">Private</a><a href="/about">About</a></body></html>`)
+	}))
+	defer server.Close()
+
+	config := utils.DefaultConfig()
+	config.StartURL = server.URL + "/"
+	config.ParseSitemap = false
+	config.Timeout = 5 * time.Second
+	config.StateDir = t.TempDir()
+
+	if _, err := NewManager(config).Crawl(); err != nil {
+		t.Fatalf("Crawl() error = %v", err)
+	}
+
+	resumed := NewManager(config)
+	resumed.Resume(interruptCheckpoint(t, config.StateDir, server.URL+"/about"))
+	if _, err := resumed.Crawl(); err != nil {
+		t.Fatalf("resumed Crawl() error = %v", err)
+	}
+
+	blocked := resumed.RobotsReport().Blocked
+	if len(blocked) != 1 || blocked[0].URL != server.URL+"/private" {
+		t.Fatalf("blocked = %+v, want the URL blocked before the resume", blocked)
+	}
+	if len(blocked[0].LinkedFrom) != 2 {
+		t.Errorf("blocked URL linked from %v, want the start page and the resumed page", blocked[0].LinkedFrom)
+	}
+}
+
+func TestResumeKeepsSitemapEntries(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/robots.txt":
+			w.WriteHeader(http.StatusNotFound)
+		case "/sitemap.xml":
+			w.Header().Set("Content-Type", "application/xml")
+			fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?><urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9"><url><loc>%[1]s/</loc></url><url><loc>%[1]s/a</loc><lastmod>2026-10-01</lastmod></url><url><loc>%[1]s/b</loc></url></urlset>`, server.URL)
+		default:
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprint(w, `<html><head><title>Page</title></head><body><a href="/a">A</a><a href="/b">B</a></body></html>`)
+		}
+	}))
+	defer server.Close()
+
+	config := utils.DefaultConfig()
+	config.StartURL = server.URL + "/"
+	config.RespectRobots = false
+	config.ParseSitemap = true
+	config.CrawlOrder = CrawlOrderSitemapFirst
+	config.Timeout = 5 * time.Second
+	config.StateDir = t.TempDir()
+
+	if _, err := NewManager(config).Crawl(); err != nil {
+		t.Fatalf("Crawl() error = %v", err)
+	}
+
+	resumed := NewManager(config)
+	resumed.Resume(interruptCheckpoint(t, config.StateDir, server.URL+"/a", server.URL+"/b"))
+	results, err := resumed.Crawl()
+	if err != nil {
+		t.Fatalf("resumed Crawl() error = %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("got %d results, want 3", len(results))
+	}
+	for _, page := range results {
+		if page.Sitemap == nil {
+			t.Errorf("page %s lost its sitemap entry", page.URL)
+		}
+	}
+	for _, page := range results {
+		if page.URL == server.URL+"/a" && (page.Sitemap == nil || page.Sitemap.LastMod != "2026-10-01") {
+			t.Errorf("resumed page sitemap entry = %+v, want the lastmod from the sitemap", page.Sitemap)
+		}
+	}
+}
+
+// TestResumedCrawlSitemapAudit checks that pages crawled after a resume are still audited
+// against the sitemap, including the hreflang alternates it declares
+func TestResumedCrawlSitemapAudit(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/robots.txt":
+			w.WriteHeader(http.StatusNotFound)
+		case "/sitemap.xml":
+			alternates := fmt.Sprintf(`<xhtml:link rel="alternate" hreflang="en" href="%[1]s/en"/><xhtml:link rel="alternate" hreflang="fr" href="%[1]s/fr"/><xhtml:link rel="alternate" hreflang="x-default" href="%[1]s/en"/>`, server.URL)
+			w.Header().Set("Content-Type", "application/xml")
+			fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?><urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9" xmlns:xhtml="http://www.w3.org/1999/xhtml">`)
+			fmt.Fprintf(w, `<url><loc>%s/</loc></url>`, server.URL)
+			fmt.Fprintf(w, `<url><loc>%s/en</loc>%s</url>`, server.URL, alternates)
+			fmt.Fprintf(w, `<url><loc>%s/fr</loc>%s</url>`, server.URL, alternates)
+			fmt.Fprint(w, `</urlset>`)
+		default:
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprintf(w, `<html lang="en"><head><title>%s</title></head><body><a href="/">Home</a><a href="/en">English</a><a href="/fr">Français</a></body></html>`, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	config := utils.DefaultConfig()
+	config.StartURL = server.URL + "/"
+	config.RespectRobots = false
+	config.ParseSitemap = true
+	config.CrawlOrder = CrawlOrderSitemapFirst
+	config.Timeout = 5 * time.Second
+	config.StateDir = t.TempDir()
+
+	if _, err := NewManager(config).Crawl(); err != nil {
+		t.Fatalf("Crawl() error = %v", err)
+	}
+
+	resumed := NewManager(config)
+	resumed.Resume(interruptCheckpoint(t, config.StateDir, server.URL+"/en", server.URL+"/fr"))
+	results, err := resumed.Crawl()
+	if err != nil {
+		t.Fatalf("resumed Crawl() error = %v", err)
+	}
+
+	summary := analyzer.Analyze(results)
+	summary.AddIssues(analyzer.AnalyzeSitemap(results, resumed.GetLinkGraph(), resumed.RobotsReport()))
+	for _, issue := range summary.Issues {
+		switch issue.Type {
+		case analyzer.IssueMissingFromSitemap, analyzer.IssueHreflangMissingReturn, analyzer.IssueHreflangMissingSelf:
+			t.Errorf("resumed crawl reported %s for %s: %s", issue.Type, issue.URL, issue.Message)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package crawler
+
+import (
+	"fmt"
+
+	"github.com/dillonlara115/barracudaseo/internal/utils"
+	"github.com/dillonlara115/barracudaseo/pkg/models"
+)
+
+// Device names recorded on pages in parity mode
+const (
+	DeviceMobile  = "mobile"
+	DeviceDesktop = "desktop"
+)
+
+// parityUserAgents returns the smartphone and desktop agents for a parity crawl
+func parityUserAgents(config *utils.Config) (mobile, desktop string) {
+	mobile = config.MobileUserAgent
+	if mobile == "" {
+		mobile = utils.DefaultMobileUserAgent
+	}
+	desktop = config.DesktopUserAgent
+	if desktop == "" {
+		desktop = utils.DefaultDesktopUserAgent
+	}
+	return mobile, desktop
+}
+
+// setupParity creates the desktop agent fetcher and renderer. The fetcher shares the
+// primary fetcher's client, throttle and credentials, so it must run after authentication.
+func (m *Manager) setupParity() error {
+	_, desktopUA := parityUserAgents(m.config)
+	m.desktopFetcher = m.fetcher.WithUserAgent(desktopUA)
+
+	// Static rendering does not depend on the agent; a browser needs its own instance
+	m.desktopRenderer = m.renderer
+	if m.renderer.Mode() == RenderModeJS {
+		renderer, err := NewRenderer(RenderModeJS, desktopUA, m.config.Timeout, m.config.Workers)
+		if err != nil {
+			return fmt.Errorf("failed to initialize desktop renderer: %w", err)
+		}
+		renderer.(*ChromeRenderer).UseFetcher(m.desktopFetcher)
+		m.desktopRenderer = renderer
+	}
+
+	utils.Info("Parity crawl", utils.NewField("desktop_user_agent", desktopUA))
+	return nil
+}
+
+// fetchDesktop fetches and parses the desktop version of a page for comparison with the smartphone version
+func (m *Manager) fetchDesktop(task crawlTask, isBlockedByRobots bool) *models.PageResult {
+	result := m.desktopFetcher.FetchWithRetry(m.ctx, task.URL, nil, 3)
+	result.PageResult.Device = DeviceDesktop
+
+	if result.Error == nil && result.PageResult.StatusCode == 200 {
+		if _, ok := m.parseFetched(m.desktopRenderer, task, result); !ok {
+			utils.Debug("Failed to parse desktop version", utils.NewField("url", task.URL))
+		}
+	}
+
+	result.PageResult.DetermineIndexabilityStatus(isBlockedByRobots)
+	return result.PageResult
+}
@@ -13,6 +13,7 @@ import (
 type Parser struct {
 	baseURL string
 	domain  string
+	secure  bool // Page was served over HTTPS, so http:// subresources are mixed content
 }
 
 // NewParser creates a new Parser instance
@@ -25,6 +26,7 @@ func NewParser(baseURL string) (*Parser, error) {
 	return &Parser{
 		baseURL: baseURL,
 		domain:  domain,
+		secure:  strings.HasPrefix(strings.ToLower(baseURL), "https://"),
 	}, nil
 }
 
@@ -184,6 +186,7 @@ func (p *Parser) Parse(htmlContent []byte) (*models.PageResult, error) {
 	})
 
 	// Extract links
+	seenLinks := make(map[string]bool)
 	doc.Find("a[href]").Each(func(i int, s *goquery.Selection) {
 		href, exists := s.Attr("href")
 		if !exists {
@@ -212,8 +215,17 @@ func (p *Parser) Parse(htmlContent []byte) (*models.PageResult, error) {
 			return
 		}
 
+		// Keep every distinct link with its anchor text, rel and position
+		internal := utils.IsSameDomain(normalizedURL, p.baseURL)
+		link := newLink(s, normalizedURL, internal)
+		key := strings.Join([]string{link.URL, link.Anchor, link.Alt, strings.Join(link.Rel, " "), link.Target, link.Position}, "\x00")
+		if !seenLinks[key] {
+			seenLinks[key] = true
+			result.Links = append(result.Links, link)
+		}
+
 		// Categorize as internal or external
-		if utils.IsSameDomain(normalizedURL, p.baseURL) {
+		if internal {
 			// Avoid duplicates
 			for _, existing := range result.InternalLinks {
 				if existing == normalizedURL {
@@ -287,9 +299,316 @@ func (p *Parser) Parse(htmlContent []byte) (*models.PageResult, error) {
 			utils.NewField("image_count", imageCount))
 	}
 
+	if p.secure {
+		result.MixedContent = p.findMixedContent(doc)
+	}
+	result.Resources = p.findResources(doc)
+	result.StructuredData = findStructuredData(doc)
+	result.Hreflang = p.findHreflang(doc)
+	result.Social = p.findSocialMeta(doc)
+	result.HTMLLang = strings.TrimSpace(doc.Find("html").AttrOr("lang", ""))
+
+	visible, main := pageText(doc)
+	result.WordCount = countWords(main)
+	result.TextRatio = textRatio(visible, len(htmlContent))
+	result.TextHash, result.SimHash = contentFingerprints(main)
+
 	return result, nil
 }
 
+// newLink describes an <a> element that points to target
+func newLink(s *goquery.Selection, target string, internal bool) models.Link {
+	link := models.Link{
+		URL:      target,
+		Anchor:   strings.Join(strings.Fields(s.Text()), " "),
+		Target:   strings.TrimSpace(s.AttrOr("target", "")),
+		Position: linkPosition(s),
+		Internal: internal,
+	}
+	if rel := strings.Fields(strings.ToLower(s.AttrOr("rel", ""))); len(rel) > 0 {
+		link.Rel = rel
+	}
+	var alts []string
+	s.Find("img[alt]").Each(func(i int, img *goquery.Selection) {
+		if alt := strings.Join(strings.Fields(img.AttrOr("alt", "")), " "); alt != "" {
+			alts = append(alts, alt)
+		}
+	})
+	link.Alt = strings.Join(alts, " ")
+	return link
+}
+
+// linkPosition returns the part of the page a link is in. Headers and footers inside an
+// article or <main> belong to the content rather than the site chrome.
+func linkPosition(s *goquery.Selection) string {
+	if s.Closest("nav, [role='navigation']").Length() > 0 {
+		return models.LinkPositionNav
+	}
+	if s.Closest("aside, [role='complementary']").Length() > 0 {
+		return models.LinkPositionSidebar
+	}
+	inContent := s.Closest("article, main, [role='main']").Length() > 0
+	if footer := s.Closest("footer, [role='contentinfo']"); footer.Length() > 0 && (!inContent || footer.Is("[role='contentinfo']")) {
+		return models.LinkPositionFooter
+	}
+	if header := s.Closest("header, [role='banner']"); header.Length() > 0 && (!inContent || header.Is("[role='banner']")) {
+		return models.LinkPositionHeader
+	}
+	return models.LinkPositionContent
+}
+
+// findSocialMeta reads the Open Graph and Twitter Card tags of a page, or returns nil if it has none.
+// Sites put both kinds in either the property or the name attribute, so both are read.
+func (p *Parser) findSocialMeta(doc *goquery.Document) *models.SocialMeta {
+	social := &models.SocialMeta{}
+	fields := map[string]*string{
+		"og:title":            &social.OGTitle,
+		"og:description":      &social.OGDescription,
+		"og:image":            &social.OGImage,
+		"og:image:url":        &social.OGImage,
+		"og:url":              &social.OGURL,
+		"og:type":             &social.OGType,
+		"og:site_name":        &social.OGSiteName,
+		"twitter:card":        &social.TwitterCard,
+		"twitter:title":       &social.TwitterTitle,
+		"twitter:description": &social.TwitterDescription,
+		"twitter:image":       &social.TwitterImage,
+		"twitter:image:src":   &social.TwitterImage,
+		"twitter:site":        &social.TwitterSite,
+	}
+
+	found := false
+	doc.Find("meta[content]").Each(func(i int, s *goquery.Selection) {
+		key := strings.ToLower(strings.TrimSpace(s.AttrOr("property", "")))
+		if key == "" {
+			key = strings.ToLower(strings.TrimSpace(s.AttrOr("name", "")))
+		}
+		field, ok := fields[key]
+		if !ok || *field != "" {
+			return // The first tag wins, as it does for the platforms
+		}
+		if value := strings.TrimSpace(s.AttrOr("content", "")); value != "" {
+			*field = value
+			found = true
+		}
+	})
+	if !found {
+		return nil
+	}
+
+	for _, field := range []*string{&social.OGImage, &social.OGURL, &social.TwitterImage} {
+		if *field == "" {
+			continue
+		}
+		if resolved, err := utils.ResolveURL(p.baseURL, *field); err == nil {
+			*field = resolved
+		}
+	}
+	return social
+}
+
+// findHreflang returns the <link rel="alternate" hreflang> annotations of a page
+func (p *Parser) findHreflang(doc *goquery.Document) []models.Hreflang {
+	var alternates []models.Hreflang
+	doc.Find("link[hreflang][href]").Each(func(i int, s *goquery.Selection) {
+		isAlternate := false
+		for _, rel := range strings.Fields(strings.ToLower(s.AttrOr("rel", ""))) {
+			if rel == "alternate" {
+				isAlternate = true
+			}
+		}
+		lang := strings.TrimSpace(s.AttrOr("hreflang", ""))
+		if !isAlternate || lang == "" {
+			return
+		}
+		resolvedURL, err := utils.ResolveURL(p.baseURL, strings.TrimSpace(s.AttrOr("href", "")))
+		if err != nil {
+			return
+		}
+		alternates = append(alternates, models.Hreflang{Lang: lang, URL: resolvedURL, Source: models.HreflangHTML})
+	})
+	return alternates
+}
+
+// resourceLinkRels maps the <link rel> values that load a resource to its type; preloads use their "as" attribute
+var resourceLinkRels = map[string]string{
+	"stylesheet":       models.ResourceStylesheet,
+	"icon":             models.ResourceImage,
+	"apple-touch-icon": models.ResourceImage,
+	"modulepreload":    models.ResourceScript,
+	"preload":          "",
+}
+
+// preloadTypes maps the "as" attribute of <link rel="preload"> to a resource type
+var preloadTypes = map[string]string{
+	"style":  models.ResourceStylesheet,
+	"script": models.ResourceScript,
+	"font":   models.ResourceFont,
+	"image":  models.ResourceImage,
+	"video":  models.ResourceMedia,
+	"audio":  models.ResourceMedia,
+}
+
+// fontExtensions identify font files loaded with a <link> that doesn't say what it loads
+var fontExtensions = []string{".woff2", ".woff", ".ttf", ".otf", ".eot"}
+
+// findResources returns the stylesheets, scripts, fonts, iframes, images and media files a page loads.
+// Scripts in <head> without async, defer or type="module" are marked render-blocking.
+func (p *Parser) findResources(doc *goquery.Document) []models.Resource {
+	var resources []models.Resource
+	seen := make(map[string]bool)
+	doc.Find("link[href], script[src], iframe[src], img[src], video[src], audio[src], video source[src], audio source[src]").Each(func(i int, s *goquery.Selection) {
+		var resourceType, ref string
+		switch tag := goquery.NodeName(s); tag {
+		case "link":
+			resourceType = linkResourceType(s)
+			ref = s.AttrOr("href", "")
+		case "script":
+			resourceType = models.ResourceScript
+			ref = s.AttrOr("src", "")
+		case "iframe":
+			resourceType = models.ResourceIframe
+			ref = s.AttrOr("src", "")
+		case "img":
+			resourceType = models.ResourceImage
+			ref = s.AttrOr("src", "")
+		default:
+			resourceType = models.ResourceMedia
+			ref = s.AttrOr("src", "")
+		}
+		if resourceType == "" {
+			return
+		}
+
+		resourceURL, ok := p.resolveResource(ref)
+		if !ok || seen[resourceURL] {
+			return
+		}
+		seen[resourceURL] = true
+
+		resource := models.Resource{URL: resourceURL, Type: resourceType}
+		if resourceType == models.ResourceScript && goquery.NodeName(s) == "script" {
+			resource.RenderBlocking = isRenderBlockingScript(s)
+		}
+		if resourceType != models.ResourceFont && hasFontExtension(resourceURL) {
+			resource.Type = models.ResourceFont
+		}
+		resources = append(resources, resource)
+	})
+	return resources
+}
+
+// linkResourceType returns the type of resource a <link> loads, or "" if it loads nothing
+func linkResourceType(s *goquery.Selection) string {
+	for _, rel := range strings.Fields(strings.ToLower(s.AttrOr("rel", ""))) {
+		resourceType, ok := resourceLinkRels[rel]
+		if !ok {
+			continue
+		}
+		if rel == "preload" {
+			resourceType = preloadTypes[strings.ToLower(strings.TrimSpace(s.AttrOr("as", "")))]
+			if resourceType == "" && hasFontExtension(s.AttrOr("href", "")) {
+				resourceType = models.ResourceFont
+			}
+		}
+		if resourceType != "" {
+			return resourceType
+		}
+	}
+	return ""
+}
+
+// isRenderBlockingScript reports whether an external script blocks rendering: it is in <head>
+// and has neither async nor defer, and is not a module (modules are deferred by default)
+func isRenderBlockingScript(s *goquery.Selection) bool {
+	if s.ParentsFiltered("head").Length() == 0 {
+		return false
+	}
+	if _, async := s.Attr("async"); async {
+		return false
+	}
+	if _, deferred := s.Attr("defer"); deferred {
+		return false
+	}
+	return !strings.EqualFold(strings.TrimSpace(s.AttrOr("type", "")), "module")
+}
+
+// hasFontExtension reports whether a URL path ends in a web font extension
+func hasFontExtension(rawURL string) bool {
+	if u, err := url.Parse(rawURL); err == nil {
+		rawURL = u.Path
+	}
+	lower := strings.ToLower(rawURL)
+	for _, ext := range fontExtensions {
+		if strings.HasSuffix(lower, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveResource resolves and normalizes a subresource reference, skipping data: and other non-HTTP URLs
+func (p *Parser) resolveResource(ref string) (string, bool) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return "", false
+	}
+	resolvedURL, err := utils.ResolveURL(p.baseURL, ref)
+	if err != nil {
+		return "", false
+	}
+	normalizedURL, err := utils.NormalizeURL(resolvedURL)
+	if err != nil {
+		return "", false
+	}
+	u, err := url.Parse(normalizedURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return "", false
+	}
+	return normalizedURL, true
+}
+
+// mixedContentLinkRels are the <link rel> values that make the browser load the resource
+var mixedContentLinkRels = map[string]bool{
+	"stylesheet":       true,
+	"icon":             true,
+	"apple-touch-icon": true,
+	"preload":          true,
+	"modulepreload":    true,
+	"manifest":         true,
+}
+
+// findMixedContent returns the http:// images, scripts, stylesheets and iframes a page loads
+func (p *Parser) findMixedContent(doc *goquery.Document) []models.MixedContent {
+	var mixed []models.MixedContent
+	seen := make(map[string]bool)
+	doc.Find("img[src], script[src], iframe[src], link[href]").Each(func(i int, s *goquery.Selection) {
+		tag := goquery.NodeName(s)
+		attr := "src"
+		if tag == "link" {
+			attr = "href"
+			loads := false
+			for _, rel := range strings.Fields(strings.ToLower(s.AttrOr("rel", ""))) {
+				if mixedContentLinkRels[rel] {
+					loads = true
+				}
+			}
+			if !loads {
+				return
+			}
+		}
+
+		// Relative and protocol-relative URLs inherit https, so only explicit http:// URLs are mixed
+		ref := strings.TrimSpace(s.AttrOr(attr, ""))
+		if !strings.HasPrefix(strings.ToLower(ref), "http://") || seen[ref] {
+			return
+		}
+		seen[ref] = true
+		mixed = append(mixed, models.MixedContent{URL: ref, Tag: tag})
+	})
+	return mixed
+}
+
 // ExtractLinks extracts all links from HTML content and returns them as a slice
 func (p *Parser) ExtractLinks(htmlContent []byte) ([]string, error) {
 	result, err := p.Parse(htmlContent)
@@ -0,0 +1,171 @@
+package crawler
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dillonlara115/barracudaseo/pkg/models"
+)
+
+const resourcePage = `<!DOCTYPE html>
+<html><head>
+<link rel="stylesheet" href="/css/site.css">
+<link rel="preload" href="/fonts/inter.woff2" as="font" crossorigin>
+<link rel="canonical" href="https://example.com/">
+<link rel="icon" href="/favicon.ico">
+<script src="/js/blocking.js"></script>
+<script src="/js/deferred.js" defer></script>
+<script src="/js/async.js" async></script>
+<script type="module" src="/js/app.mjs"></script>
+<script>var inline = true;</script>
+</head><body>
+<img src="/img/hero.jpg" alt="Hero">
+<img src="data:image/png;base64,AAAA">
+<iframe src="https://www.youtube.com/embed/abc"></iframe>
+<video><source src="/media/intro.mp4" type="video/mp4"></video>
+<script src="/js/footer.js"></script>
+<script src="/js/blocking.js"></script>
+</body></html>`
+
+func TestParserFindsResources(t *testing.T) {
+	parser, err := NewParser("https://example.com/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := parser.Parse([]byte(resourcePage))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]models.Resource{
+		"https://example.com/css/site.css":      {Type: models.ResourceStylesheet},
+		"https://example.com/fonts/inter.woff2": {Type: models.ResourceFont},
+		"https://example.com/favicon.ico":       {Type: models.ResourceImage},
+		"https://example.com/js/blocking.js":    {Type: models.ResourceScript, RenderBlocking: true},
+		"https://example.com/js/deferred.js":    {Type: models.ResourceScript},
+		"https://example.com/js/async.js":       {Type: models.ResourceScript},
+		"https://example.com/js/app.mjs":        {Type: models.ResourceScript},
+		"https://example.com/img/hero.jpg":      {Type: models.ResourceImage},
+		"https://www.youtube.com/embed/abc":     {Type: models.ResourceIframe},
+		"https://example.com/media/intro.mp4":   {Type: models.ResourceMedia},
+		"https://example.com/js/footer.js":      {Type: models.ResourceScript},
+	}
+	if len(result.Resources) != len(want) {
+		t.Fatalf("expected %d resources, got %+v", len(want), result.Resources)
+	}
+	for _, resource := range result.Resources {
+		expected, ok := want[resource.URL]
+		if !ok {
+			t.Errorf("unexpected resource %+v", resource)
+			continue
+		}
+		if resource.Type != expected.Type || resource.RenderBlocking != expected.RenderBlocking {
+			t.Errorf("resource %s = %+v, want type %s, render blocking %v", resource.URL, resource, expected.Type, expected.RenderBlocking)
+		}
+	}
+}
+
+func TestParserFindsHreflang(t *testing.T) {
+	parser, err := NewParser("https://example.com/en/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := parser.Parse([]byte(`<html lang="en-GB"><head>
+<link rel="alternate" hreflang="en-GB" href="/en/">
+<link rel="alternate" hreflang="de" href="https://example.com/de/">
+<link rel="alternate" hreflang="x-default" href="https://example.com/">
+<link rel="alternate" type="application/rss+xml" href="/feed.xml">
+<link rel="stylesheet" hreflang="en" href="/site.css">
+</head></html>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.HTMLLang != "en-GB" {
+		t.Errorf("HTMLLang = %q, want en-GB", result.HTMLLang)
+	}
+	// URLs are normalized like links, without the trailing slash
+	want := []string{"en-GB https://example.com/en", "de https://example.com/de", "x-default https://example.com"}
+	if len(result.Hreflang) != len(want) {
+		t.Fatalf("Hreflang = %+v, want %v", result.Hreflang, want)
+	}
+	for i, alternate := range result.Hreflang {
+		if alternate.Lang+" "+alternate.URL != want[i] || alternate.Source != models.HreflangHTML {
+			t.Errorf("alternate %d = %+v, want %s from the HTML", i, alternate, want[i])
+		}
+	}
+}
+
+func TestParserFindsSocialMeta(t *testing.T) {
+	parser, err := NewParser("https://example.com/post")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := parser.Parse([]byte(`<html><head>
+<meta property="og:title" content="Post title">
+<meta property="og:title" content="Ignored second title">
+<meta property="og:image" content="/images/share.jpg">
+<meta property="og:url" content="https://example.com/post">
+<meta name="twitter:card" content="summary_large_image">
+<meta property="twitter:title" content="Tweet title">
+</head></html>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := models.SocialMeta{
+		OGTitle:      "Post title",
+		OGImage:      "https://example.com/images/share.jpg",
+		OGURL:        "https://example.com/post",
+		TwitterCard:  "summary_large_image",
+		TwitterTitle: "Tweet title",
+	}
+	if result.Social == nil || *result.Social != want {
+		t.Errorf("Social = %+v, want %+v", result.Social, want)
+	}
+
+	if result, _ := parser.Parse([]byte(`<html><head><title>Plain</title></head></html>`)); result.Social != nil {
+		t.Errorf("expected no social metadata, got %+v", result.Social)
+	}
+}
+
+func TestParserFindsLinks(t *testing.T) {
+	parser, err := NewParser("https://example.com/page")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := parser.Parse([]byte(`<html><body>
+<header><a href="/">Home</a><nav><a href="/shop">Shop</a></nav></header>
+<main><article><header><a href="/author">By   Jane</a></header>
+<p>Read the <a href="/guide" rel="Nofollow UGC" target="_blank">full <b>guide</b></a>.</p>
+<p><a href="/guide" rel="nofollow ugc" target="_blank">full guide</a></p>
+<a href="https://partner.example.org/" rel="sponsored"><img src="/logo.png" alt="Partner logo"></a>
+</article></main>
+<aside><a href="/related">Related</a></aside>
+<footer><a href="/shop">Shop</a><a href="mailto:hi@example.com">Mail</a></footer>
+</body></html>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []models.Link{
+		{URL: "https://example.com", Anchor: "Home", Position: models.LinkPositionHeader, Internal: true},
+		{URL: "https://example.com/shop", Anchor: "Shop", Position: models.LinkPositionNav, Internal: true},
+		{URL: "https://example.com/author", Anchor: "By Jane", Position: models.LinkPositionContent, Internal: true},
+		{URL: "https://example.com/guide", Anchor: "full guide", Rel: []string{"nofollow", "ugc"}, Target: "_blank", Position: models.LinkPositionContent, Internal: true},
+		{URL: "https://partner.example.org", Alt: "Partner logo", Rel: []string{"sponsored"}, Position: models.LinkPositionContent},
+		{URL: "https://example.com/related", Anchor: "Related", Position: models.LinkPositionSidebar, Internal: true},
+		{URL: "https://example.com/shop", Anchor: "Shop", Position: models.LinkPositionFooter, Internal: true},
+	}
+	if !reflect.DeepEqual(result.Links, want) {
+		t.Errorf("Links =\n%+v\nwant\n%+v", result.Links, want)
+	}
+	if !result.Links[3].Nofollow() || !result.Links[4].Nofollow() || result.Links[0].Nofollow() {
+		t.Errorf("Nofollow() does not match the rel values")
+	}
+
+	// The URL lists keep one entry per target
+	if len(result.InternalLinks) != 5 || len(result.ExternalLinks) != 1 {
+		t.Errorf("InternalLinks = %v, ExternalLinks = %v", result.InternalLinks, result.ExternalLinks)
+	}
+}
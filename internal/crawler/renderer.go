@@ -0,0 +1,311 @@
+package crawler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/dillonlara115/barracudaseo/internal/utils"
+)
+
+const (
+	// RenderModeStatic parses the raw HTML returned by the server
+	RenderModeStatic = "static"
+	// RenderModeJS renders pages in a headless browser before parsing
+	RenderModeJS = "js"
+
+	// defaultRenderBudget is how long the headless browser may run scripts before the DOM is captured
+	defaultRenderBudget = 5 * time.Second
+)
+
+// Renderer produces the HTML that the parser should see for a fetched page.
+// Implementations may return the raw body untouched or execute JavaScript first.
+type Renderer interface {
+	// Render returns the HTML for pageURL. raw is the body already fetched by the Fetcher.
+	Render(ctx context.Context, pageURL string, raw []byte) ([]byte, error)
+	// Mode returns the render mode implemented by this renderer
+	Mode() string
+	// Close releases any resources held by the renderer
+	Close() error
+}
+
+// NewRenderer creates the renderer for the given render mode
+func NewRenderer(mode string, userAgent string, timeout time.Duration, concurrency int) (Renderer, error) {
+	switch mode {
+	case "", RenderModeStatic:
+		return NewStaticRenderer(), nil
+	case RenderModeJS:
+		return NewChromeRenderer(userAgent, timeout, concurrency)
+	default:
+		return nil, fmt.Errorf("unsupported render mode: %s", mode)
+	}
+}
+
+// StaticRenderer returns the raw HTML body without executing JavaScript
+type StaticRenderer struct{}
+
+// NewStaticRenderer creates a new StaticRenderer instance
+func NewStaticRenderer() *StaticRenderer {
+	return &StaticRenderer{}
+}
+
+// Render returns the raw body unchanged
+func (r *StaticRenderer) Render(ctx context.Context, pageURL string, raw []byte) ([]byte, error) {
+	return raw, nil
+}
+
+// Mode returns RenderModeStatic
+func (r *StaticRenderer) Mode() string {
+	return RenderModeStatic
+}
+
+// Close is a no-op for the static renderer
+func (r *StaticRenderer) Close() error {
+	return nil
+}
+
+// ChromeRenderer renders pages with a headless Chrome/Chromium binary and captures the resulting DOM
+type ChromeRenderer struct {
+	binary    string
+	userAgent string
+	timeout   time.Duration
+	budget    time.Duration
+	noSandbox bool          // Run without the Chrome sandbox (CHROME_NO_SANDBOX), e.g. as root in a container
+	slots     chan struct{} // Limits concurrent browser processes
+	fetcher   *Fetcher      // Supplies credentials, cookies and throttling; see UseFetcher
+}
+
+// chromeCandidates lists binaries checked when CHROME_PATH is not set
+var chromeCandidates = []string{
+	"google-chrome",
+	"google-chrome-stable",
+	"chromium",
+	"chromium-browser",
+	"/Applications/Google Chrome.app/Contents/MacOS/Google Chrome",
+}
+
+// NewChromeRenderer creates a new ChromeRenderer instance.
+// The browser binary is taken from CHROME_PATH or discovered on PATH. The Chrome sandbox
+// stays on unless CHROME_NO_SANDBOX is set, which is needed when running as root.
+func NewChromeRenderer(userAgent string, timeout time.Duration, concurrency int) (*ChromeRenderer, error) {
+	binary, err := findChromeBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	return &ChromeRenderer{
+		binary:    binary,
+		userAgent: userAgent,
+		timeout:   timeout,
+		budget:    defaultRenderBudget,
+		noSandbox: os.Getenv("CHROME_NO_SANDBOX") != "",
+		slots:     make(chan struct{}, concurrency),
+	}, nil
+}
+
+// UseFetcher makes the browser render pages like fetcher requests them: each render waits
+// for fetcher's throttle, and requests carry its credentials and session cookies
+func (r *ChromeRenderer) UseFetcher(fetcher *Fetcher) {
+	r.fetcher = fetcher
+}
+
+// findChromeBinary locates a headless-capable Chrome or Chromium executable
+func findChromeBinary() (string, error) {
+	if path := os.Getenv("CHROME_PATH"); path != "" {
+		if _, err := os.Stat(path); err != nil {
+			return "", fmt.Errorf("CHROME_PATH %q not found: %w", path, err)
+		}
+		return path, nil
+	}
+
+	for _, candidate := range chromeCandidates {
+		if path, err := exec.LookPath(candidate); err == nil {
+			return path, nil
+		}
+	}
+
+	return "", fmt.Errorf("no Chrome or Chromium binary found for JavaScript rendering (set CHROME_PATH)")
+}
+
+// Render loads pageURL in headless Chrome, lets scripts run, and returns the serialized DOM.
+// With a fetcher set (see UseFetcher) the browser waits for the crawl's throttle and sends its
+// credentials and cookies, so authenticated pages render the same content the fetcher saw.
+func (r *ChromeRenderer) Render(ctx context.Context, pageURL string, raw []byte) ([]byte, error) {
+	select {
+	case r.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() { <-r.slots }()
+
+	if r.fetcher != nil && r.fetcher.throttle != nil {
+		if err := r.fetcher.throttle.Wait(ctx, pageURL); err != nil {
+			return nil, fmt.Errorf("render cancelled: %w", err)
+		}
+	}
+
+	renderCtx, cancel := context.WithTimeout(ctx, r.timeout+r.budget)
+	defer cancel()
+
+	args := []string{
+		"--headless=new",
+		"--disable-gpu",
+		"--hide-scrollbars",
+		"--mute-audio",
+		"--remote-debugging-pipe",
+	}
+	if r.noSandbox {
+		args = append(args, "--no-sandbox")
+	}
+	if r.userAgent != "" {
+		args = append(args, "--user-agent="+r.userAgent)
+	}
+	args = append(args, "about:blank")
+
+	// The browser reads commands from fd 3 and writes responses to fd 4
+	browserIn, commands, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create devtools pipe: %w", err)
+	}
+	defer commands.Close()
+	responses, browserOut, err := os.Pipe()
+	if err != nil {
+		browserIn.Close()
+		return nil, fmt.Errorf("failed to create devtools pipe: %w", err)
+	}
+	defer responses.Close()
+
+	cmd := exec.CommandContext(renderCtx, r.binary, args...)
+	cmd.ExtraFiles = []*os.File{browserIn, browserOut}
+	cmd.WaitDelay = time.Second
+	startTime := time.Now()
+	err = cmd.Start()
+	browserIn.Close()
+	browserOut.Close()
+	if err != nil {
+		return nil, fmt.Errorf("headless render failed: %w", err)
+	}
+
+	conn := newCDPConn(responses, commands)
+	output, err := r.dumpDOM(conn, pageURL)
+
+	// The browser exits when its command pipe closes; drain what it still sends so the reader stops
+	conn.notify("", "Browser.close", nil)
+	commands.Close()
+	for range conn.messages {
+	}
+	cmd.Wait()
+	if err != nil {
+		if renderCtx.Err() != nil {
+			err = renderCtx.Err()
+		}
+		return nil, fmt.Errorf("headless render failed: %w", err)
+	}
+
+	utils.Debug("Rendered page",
+		utils.NewField("url", pageURL),
+		utils.NewField("raw_size", len(raw)),
+		utils.NewField("rendered_size", len(output)),
+		utils.NewField("render_ms", time.Since(startTime).Milliseconds()))
+
+	if len(output) == 0 {
+		return nil, fmt.Errorf("headless render returned empty DOM")
+	}
+
+	return output, nil
+}
+
+// dumpDOM opens pageURL in a new tab, lets scripts run for the render budget and returns the DOM
+func (r *ChromeRenderer) dumpDOM(conn *cdpConn, pageURL string) ([]byte, error) {
+	var target struct {
+		TargetID string `json:"targetId"`
+	}
+	if err := conn.call("", "Target.createTarget", map[string]any{"url": "about:blank"}, &target); err != nil {
+		return nil, err
+	}
+	var attached struct {
+		SessionID string `json:"sessionId"`
+	}
+	if err := conn.call("", "Target.attachToTarget", map[string]any{"targetId": target.TargetID, "flatten": true}, &attached); err != nil {
+		return nil, err
+	}
+	session := attached.SessionID
+
+	// Pause every request to add the crawl's credentials and cookies
+	if r.fetcher != nil {
+		conn.onEvent = func(msg cdpMessage) {
+			if msg.Method != "Fetch.requestPaused" || msg.SessionID != session {
+				return
+			}
+			var paused struct {
+				RequestID string `json:"requestId"`
+				Request   struct {
+					URL     string            `json:"url"`
+					Headers map[string]string `json:"headers"`
+				} `json:"request"`
+			}
+			if err := json.Unmarshal(msg.Params, &paused); err != nil {
+				return
+			}
+			params := map[string]any{"requestId": paused.RequestID}
+			if headers, ok := r.fetcher.browserHeaders(paused.Request.URL, paused.Request.Headers); ok {
+				params["headers"] = headers
+			}
+			conn.notify(session, "Fetch.continueRequest", params)
+		}
+		if err := conn.call(session, "Fetch.enable", map[string]any{"patterns": []map[string]string{{"urlPattern": "*"}}}, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := conn.call(session, "Page.enable", nil, nil); err != nil {
+		return nil, err
+	}
+	var navigation struct {
+		ErrorText string `json:"errorText"`
+	}
+	if err := conn.call(session, "Page.navigate", map[string]any{"url": pageURL}, &navigation); err != nil {
+		return nil, err
+	}
+	if navigation.ErrorText != "" {
+		return nil, fmt.Errorf("navigation failed: %s", navigation.ErrorText)
+	}
+
+	// Virtual time runs ahead while no requests are pending, so scripts get the whole budget
+	// without the render taking that long (like --virtual-time-budget)
+	budget := map[string]any{"policy": "pauseIfNetworkFetchesPending", "budget": r.budget.Milliseconds()}
+	if err := conn.call(session, "Emulation.setVirtualTimePolicy", budget, nil); err != nil {
+		return nil, err
+	}
+	if err := conn.waitEvent(session, "Emulation.virtualTimeBudgetExpired"); err != nil {
+		return nil, err
+	}
+
+	var evaluated struct {
+		Result struct {
+			Value string `json:"value"`
+		} `json:"result"`
+	}
+	dom := map[string]any{"expression": "document.documentElement.outerHTML", "returnByValue": true}
+	if err := conn.call(session, "Runtime.evaluate", dom, &evaluated); err != nil {
+		return nil, err
+	}
+	return []byte(evaluated.Result.Value), nil
+}
+
+// Mode returns RenderModeJS
+func (r *ChromeRenderer) Mode() string {
+	return RenderModeJS
+}
+
+// Close is a no-op; each render runs in its own short-lived browser process
+func (r *ChromeRenderer) Close() error {
+	return nil
+}
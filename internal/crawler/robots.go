@@ -3,26 +3,35 @@ package crawler
 import (
 	"fmt"
 	"net/url"
+	"slices"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/dillonlara115/barracudaseo/internal/utils"
-	"github.com/temoto/robotstxt"
+	"github.com/dillonlara115/barracudaseo/pkg/models"
 )
 
 // RobotsChecker handles robots.txt checking and caching
 type RobotsChecker struct {
 	fetcher       *Fetcher
-	cache         map[string]*robotstxt.Group
+	cache         map[string]*robotsEntry
 	cacheMu       sync.RWMutex
 	userAgent     string
 	respectRobots bool
 }
 
+// robotsEntry is the cached robots.txt of a host
+type robotsEntry struct {
+	host models.RobotsHost
+	file *RobotsFile // nil when there is no robots.txt or it could not be read
+}
+
 // NewRobotsChecker creates a new RobotsChecker instance
 func NewRobotsChecker(fetcher *Fetcher, userAgent string, respectRobots bool) *RobotsChecker {
 	return &RobotsChecker{
 		fetcher:       fetcher,
-		cache:         make(map[string]*robotstxt.Group),
+		cache:         make(map[string]*robotsEntry),
 		userAgent:     userAgent,
 		respectRobots: respectRobots,
 	}
@@ -30,76 +39,162 @@ func NewRobotsChecker(fetcher *Fetcher, userAgent string, respectRobots bool) *R
 
 // IsAllowed checks if a URL is allowed by robots.txt
 func (r *RobotsChecker) IsAllowed(targetURL string) (bool, error) {
-	if !r.respectRobots {
-		return true, nil
+	decision, err := r.Check(targetURL)
+	if err != nil {
+		return false, err
 	}
+	return decision.Allowed, nil
+}
 
+// Check decides whether robots.txt allows a URL and records the rule that decided it.
+// Robots.txt is evaluated even when it is not respected, so blocked pages can still be flagged.
+// Like Google, a robots.txt returning a server error disallows everything, while a missing
+// robots.txt (4xx) or one that cannot be fetched at all allows everything.
+func (r *RobotsChecker) Check(targetURL string) (*RobotsDecision, error) {
 	u, err := url.Parse(targetURL)
 	if err != nil {
-		return false, fmt.Errorf("invalid URL: %w", err)
+		return nil, fmt.Errorf("invalid URL: %w", err)
 	}
 
-	robotsURL := fmt.Sprintf("%s://%s/robots.txt", u.Scheme, u.Host)
-	domain := u.Host
+	entry := r.entryFor(u)
+	if entry.host.Unavailable {
+		return &RobotsDecision{URL: targetURL, Unavailable: true}, nil
+	}
+	if entry.file == nil {
+		return &RobotsDecision{URL: targetURL, Allowed: true}, nil
+	}
+	return entry.file.Decide(r.userAgent, targetURL)
+}
+
+// CrawlDelay returns the Crawl-delay declared for our user agent on targetURL's host.
+// It only reads the cache, so Check must have been called for the host first.
+func (r *RobotsChecker) CrawlDelay(targetURL string) time.Duration {
+	if !r.respectRobots {
+		return 0
+	}
+
+	u, err := url.Parse(targetURL)
+	if err != nil {
+		return 0
+	}
 
-	// Check cache
 	r.cacheMu.RLock()
-	cached, exists := r.cache[domain]
+	entry := r.cache[u.Host]
 	r.cacheMu.RUnlock()
 
-	if exists && cached != nil {
-		return cached.Test(targetURL), nil
+	if entry == nil || entry.file == nil {
+		return 0
 	}
+	return entry.file.CrawlDelay(r.userAgent)
+}
 
-	// Fetch robots.txt
-	robotsData, err := r.fetchRobotsTxt(robotsURL)
+// Sitemaps returns the sitemaps declared with Sitemap: lines in the robots.txt of targetURL's host
+func (r *RobotsChecker) Sitemaps(targetURL string) []string {
+	u, err := url.Parse(targetURL)
 	if err != nil {
+		return nil
+	}
+	return r.entryFor(u).host.Sitemaps
+}
+
+// Hosts returns the robots.txt fetch outcome for every host checked so far, sorted by URL
+func (r *RobotsChecker) Hosts() []models.RobotsHost {
+	r.cacheMu.RLock()
+	hosts := make([]models.RobotsHost, 0, len(r.cache))
+	for _, entry := range r.cache {
+		hosts = append(hosts, entry.host)
+	}
+	r.cacheMu.RUnlock()
+
+	sort.Slice(hosts, func(i, j int) bool { return hosts[i].URL < hosts[j].URL })
+	return hosts
+}
+
+// entryFor returns the cached robots.txt for a URL's host, fetching it on first use
+func (r *RobotsChecker) entryFor(u *url.URL) *robotsEntry {
+	r.cacheMu.RLock()
+	entry, exists := r.cache[u.Host]
+	r.cacheMu.RUnlock()
+	if exists {
+		return entry
+	}
+
+	robotsURL := fmt.Sprintf("%s://%s/robots.txt", u.Scheme, u.Host)
+	entry = &robotsEntry{host: models.RobotsHost{URL: robotsURL}}
+
+	result := r.fetcher.Fetch(robotsURL)
+	entry.host.StatusCode = result.PageResult.StatusCode
+	switch {
+	case result.PageResult.StatusCode == 0:
 		// If robots.txt can't be fetched, allow by default
-		utils.Debug("Could not fetch robots.txt", utils.NewField("url", robotsURL), utils.NewField("error", err.Error()))
-		
-		// Cache a permissive group to avoid repeated fetches
-		r.cacheMu.Lock()
-		r.cache[domain] = nil // nil means allow all
-		r.cacheMu.Unlock()
-		
-		return true, nil
-	}
-
-	// Parse robots.txt
-	robotsGroup, err := robotstxt.FromBytes(robotsData)
-	if err != nil {
-		utils.Debug("Could not parse robots.txt", utils.NewField("url", robotsURL), utils.NewField("error", err.Error()))
-		
-		// Cache a permissive group
-		r.cacheMu.Lock()
-		r.cache[domain] = nil
-		r.cacheMu.Unlock()
-		
-		return true, nil
-	}
-
-	// Get group for user agent
-	group := robotsGroup.FindGroup(r.userAgent)
-	
-	// Cache the group
+		entry.host.Error = result.Error.Error()
+		utils.Debug("Could not fetch robots.txt", utils.NewField("url", robotsURL), utils.NewField("error", entry.host.Error))
+	case result.PageResult.StatusCode >= 500:
+		entry.host.Unavailable = true
+		utils.Warn("robots.txt unavailable, treating the host as disallowed",
+			utils.NewField("url", robotsURL),
+			utils.NewField("status", result.PageResult.StatusCode))
+	case result.Error != nil || result.PageResult.StatusCode != 200:
+		utils.Debug("No robots.txt", utils.NewField("url", robotsURL), utils.NewField("status", result.PageResult.StatusCode))
+	default:
+		entry.file = ParseRobots(result.Body)
+		entry.host.Warnings = entry.file.Warnings
+		entry.host.Sitemaps = entry.file.Sitemaps
+		if len(entry.file.Warnings) > 0 {
+			utils.Debug("robots.txt has syntax problems", utils.NewField("url", robotsURL), utils.NewField("warnings", len(entry.file.Warnings)))
+		}
+	}
+
+	// Cache the entry, including failures, to avoid repeated fetches
 	r.cacheMu.Lock()
-	r.cache[domain] = group
+	if cached, exists := r.cache[u.Host]; exists {
+		entry = cached
+	} else {
+		r.cache[u.Host] = entry
+	}
 	r.cacheMu.Unlock()
 
-	return group.Test(targetURL), nil
+	return entry
 }
 
-// fetchRobotsTxt fetches robots.txt content
-func (r *RobotsChecker) fetchRobotsTxt(robotsURL string) ([]byte, error) {
-	result := r.fetcher.Fetch(robotsURL)
-	if result.Error != nil {
-		return nil, result.Error
+// recordBlocked remembers a URL that robots.txt disallows
+func (m *Manager) recordBlocked(targetURL, rule string) {
+	m.robotsBlockedMu.Lock()
+	blocked := models.RobotsBlockedURL{URL: targetURL, Rule: rule}
+	if entry := m.sitemapEntries[targetURL]; entry != nil {
+		blocked.Sitemap = entry.Source
 	}
-	
-	if result.PageResult.StatusCode != 200 {
-		return nil, fmt.Errorf("HTTP %d", result.PageResult.StatusCode)
-	}
-	
-	return result.Body, nil
+	m.robotsBlocked = append(m.robotsBlocked, blocked)
+	m.robotsBlockedMu.Unlock()
 }
 
+// RobotsReport returns the robots.txt fetched for each host and the URLs robots.txt disallows,
+// with the crawled pages that link to them
+func (m *Manager) RobotsReport() *models.RobotsReport {
+	report := &models.RobotsReport{Hosts: m.robotsChecker.Hosts()}
+
+	m.robotsBlockedMu.Lock()
+	blocked := append([]models.RobotsBlockedURL(nil), m.robotsBlocked...)
+	m.robotsBlockedMu.Unlock()
+	if len(blocked) == 0 {
+		return report
+	}
+
+	index := make(map[string]int, len(blocked))
+	for i, b := range blocked {
+		index[b.URL] = i
+	}
+	m.resultsMu.Lock()
+	for _, page := range m.results {
+		for _, link := range page.InternalLinks {
+			if i, ok := index[link]; ok && !slices.Contains(blocked[i].LinkedFrom, page.URL) {
+				blocked[i].LinkedFrom = append(blocked[i].LinkedFrom, page.URL)
+			}
+		}
+	}
+	m.resultsMu.Unlock()
+
+	sort.Slice(blocked, func(i, j int) bool { return blocked[i].URL < blocked[j].URL })
+	report.Blocked = blocked
+	return report
+}
@@ -0,0 +1,278 @@
+package crawler
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RobotsRule is an Allow or Disallow line of a robots.txt file
+type RobotsRule struct {
+	Allow   bool
+	Path    string
+	Line    int
+	pattern *regexp.Regexp // Set when the path uses * or $ wildcards
+}
+
+// String formats the rule as it appears in robots.txt, with its line number
+func (r *RobotsRule) String() string {
+	if r == nil {
+		return ""
+	}
+	directive := "Disallow"
+	if r.Allow {
+		directive = "Allow"
+	}
+	return fmt.Sprintf("%s: %s (line %d)", directive, r.Path, r.Line)
+}
+
+// matches reports whether the rule applies to a URL path (including the query string)
+func (r *RobotsRule) matches(path string) bool {
+	if r.pattern != nil {
+		return r.pattern.MatchString(path)
+	}
+	return strings.HasPrefix(path, r.Path)
+}
+
+// robotsGroup is a group of rules declared for one or more user agents
+type robotsGroup struct {
+	agents     []string // Lowercased User-agent values
+	rules      []*RobotsRule
+	crawlDelay time.Duration
+}
+
+// RobotsFile is a parsed robots.txt file
+type RobotsFile struct {
+	groups   []*robotsGroup
+	Sitemaps []string
+	Warnings []string // Syntax problems, prefixed with their line number
+}
+
+// RobotsDecision explains whether robots.txt allows a URL for a user agent
+type RobotsDecision struct {
+	URL         string
+	Allowed     bool
+	Group       string      // User-agent of the group that applied ("*" for the default group, empty when none)
+	Rule        *RobotsRule // Rule that decided the URL, nil when no rule matched
+	Unavailable bool        // robots.txt returned a server error, which disallows every URL
+}
+
+// Reason describes what decided the URL: the matching rule, or an unavailable robots.txt
+func (d *RobotsDecision) Reason() string {
+	if d.Unavailable {
+		return "robots.txt unavailable (server error)"
+	}
+	return d.Rule.String()
+}
+
+// ParseRobots parses robots.txt content following Google's interpretation: groups start at
+// User-agent lines, unknown directives are ignored and problems are recorded as warnings.
+func ParseRobots(data []byte) *RobotsFile {
+	file := &RobotsFile{}
+	var current *robotsGroup
+	inAgents := false // Consecutive User-agent lines share one group
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+		if line == 1 {
+			text = strings.TrimPrefix(text, "\ufeff")
+		}
+		if i := strings.Index(text, "#"); i >= 0 {
+			text = text[:i]
+		}
+		text = strings.TrimSpace(text)
+		if text == "" {
+			continue
+		}
+
+		key, value, ok := strings.Cut(text, ":")
+		if !ok {
+			file.warnf(line, "missing ':' in %q", text)
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			if value == "" {
+				file.warnf(line, "empty User-agent")
+				continue
+			}
+			if !inAgents {
+				current = &robotsGroup{}
+				file.groups = append(file.groups, current)
+				inAgents = true
+			}
+			current.agents = append(current.agents, strings.ToLower(value))
+		case "allow", "disallow":
+			inAgents = false
+			if current == nil {
+				file.warnf(line, "%s before any User-agent", directiveName(key))
+				continue
+			}
+			if value == "" {
+				// An empty Disallow allows everything; an empty Allow has no effect
+				continue
+			}
+			if !strings.HasPrefix(value, "/") && !strings.HasPrefix(value, "*") {
+				file.warnf(line, "%s path %q should start with /", directiveName(key), value)
+			}
+			current.rules = append(current.rules, newRobotsRule(key == "allow", value, line))
+		case "crawl-delay":
+			inAgents = false
+			if current == nil {
+				file.warnf(line, "Crawl-delay before any User-agent")
+				continue
+			}
+			seconds, err := strconv.ParseFloat(value, 64)
+			if err != nil || seconds < 0 {
+				file.warnf(line, "invalid Crawl-delay %q", value)
+				continue
+			}
+			current.crawlDelay = time.Duration(seconds * float64(time.Second))
+		case "sitemap":
+			if u, err := url.Parse(value); err != nil || !u.IsAbs() {
+				file.warnf(line, "Sitemap must be an absolute URL, got %q", value)
+				continue
+			}
+			file.Sitemaps = append(file.Sitemaps, value)
+		case "host", "noindex", "clean-param", "request-rate", "visit-time":
+			// Non-standard directives some crawlers support; Google ignores them
+		default:
+			file.warnf(line, "unknown directive %q", key)
+		}
+	}
+	return file
+}
+
+// warnf records a syntax warning for a line
+func (f *RobotsFile) warnf(line int, format string, args ...interface{}) {
+	f.Warnings = append(f.Warnings, fmt.Sprintf("line %d: %s", line, fmt.Sprintf(format, args...)))
+}
+
+// directiveName restores the conventional capitalization of a directive
+func directiveName(key string) string {
+	if key == "allow" {
+		return "Allow"
+	}
+	return "Disallow"
+}
+
+// newRobotsRule creates a rule, compiling wildcard paths to a regular expression
+func newRobotsRule(allow bool, path string, line int) *RobotsRule {
+	rule := &RobotsRule{Allow: allow, Path: path, Line: line}
+	if strings.ContainsAny(path, "*$") {
+		pattern := regexp.QuoteMeta(path)
+		pattern = strings.ReplaceAll(pattern, `\*`, ".*")
+		if strings.HasSuffix(pattern, `\$`) {
+			pattern = strings.TrimSuffix(pattern, `\$`) + "$"
+		}
+		rule.pattern = regexp.MustCompile("^" + pattern)
+	}
+	return rule
+}
+
+// robotsAgentToken extracts the product token robots.txt groups are matched against,
+// e.g. "googlebot" from "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)"
+func robotsAgentToken(agent string) string {
+	agent = strings.ToLower(strings.TrimSpace(agent))
+	if i := strings.Index(agent, "compatible; "); i >= 0 {
+		agent = agent[i+len("compatible; "):]
+	}
+	if i := strings.IndexAny(agent, "/ ;)"); i >= 0 {
+		agent = agent[:i]
+	}
+	return agent
+}
+
+// findGroup returns the rules for the most specific group matching the agent, merging groups
+// that name the same agent, and the user-agent that was matched. Without a match it falls back to "*".
+func (f *RobotsFile) findGroup(agent string) (*robotsGroup, string) {
+	token := robotsAgentToken(agent)
+
+	best := ""
+	for _, group := range f.groups {
+		for _, name := range group.agents {
+			if name != "*" && strings.HasPrefix(token, name) && len(name) > len(best) {
+				best = name
+			}
+		}
+	}
+	if best == "" {
+		best = "*"
+	}
+
+	merged := &robotsGroup{agents: []string{best}}
+	found := false
+	for _, group := range f.groups {
+		for _, name := range group.agents {
+			if name == best {
+				found = true
+				merged.rules = append(merged.rules, group.rules...)
+				if merged.crawlDelay == 0 {
+					merged.crawlDelay = group.crawlDelay
+				}
+				break
+			}
+		}
+	}
+	if !found {
+		return nil, ""
+	}
+	return merged, best
+}
+
+// CrawlDelay returns the Crawl-delay declared for the agent
+func (f *RobotsFile) CrawlDelay(agent string) time.Duration {
+	group, _ := f.findGroup(agent)
+	if group == nil {
+		return 0
+	}
+	return group.crawlDelay
+}
+
+// Decide applies the file to a URL: the longest matching rule wins and Allow wins ties.
+func (f *RobotsFile) Decide(agent, targetURL string) (*RobotsDecision, error) {
+	u, err := url.Parse(targetURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+
+	decision := &RobotsDecision{URL: targetURL, Allowed: true}
+	group, name := f.findGroup(agent)
+	if group == nil {
+		return decision, nil
+	}
+	decision.Group = name
+
+	for _, rule := range group.rules {
+		if !rule.matches(path) {
+			continue
+		}
+		if decision.Rule == nil || len(rule.Path) > len(decision.Rule.Path) ||
+			(len(rule.Path) == len(decision.Rule.Path) && rule.Allow && !decision.Rule.Allow) {
+			decision.Rule = rule
+		}
+	}
+	if decision.Rule != nil {
+		decision.Allowed = decision.Rule.Allow
+	}
+	return decision, nil
+}
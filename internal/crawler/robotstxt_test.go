@@ -0,0 +1,110 @@
+package crawler
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const testRobotsTxt = `# Example robots.txt
+User-agent: *
+Disallow: /private
+Allow: /private/public
+Disallow: /*.pdf$
+Disallow: /search?
+
+User-agent: Googlebot
+User-agent: Bingbot
+Disallow: /nogoogle
+Crawl-delay: 2
+
+Sitemap: https://example.com/sitemap.xml
+Noindex /oops
+Disalow: /typo
+`
+
+func TestRobotsFileDecide(t *testing.T) {
+	file := ParseRobots([]byte(testRobotsTxt))
+
+	tests := []struct {
+		agent   string
+		url     string
+		allowed bool
+		group   string
+		rule    string
+	}{
+		{"barracuda/1.0.0", "https://example.com/", true, "*", ""},
+		{"barracuda/1.0.0", "https://example.com/private/page", false, "*", "Disallow: /private (line 3)"},
+		{"barracuda/1.0.0", "https://example.com/private/public/page", true, "*", "Allow: /private/public (line 4)"},
+		{"barracuda/1.0.0", "https://example.com/files/report.pdf", false, "*", "Disallow: /*.pdf$ (line 5)"},
+		{"barracuda/1.0.0", "https://example.com/files/report.pdf?v=2", true, "*", ""},
+		{"barracuda/1.0.0", "https://example.com/search?q=shoes", false, "*", "Disallow: /search? (line 6)"},
+		{"Googlebot", "https://example.com/private/page", true, "googlebot", ""},
+		{"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", "https://example.com/nogoogle", false, "googlebot", "Disallow: /nogoogle (line 10)"},
+		{"bingbot/2.0", "https://example.com/nogoogle/x", false, "bingbot", "Disallow: /nogoogle (line 10)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.agent+" "+tt.url, func(t *testing.T) {
+			decision, err := file.Decide(tt.agent, tt.url)
+			if err != nil {
+				t.Fatalf("Decide() error = %v", err)
+			}
+			if decision.Allowed != tt.allowed || decision.Group != tt.group || decision.Rule.String() != tt.rule {
+				t.Errorf("Decide() = allowed %v, group %q, rule %q; want %v, %q, %q",
+					decision.Allowed, decision.Group, decision.Rule, tt.allowed, tt.group, tt.rule)
+			}
+		})
+	}
+
+	if got := file.CrawlDelay("Googlebot"); got != 2*time.Second {
+		t.Errorf("CrawlDelay(Googlebot) = %v, want 2s", got)
+	}
+	if len(file.Sitemaps) != 1 || file.Sitemaps[0] != "https://example.com/sitemap.xml" {
+		t.Errorf("Sitemaps = %v", file.Sitemaps)
+	}
+	if len(file.Warnings) != 2 {
+		t.Errorf("Warnings = %v, want the missing ':' on line 13 and the unknown directive on line 14", file.Warnings)
+	}
+}
+
+func TestRobotsCheckerRecordsHostStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprint(w, "maintenance")
+	}))
+	defer server.Close()
+
+	checker := NewRobotsChecker(NewFetcher(5*time.Second, "barracuda/1.0.0"), "barracuda/1.0.0", true)
+	decision, err := checker.Check(server.URL + "/page")
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if decision.Allowed || !decision.Unavailable {
+		t.Errorf("Check() = %+v, want disallowed when robots.txt returns a server error", decision)
+	}
+	if decision.Reason() != "robots.txt unavailable (server error)" {
+		t.Errorf("Reason() = %q", decision.Reason())
+	}
+
+	hosts := checker.Hosts()
+	if len(hosts) != 1 || hosts[0].StatusCode != http.StatusServiceUnavailable || !hosts[0].Unavailable || hosts[0].URL != server.URL+"/robots.txt" {
+		t.Errorf("Hosts() = %+v, want one unavailable host with status 503", hosts)
+	}
+}
+
+func TestRobotsCheckerAllowsMissingRobots(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	checker := NewRobotsChecker(NewFetcher(5*time.Second, "barracuda/1.0.0"), "barracuda/1.0.0", true)
+	allowed, err := checker.IsAllowed(server.URL + "/page")
+	if err != nil || !allowed {
+		t.Fatalf("IsAllowed() = %v, %v; want allowed when there is no robots.txt", allowed, err)
+	}
+	if hosts := checker.Hosts(); len(hosts) != 1 || hosts[0].Unavailable {
+		t.Errorf("Hosts() = %+v, want one available host", hosts)
+	}
+}
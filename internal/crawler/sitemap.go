@@ -1,23 +1,38 @@
 package crawler
 
 import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
 	"encoding/xml"
 	"fmt"
+	"io"
 	"net/url"
+	"os"
 	"strings"
 
 	"github.com/dillonlara115/barracudaseo/internal/utils"
+	"github.com/dillonlara115/barracudaseo/pkg/models"
+)
+
+const (
+	// maxSitemapDepth limits how deeply sitemap indexes may nest
+	maxSitemapDepth = 5
+	// maxSitemapSize is the protocol's limit on an (uncompressed) sitemap file
+	maxSitemapSize = 50 * 1024 * 1024
 )
 
 // SitemapIndex represents a sitemap index file
 type SitemapIndex struct {
-	XMLName xml.Name `xml:"sitemapindex"`
+	XMLName  xml.Name  `xml:"sitemapindex"`
 	Sitemaps []Sitemap `xml:"sitemap"`
 }
 
 // Sitemap represents a single sitemap entry
 type Sitemap struct {
-	Loc string `xml:"loc"`
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod"`
 }
 
 // URLSet represents a sitemap URL set
@@ -26,9 +41,47 @@ type URLSet struct {
 	URLs    []URL    `xml:"url"`
 }
 
-// URL represents a single URL in a sitemap
+// URL represents a single URL in a sitemap, with the image, video, news and xhtml:link extensions
 type URL struct {
-	Loc string `xml:"loc"`
+	Loc        string         `xml:"loc"`
+	LastMod    string         `xml:"lastmod"`
+	ChangeFreq string         `xml:"changefreq"`
+	Priority   string         `xml:"priority"`
+	Images     []SitemapImage `xml:"image"`
+	Videos     []SitemapVideo `xml:"video"`
+	News       *SitemapNews   `xml:"news"`
+	Links      []SitemapLink  `xml:"link"`
+}
+
+// SitemapImage is an image:image extension entry
+type SitemapImage struct {
+	Loc     string `xml:"loc"`
+	Title   string `xml:"title"`
+	Caption string `xml:"caption"`
+}
+
+// SitemapVideo is a video:video extension entry
+type SitemapVideo struct {
+	ThumbnailLoc string `xml:"thumbnail_loc"`
+	Title        string `xml:"title"`
+	Description  string `xml:"description"`
+	ContentLoc   string `xml:"content_loc"`
+	PlayerLoc    string `xml:"player_loc"`
+}
+
+// SitemapNews is a news:news extension entry
+type SitemapNews struct {
+	PublicationName     string `xml:"publication>name"`
+	PublicationLanguage string `xml:"publication>language"`
+	PublicationDate     string `xml:"publication_date"`
+	Title               string `xml:"title"`
+}
+
+// SitemapLink is an xhtml:link alternate language entry
+type SitemapLink struct {
+	Rel      string `xml:"rel,attr"`
+	Hreflang string `xml:"hreflang,attr"`
+	Href     string `xml:"href,attr"`
 }
 
 // SitemapParser parses sitemap.xml files
@@ -45,73 +98,279 @@ func NewSitemapParser(fetcher *Fetcher) *SitemapParser {
 
 // ParseSitemap fetches and parses a sitemap URL, returning all URLs found
 func (s *SitemapParser) ParseSitemap(sitemapURL string) ([]string, error) {
-	result := s.fetcher.Fetch(sitemapURL)
+	entries, err := s.ParseSitemaps([]string{sitemapURL})
+	if err != nil {
+		return nil, err
+	}
+
+	urls := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		urls = append(urls, entry.Loc)
+	}
+	return urls, nil
+}
+
+// ParseSitemaps fetches sitemaps (XML, gzipped XML or plain text) and follows sitemap indexes,
+// returning every unique URL with its sitemap metadata. Loc is the normalized URL and Source the
+// first sitemap file that listed it. An error is returned only when none of the sitemaps could be read.
+func (s *SitemapParser) ParseSitemaps(sitemapURLs []string) ([]*models.SitemapEntry, error) {
+	walk := newSitemapWalk(s)
+
+	var firstErr error
+	parsed := 0
+	for _, sitemapURL := range sitemapURLs {
+		if err := walk.visit(strings.TrimSpace(sitemapURL), 0); err != nil {
+			utils.Debug("Failed to parse sitemap", utils.NewField("url", sitemapURL), utils.NewField("error", err.Error()))
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		parsed++
+	}
+	if parsed == 0 && firstErr != nil {
+		return nil, firstErr
+	}
+	return walk.entries, nil
+}
+
+// ParseSitemapFile parses a local sitemap file (XML, gzipped XML or plain text).
+// Sitemaps listed by a local sitemap index are fetched.
+func (s *SitemapParser) ParseSitemapFile(path string) ([]*models.SitemapEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sitemap: %w", err)
+	}
+	body, err := decodeSitemap(data)
+	if err != nil {
+		return nil, err
+	}
+
+	walk := newSitemapWalk(s)
+	walk.visited[path] = true
+	if err := walk.parse(path, body, 0); err != nil {
+		return nil, err
+	}
+	return walk.entries, nil
+}
+
+// DiscoverSitemapURL attempts to discover sitemap.xml URL from a base URL
+func (s *SitemapParser) DiscoverSitemapURL(baseURL string) string {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%s://%s/sitemap.xml", u.Scheme, u.Host)
+}
+
+// fetchSitemap downloads a sitemap file, decompressing it when it is gzipped
+func (s *SitemapParser) fetchSitemap(sitemapURL string) ([]byte, error) {
+	result := s.fetcher.FetchResource(context.Background(), sitemapURL)
+	if result.PageResult.StatusCode != 0 && result.PageResult.StatusCode != 200 {
+		return nil, fmt.Errorf("sitemap returned HTTP %d", result.PageResult.StatusCode)
+	}
 	if result.Error != nil {
 		return nil, fmt.Errorf("failed to fetch sitemap: %w", result.Error)
 	}
 
-	if result.PageResult.StatusCode != 200 {
-		return nil, fmt.Errorf("sitemap returned HTTP %d", result.PageResult.StatusCode)
+	return decodeSitemap(result.Body)
+}
+
+// decodeSitemap decompresses a gzipped sitemap and enforces the size limit
+func decodeSitemap(body []byte) ([]byte, error) {
+	// .xml.gz files are gzipped; the transport already decoded any Content-Encoding
+	if len(body) >= 2 && body[0] == 0x1f && body[1] == 0x8b {
+		reader, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress sitemap: %w", err)
+		}
+		defer reader.Close()
+		if body, err = io.ReadAll(io.LimitReader(reader, maxSitemapSize+1)); err != nil {
+			return nil, fmt.Errorf("failed to decompress sitemap: %w", err)
+		}
 	}
+	if len(body) > maxSitemapSize {
+		return nil, fmt.Errorf("sitemap exceeds %d MB", maxSitemapSize/1024/1024)
+	}
+	return body, nil
+}
+
+// sitemapWalk collects entries across sitemap files, guarding against index loops
+type sitemapWalk struct {
+	parser  *SitemapParser
+	visited map[string]bool // Sitemap files already read
+	seen    map[string]bool // Normalized URLs already collected
+	entries []*models.SitemapEntry
+}
+
+// newSitemapWalk starts an empty walk
+func newSitemapWalk(parser *SitemapParser) *sitemapWalk {
+	return &sitemapWalk{
+		parser:  parser,
+		visited: make(map[string]bool),
+		seen:    make(map[string]bool),
+	}
+}
 
-	// Try parsing as sitemap index first
-	var index SitemapIndex
-	err := xml.Unmarshal(result.Body, &index)
-	if err == nil && len(index.Sitemaps) > 0 {
-		// It's a sitemap index, recursively parse each sitemap
-		seen := make(map[string]bool)
-		urls := make([]string, 0)
+// visit reads one sitemap file, recursing into the sitemaps listed by an index
+func (w *sitemapWalk) visit(sitemapURL string, depth int) error {
+	if w.visited[sitemapURL] {
+		utils.Debug("Skipping sitemap already read", utils.NewField("url", sitemapURL))
+		return nil
+	}
+	w.visited[sitemapURL] = true
+	if depth > maxSitemapDepth {
+		return fmt.Errorf("sitemap indexes nested deeper than %d levels", maxSitemapDepth)
+	}
+
+	body, err := w.parser.fetchSitemap(sitemapURL)
+	if err != nil {
+		return err
+	}
+	return w.parse(sitemapURL, body, depth)
+}
+
+// parse collects the entries of a sitemap file, visiting the sitemaps listed by an index
+func (w *sitemapWalk) parse(sitemapURL string, body []byte, depth int) error {
+	root, err := sitemapRoot(body)
+	if err != nil {
+		return fmt.Errorf("failed to parse sitemap XML: %w", err)
+	}
+
+	switch root {
+	case "sitemapindex":
+		var index SitemapIndex
+		if err := xml.Unmarshal(body, &index); err != nil {
+			return fmt.Errorf("failed to parse sitemap index XML: %w", err)
+		}
+		utils.Debug("Parsed sitemap index", utils.NewField("url", sitemapURL), utils.NewField("sitemaps", len(index.Sitemaps)))
 		for _, sitemap := range index.Sitemaps {
-			subURLs, err := s.ParseSitemap(strings.TrimSpace(sitemap.Loc))
-			if err != nil {
-				utils.Debug("Failed to parse sub-sitemap", utils.NewField("url", sitemap.Loc), utils.NewField("error", err.Error()))
-				continue
+			loc := resolveSitemapLoc(sitemapURL, sitemap.Loc)
+			if err := w.visit(loc, depth+1); err != nil {
+				utils.Debug("Failed to parse sub-sitemap", utils.NewField("url", loc), utils.NewField("error", err.Error()))
+			}
+		}
+	case "urlset":
+		var urlSet URLSet
+		if err := xml.Unmarshal(body, &urlSet); err != nil {
+			return fmt.Errorf("failed to parse sitemap XML: %w", err)
+		}
+		added := 0
+		for _, u := range urlSet.URLs {
+			if w.add(sitemapEntry(u, sitemapURL)) {
+				added++
 			}
-			// Deduplicate URLs from sub-sitemaps
-			for _, u := range subURLs {
-				if !seen[u] {
-					seen[u] = true
-					urls = append(urls, u)
-				}
+		}
+		utils.Debug("Parsed sitemap", utils.NewField("url", sitemapURL), utils.NewField("urls", len(urlSet.URLs)), utils.NewField("new", added))
+	case "":
+		// Plain text sitemap: one URL per line
+		scanner := bufio.NewScanner(bytes.NewReader(body))
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			if line := strings.TrimSpace(scanner.Text()); line != "" {
+				w.add(&models.SitemapEntry{Loc: line, Source: sitemapURL})
 			}
 		}
-		return urls, nil
+	default:
+		return fmt.Errorf("unsupported sitemap format <%s>", root)
 	}
+	return nil
+}
 
-	// Try parsing as URL set
-	var urlSet URLSet
-	err = xml.Unmarshal(result.Body, &urlSet)
+// add normalizes and collects an entry unless its URL was already listed
+func (w *sitemapWalk) add(entry *models.SitemapEntry) bool {
+	normalized, err := utils.NormalizeURL(strings.TrimSpace(entry.Loc))
+	if err == nil && !strings.HasPrefix(normalized, "http://") && !strings.HasPrefix(normalized, "https://") {
+		err = fmt.Errorf("not an http(s) URL")
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse sitemap XML: %w", err)
+		utils.Debug("Invalid URL in sitemap", utils.NewField("url", entry.Loc), utils.NewField("error", err.Error()))
+		return false
+	}
+	// Skip duplicates (e.g., same URL with/without trailing slash)
+	if w.seen[normalized] {
+		utils.Debug("Skipping duplicate URL in sitemap", utils.NewField("original", entry.Loc), utils.NewField("normalized", normalized))
+		return false
 	}
+	w.seen[normalized] = true
+	entry.Loc = normalized
+	w.entries = append(w.entries, entry)
+	return true
+}
 
-	// Extract URLs, normalize them, and deduplicate
-	seen := make(map[string]bool)
-	urls := make([]string, 0, len(urlSet.URLs))
-	for _, u := range urlSet.URLs {
-		normalized, err := utils.NormalizeURL(strings.TrimSpace(u.Loc))
-		if err != nil {
-			utils.Debug("Invalid URL in sitemap", utils.NewField("url", u.Loc), utils.NewField("error", err.Error()))
-			continue
+// sitemapEntry converts a parsed <url> element to its model
+func sitemapEntry(u URL, source string) *models.SitemapEntry {
+	entry := &models.SitemapEntry{
+		Loc:        u.Loc,
+		Source:     source,
+		LastMod:    strings.TrimSpace(u.LastMod),
+		ChangeFreq: strings.TrimSpace(u.ChangeFreq),
+		Priority:   strings.TrimSpace(u.Priority),
+	}
+	for _, image := range u.Images {
+		entry.Images = append(entry.Images, models.SitemapImage{
+			Loc:     strings.TrimSpace(image.Loc),
+			Title:   strings.TrimSpace(image.Title),
+			Caption: strings.TrimSpace(image.Caption),
+		})
+	}
+	for _, video := range u.Videos {
+		entry.Videos = append(entry.Videos, models.SitemapVideo{
+			Title:        strings.TrimSpace(video.Title),
+			Description:  strings.TrimSpace(video.Description),
+			ThumbnailLoc: strings.TrimSpace(video.ThumbnailLoc),
+			ContentLoc:   strings.TrimSpace(video.ContentLoc),
+			PlayerLoc:    strings.TrimSpace(video.PlayerLoc),
+		})
+	}
+	if u.News != nil {
+		entry.News = &models.SitemapNews{
+			PublicationName:     strings.TrimSpace(u.News.PublicationName),
+			PublicationLanguage: strings.TrimSpace(u.News.PublicationLanguage),
+			PublicationDate:     strings.TrimSpace(u.News.PublicationDate),
+			Title:               strings.TrimSpace(u.News.Title),
 		}
-		// Skip duplicates (e.g., same URL with/without trailing slash)
-		if seen[normalized] {
-			utils.Debug("Skipping duplicate URL in sitemap", utils.NewField("original", u.Loc), utils.NewField("normalized", normalized))
-			continue
+	}
+	for _, link := range u.Links {
+		if strings.EqualFold(link.Rel, "alternate") && link.Hreflang != "" {
+			entry.Alternates = append(entry.Alternates, models.SitemapAlternate{
+				Hreflang: strings.TrimSpace(link.Hreflang),
+				Href:     strings.TrimSpace(link.Href),
+			})
 		}
-		seen[normalized] = true
-		urls = append(urls, normalized)
+	}
+	return entry
+}
+
+// sitemapRoot returns the name of the root XML element, or "" when the file is not XML
+func sitemapRoot(body []byte) (string, error) {
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(body, []byte("\xef\xbb\xbf")))
+	if !bytes.HasPrefix(trimmed, []byte("<")) {
+		return "", nil
 	}
 
-	return urls, nil
+	decoder := xml.NewDecoder(bytes.NewReader(trimmed))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return "", err
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name.Local, nil
+		}
+	}
 }
 
-// DiscoverSitemapURL attempts to discover sitemap.xml URL from a base URL
-func (s *SitemapParser) DiscoverSitemapURL(baseURL string) string {
-	u, err := url.Parse(baseURL)
+// resolveSitemapLoc resolves a sitemap location listed in an index against the index URL
+func resolveSitemapLoc(indexURL, loc string) string {
+	loc = strings.TrimSpace(loc)
+	base, err := url.Parse(indexURL)
 	if err != nil {
-		return ""
+		return loc
 	}
-	return fmt.Sprintf("%s://%s/sitemap.xml", u.Scheme, u.Host)
+	ref, err := url.Parse(loc)
+	if err != nil {
+		return loc
+	}
+	return base.ResolveReference(ref).String()
 }
-
@@ -0,0 +1,92 @@
+package crawler
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseSitemapsFollowsIndexesAndExtensions(t *testing.T) {
+	var gzipped bytes.Buffer
+	gz := gzip.NewWriter(&gzipped)
+	fmt.Fprint(gz, `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9"
+        xmlns:image="http://www.google.com/schemas/sitemap-image/1.1"
+        xmlns:news="http://www.google.com/schemas/sitemap-news/0.9"
+        xmlns:xhtml="http://www.w3.org/1999/xhtml">
+  <url>
+    <loc>https://example.com/products/</loc>
+    <lastmod>2026-01-02</lastmod>
+    <changefreq>weekly</changefreq>
+    <priority>0.8</priority>
+    <image:image><image:loc>https://example.com/shoe.jpg</image:loc><image:title>Shoe</image:title></image:image>
+    <xhtml:link rel="alternate" hreflang="de" href="https://example.com/de/products"/>
+  </url>
+  <url>
+    <loc>https://example.com/news/launch</loc>
+    <news:news>
+      <news:publication><news:name>Example News</news:name><news:language>en</news:language></news:publication>
+      <news:publication_date>2026-01-01</news:publication_date>
+      <news:title>Launch</news:title>
+    </news:news>
+  </url>
+</urlset>`)
+	gz.Close()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/sitemap_index.xml", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		fmt.Fprint(w, `<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <sitemap><loc>/products.xml.gz</loc></sitemap>
+  <sitemap><loc>/pages.txt</loc></sitemap>
+  <sitemap><loc>/sitemap_index.xml</loc></sitemap>
+</sitemapindex>`)
+	})
+	mux.HandleFunc("/products.xml.gz", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/gzip")
+		w.Write(gzipped.Bytes())
+	})
+	mux.HandleFunc("/pages.txt", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(w, "https://example.com/about\nhttps://example.com/products\nnot a url\n")
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	parser := NewSitemapParser(NewFetcher(5*time.Second, "barracuda/1.0.0"))
+	entries, err := parser.ParseSitemaps([]string{server.URL + "/sitemap_index.xml"})
+	if err != nil {
+		t.Fatalf("ParseSitemaps() error = %v", err)
+	}
+
+	if len(entries) != 3 {
+		t.Fatalf("got %d entries, want 3 (duplicate and invalid URLs dropped, index loop ignored)", len(entries))
+	}
+
+	products := entries[0]
+	if products.Loc != "https://example.com/products" || products.Source != server.URL+"/products.xml.gz" {
+		t.Errorf("products entry loc = %q, source = %q", products.Loc, products.Source)
+	}
+	if products.LastMod != "2026-01-02" || products.ChangeFreq != "weekly" || products.Priority != "0.8" {
+		t.Errorf("products metadata = %q %q %q", products.LastMod, products.ChangeFreq, products.Priority)
+	}
+	if len(products.Images) != 1 || products.Images[0].Title != "Shoe" {
+		t.Errorf("products images = %+v", products.Images)
+	}
+	if len(products.Alternates) != 1 || products.Alternates[0].Hreflang != "de" {
+		t.Errorf("products alternates = %+v", products.Alternates)
+	}
+
+	news := entries[1]
+	if news.News == nil || news.News.PublicationName != "Example News" || news.News.Title != "Launch" {
+		t.Errorf("news entry = %+v", news.News)
+	}
+
+	if about := entries[2]; about.Loc != "https://example.com/about" || about.Source != server.URL+"/pages.txt" {
+		t.Errorf("text sitemap entry = %+v", about)
+	}
+}
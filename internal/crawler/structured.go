@@ -0,0 +1,188 @@
+package crawler
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/dillonlara115/barracudaseo/pkg/models"
+)
+
+// schemaOrgPrefixes are stripped from types and properties so "https://schema.org/Product"
+// and "schema:Product" are both reported as "Product"
+var schemaOrgPrefixes = []string{"https://schema.org/", "http://schema.org/", "schema:"}
+
+// findStructuredData extracts the top-level JSON-LD, Microdata and RDFa items of a page
+func findStructuredData(doc *goquery.Document) []models.StructuredData {
+	var items []models.StructuredData
+
+	doc.Find("script[type='application/ld+json']").Each(func(i int, s *goquery.Selection) {
+		items = append(items, parseJSONLD(s.Text())...)
+	})
+
+	// Microdata: items that are not themselves the property of another item
+	doc.Find("[itemscope]").Not("[itemprop]").Each(func(i int, s *goquery.Selection) {
+		item := models.StructuredData{
+			Format: models.StructuredDataMicrodata,
+			Types:  schemaNames(strings.Fields(s.AttrOr("itemtype", ""))),
+		}
+		var properties []string
+		s.Find("[itemprop]").Each(func(j int, prop *goquery.Selection) {
+			// Properties of nested items belong to those items
+			if prop.Parent().Closest("[itemscope]").IsSelection(s) && microdataHasValue(prop) {
+				properties = append(properties, strings.Fields(prop.AttrOr("itemprop", ""))...)
+			}
+		})
+		item.Properties = uniqueSorted(schemaNames(properties))
+		items = append(items, item)
+	})
+
+	// RDFa: typed resources that are not the property of another resource
+	doc.Find("[typeof]").Not("[property]").Each(func(i int, s *goquery.Selection) {
+		item := models.StructuredData{
+			Format: models.StructuredDataRDFa,
+			Types:  schemaNames(strings.Fields(s.AttrOr("typeof", ""))),
+		}
+		var properties []string
+		s.Find("[property]").Each(func(j int, prop *goquery.Selection) {
+			if prop.Parent().Closest("[typeof]").IsSelection(s) && rdfaHasValue(prop) {
+				properties = append(properties, strings.Fields(prop.AttrOr("property", ""))...)
+			}
+		})
+		item.Properties = uniqueSorted(schemaNames(properties))
+		items = append(items, item)
+	})
+
+	return items
+}
+
+// parseJSONLD returns the typed nodes of a JSON-LD block: the block itself, each element of a
+// top-level array, and each node of an @graph. A block that is not valid JSON is returned as a
+// single item with Error set.
+func parseJSONLD(source string) []models.StructuredData {
+	source = strings.TrimSpace(source)
+	if source == "" {
+		return nil
+	}
+
+	var data interface{}
+	if err := json.Unmarshal([]byte(source), &data); err != nil {
+		return []models.StructuredData{{Format: models.StructuredDataJSONLD, Error: err.Error()}}
+	}
+
+	var nodes []map[string]interface{}
+	var collect func(value interface{})
+	collect = func(value interface{}) {
+		switch v := value.(type) {
+		case []interface{}:
+			for _, element := range v {
+				collect(element)
+			}
+		case map[string]interface{}:
+			if graph, ok := v["@graph"]; ok {
+				collect(graph)
+			}
+			if _, ok := v["@type"]; ok {
+				nodes = append(nodes, v)
+			}
+		}
+	}
+	collect(data)
+
+	items := make([]models.StructuredData, 0, len(nodes))
+	for _, node := range nodes {
+		item := models.StructuredData{Format: models.StructuredDataJSONLD}
+		switch t := node["@type"].(type) {
+		case string:
+			item.Types = schemaNames([]string{t})
+		case []interface{}:
+			for _, value := range t {
+				if name, ok := value.(string); ok {
+					item.Types = append(item.Types, schemaNames([]string{name})...)
+				}
+			}
+		}
+		var properties []string
+		for key, value := range node {
+			if !strings.HasPrefix(key, "@") && jsonHasValue(value) {
+				properties = append(properties, key)
+			}
+		}
+		item.Properties = uniqueSorted(schemaNames(properties))
+		items = append(items, item)
+	}
+	return items
+}
+
+// jsonHasValue reports whether a JSON-LD property value is not empty
+func jsonHasValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case string:
+		return strings.TrimSpace(v) != ""
+	case []interface{}:
+		return len(v) > 0
+	case map[string]interface{}:
+		return len(v) > 0
+	}
+	return true
+}
+
+// microdataHasValue reports whether a Microdata property has a value, taken from the attribute
+// the HTML element uses for it or from its text
+func microdataHasValue(s *goquery.Selection) bool {
+	if _, nested := s.Attr("itemscope"); nested {
+		return true
+	}
+	for _, attr := range []string{"content", "href", "src", "datetime", "value", "data"} {
+		if value, ok := s.Attr(attr); ok {
+			return strings.TrimSpace(value) != ""
+		}
+	}
+	return strings.TrimSpace(s.Text()) != ""
+}
+
+// rdfaHasValue reports whether an RDFa property has a value
+func rdfaHasValue(s *goquery.Selection) bool {
+	if _, typed := s.Attr("typeof"); typed {
+		return true
+	}
+	for _, attr := range []string{"content", "resource", "href", "src"} {
+		if value, ok := s.Attr(attr); ok {
+			return strings.TrimSpace(value) != ""
+		}
+	}
+	return strings.TrimSpace(s.Text()) != ""
+}
+
+// schemaNames strips the schema.org vocabulary from type and property names
+func schemaNames(names []string) []string {
+	result := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		for _, prefix := range schemaOrgPrefixes {
+			if strings.HasPrefix(name, prefix) {
+				name = strings.TrimPrefix(name, prefix)
+				break
+			}
+		}
+		if name != "" {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+// uniqueSorted sorts names and removes duplicates
+func uniqueSorted(names []string) []string {
+	sort.Strings(names)
+	unique := names[:0]
+	for i, name := range names {
+		if i == 0 || name != names[i-1] {
+			unique = append(unique, name)
+		}
+	}
+	return unique
+}
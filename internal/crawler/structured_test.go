@@ -0,0 +1,76 @@
+package crawler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dillonlara115/barracudaseo/pkg/models"
+)
+
+const structuredDataPage = `<!DOCTYPE html>
+<html><head>
+<script type="application/ld+json">
+{"@context": "https://schema.org", "@graph": [
+  {"@type": "Organization", "name": "Example", "url": "https://example.com/", "logo": ""},
+  {"@type": ["Product", "https://schema.org/IndividualProduct"], "name": "Widget",
+   "offers": {"@type": "Offer", "price": "9.99"}}
+]}
+</script>
+<script type="application/ld+json">{"@type": "Article", "headline": "Broken",}</script>
+</head><body>
+<div itemscope itemtype="https://schema.org/BreadcrumbList">
+  <div itemprop="itemListElement" itemscope itemtype="https://schema.org/ListItem">
+    <a itemprop="item" href="/"><span itemprop="name">Home</span></a>
+    <meta itemprop="position" content="1">
+  </div>
+</div>
+<div vocab="https://schema.org/" typeof="LocalBusiness">
+  <span property="name">Example Cafe</span>
+  <div property="address" typeof="PostalAddress"><span property="streetLocality">Main St</span></div>
+  <span property="telephone"></span>
+</div>
+</body></html>`
+
+func TestParserFindsStructuredData(t *testing.T) {
+	parser, err := NewParser("https://example.com/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := parser.Parse([]byte(structuredDataPage))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]models.StructuredData)
+	invalid := 0
+	for _, item := range result.StructuredData {
+		if item.Error != "" {
+			invalid++
+			continue
+		}
+		got[item.Format+" "+strings.Join(item.Types, ",")] = item
+	}
+	if invalid != 1 {
+		t.Errorf("expected 1 invalid JSON-LD block, got %d", invalid)
+	}
+
+	want := map[string]string{
+		"json-ld Organization":              "name,url",
+		"json-ld Product,IndividualProduct": "name,offers",
+		"microdata BreadcrumbList":          "itemListElement",
+		"rdfa LocalBusiness":                "address,name",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d items, got %+v", len(want), result.StructuredData)
+	}
+	for key, properties := range want {
+		item, ok := got[key]
+		if !ok {
+			t.Errorf("missing item %q in %+v", key, result.StructuredData)
+			continue
+		}
+		if strings.Join(item.Properties, ",") != properties {
+			t.Errorf("%s properties = %v, want %s", key, item.Properties, properties)
+		}
+	}
+}
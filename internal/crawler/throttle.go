@@ -0,0 +1,274 @@
+package crawler
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/dillonlara115/barracudaseo/internal/utils"
+)
+
+const (
+	// throttleMaxDelay caps the per-host delay, including Crawl-delay and backoff
+	throttleMaxDelay = 60 * time.Second
+	// throttleBackoffMin is the smallest delay applied after a 429 or 503 response
+	throttleBackoffMin = 1 * time.Second
+	// throttleSlowStep is the smallest delay applied when response times rise
+	throttleSlowStep = 250 * time.Millisecond
+	// slowResponseFactor is how much slower than its fastest average a host must get to be slowed down
+	slowResponseFactor = 2
+	// slowResponseMargin ignores rising response times below this absolute increase
+	slowResponseMargin = 500 * time.Millisecond
+	// rateWindow is the window over which the effective request rate is measured
+	rateWindow = 10 * time.Second
+)
+
+// hostThrottleState tracks the request schedule and response history of one host
+type hostThrottleState struct {
+	delay       time.Duration // Current delay between request starts
+	minDelay    time.Duration // Floor for delay: max(Config.Delay, Crawl-delay)
+	next        time.Time     // Earliest time the next request may start
+	avgResponse time.Duration // Moving average of response times
+	baseline    time.Duration // Fastest moving average seen, used to detect slowdowns
+	recent      []time.Time   // Request start times within rateWindow
+}
+
+// HostThrottle spaces out requests per host. It starts from the configured delay, honours
+// robots.txt Crawl-delay, backs off on 429/503 and rising response times (waiting at least
+// as long as Retry-After), and recovers gradually once the host responds normally again.
+type HostThrottle struct {
+	mu        sync.Mutex
+	hosts     map[string]*hostThrottleState
+	baseDelay time.Duration
+}
+
+// NewHostThrottle creates a new HostThrottle with baseDelay as the minimum delay per host
+func NewHostThrottle(baseDelay time.Duration) *HostThrottle {
+	return &HostThrottle{
+		hosts:     make(map[string]*hostThrottleState),
+		baseDelay: baseDelay,
+	}
+}
+
+// Wait blocks until a request to targetURL's host may start. It returns ctx.Err() if ctx is done first.
+func (t *HostThrottle) Wait(ctx context.Context, targetURL string) error {
+	t.mu.Lock()
+	state := t.state(hostOf(targetURL))
+	start := time.Now()
+	if state.next.After(start) {
+		start = state.next
+	}
+	state.next = start.Add(state.delay)
+	state.pruneRecent(time.Now())
+	state.recent = append(state.recent, start)
+	t.mu.Unlock()
+
+	wait := time.Until(start)
+	if wait <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Observe adjusts a host's delay from the outcome of a request
+func (t *HostThrottle) Observe(targetURL string, statusCode int, responseTime, retryAfter time.Duration) {
+	host := hostOf(targetURL)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	state := t.state(host)
+
+	if statusCode == 429 || statusCode == 503 {
+		delay := state.delay * 2
+		if delay < throttleBackoffMin {
+			delay = throttleBackoffMin
+		}
+		state.delay = capDelay(delay)
+
+		// Pause the host for Retry-After (or the new delay), whichever is longer
+		pause := state.delay
+		if retryAfter > pause {
+			pause = retryAfter
+		}
+		if until := time.Now().Add(pause); until.After(state.next) {
+			state.next = until
+		}
+
+		utils.Warn("Host is throttling requests, backing off",
+			utils.NewField("host", host),
+			utils.NewField("status", statusCode),
+			utils.NewField("retry_after", retryAfter.String()),
+			utils.NewField("delay", state.delay.String()))
+		return
+	}
+
+	if statusCode == 0 || responseTime <= 0 {
+		return
+	}
+
+	// Exponential moving average of response times
+	if state.avgResponse == 0 {
+		state.avgResponse = responseTime
+	} else {
+		state.avgResponse = (state.avgResponse*4 + responseTime) / 5
+	}
+	if state.baseline == 0 || state.avgResponse < state.baseline {
+		state.baseline = state.avgResponse
+	}
+
+	if state.avgResponse > state.baseline*slowResponseFactor && state.avgResponse-state.baseline > slowResponseMargin {
+		delay := state.delay * 3 / 2
+		if delay < throttleSlowStep {
+			delay = throttleSlowStep
+		}
+		delay = capDelay(delay)
+		if delay != state.delay {
+			utils.Debug("Host response times rising, slowing down",
+				utils.NewField("host", host),
+				utils.NewField("avg_response_ms", state.avgResponse.Milliseconds()),
+				utils.NewField("baseline_ms", state.baseline.Milliseconds()),
+				utils.NewField("delay", delay.String()))
+		}
+		state.delay = delay
+		return
+	}
+
+	// Recover gradually towards the minimum delay
+	state.delay -= state.delay / 10
+	if state.delay < state.minDelay+10*time.Millisecond {
+		state.delay = state.minDelay
+	}
+}
+
+// SetCrawlDelay applies a robots.txt Crawl-delay to a host. The delay never drops below it.
+func (t *HostThrottle) SetCrawlDelay(targetURL string, crawlDelay time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	state := t.state(hostOf(targetURL))
+	minDelay := t.baseDelay
+	if crawlDelay > minDelay {
+		minDelay = capDelay(crawlDelay)
+	}
+	if minDelay == state.minDelay {
+		return
+	}
+
+	state.minDelay = minDelay
+	if state.delay < minDelay {
+		state.delay = minDelay
+	}
+	utils.Debug("Applied crawl delay", utils.NewField("url", targetURL), utils.NewField("delay", minDelay.String()))
+}
+
+// Delay returns the current delay between requests to targetURL's host
+func (t *HostThrottle) Delay(targetURL string) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.state(hostOf(targetURL)).delay
+}
+
+// Rate returns the effective request rate (requests per second) across all hosts over the last rateWindow
+func (t *HostThrottle) Rate() float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	var oldest time.Time
+	count := 0
+	for _, state := range t.hosts {
+		state.pruneRecent(now)
+		for _, start := range state.recent {
+			if start.After(now) {
+				continue // Scheduled but not started yet
+			}
+			count++
+			if oldest.IsZero() || start.Before(oldest) {
+				oldest = start
+			}
+		}
+	}
+	if count == 0 {
+		return 0
+	}
+
+	// Measure over the elapsed time until the window is full, so early rates are not understated
+	window := now.Sub(oldest)
+	if window < time.Second {
+		window = time.Second
+	}
+	return float64(count) / window.Seconds()
+}
+
+// state returns the state for a host, creating it if needed. Caller holds t.mu.
+func (t *HostThrottle) state(host string) *hostThrottleState {
+	state, ok := t.hosts[host]
+	if !ok {
+		state = &hostThrottleState{
+			delay:    t.baseDelay,
+			minDelay: t.baseDelay,
+		}
+		t.hosts[host] = state
+	}
+	return state
+}
+
+// pruneRecent drops request starts that fell out of rateWindow
+func (s *hostThrottleState) pruneRecent(now time.Time) {
+	cutoff := now.Add(-rateWindow)
+	kept := s.recent[:0]
+	for _, start := range s.recent {
+		if start.After(cutoff) {
+			kept = append(kept, start)
+		}
+	}
+	s.recent = kept
+}
+
+// hostOf returns the host of a URL, or the URL itself if it cannot be parsed
+func hostOf(targetURL string) string {
+	u, err := url.Parse(targetURL)
+	if err != nil || u.Host == "" {
+		return targetURL
+	}
+	return u.Host
+}
+
+// capDelay limits a delay to throttleMaxDelay
+func capDelay(delay time.Duration) time.Duration {
+	if delay > throttleMaxDelay {
+		return throttleMaxDelay
+	}
+	return delay
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(date); wait > 0 {
+			return wait
+		}
+	}
+	return 0
+}
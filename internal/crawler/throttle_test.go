@@ -0,0 +1,80 @@
+package crawler
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected time.Duration
+	}{
+		{name: "Empty", value: "", expected: 0},
+		{name: "Seconds", value: "120", expected: 120 * time.Second},
+		{name: "Negative", value: "-5", expected: 0},
+		{name: "Past date", value: "Wed, 21 Oct 2015 07:28:00 GMT", expected: 0},
+		{name: "Garbage", value: "soon", expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseRetryAfter(tt.value); got != tt.expected {
+				t.Errorf("parseRetryAfter(%q) = %v, expected %v", tt.value, got, tt.expected)
+			}
+		})
+	}
+
+	future := time.Now().Add(30 * time.Second).UTC().Format(http.TimeFormat)
+	if got := parseRetryAfter(future); got < 25*time.Second || got > 30*time.Second {
+		t.Errorf("parseRetryAfter(date) = %v, expected about 30s", got)
+	}
+}
+
+func TestHostThrottleBacksOff(t *testing.T) {
+	throttle := NewHostThrottle(0)
+	pageURL := "https://example.com/page"
+
+	throttle.Observe(pageURL, 429, 100*time.Millisecond, 0)
+	if got := throttle.Delay(pageURL); got != throttleBackoffMin {
+		t.Errorf("Delay after 429 = %v, expected %v", got, throttleBackoffMin)
+	}
+
+	throttle.Observe(pageURL, 503, 100*time.Millisecond, 0)
+	if got := throttle.Delay(pageURL); got != 2*throttleBackoffMin {
+		t.Errorf("Delay after second 503 = %v, expected %v", got, 2*throttleBackoffMin)
+	}
+
+	// Other hosts are not affected
+	if got := throttle.Delay("https://other.example.com/"); got != 0 {
+		t.Errorf("Delay for other host = %v, expected 0", got)
+	}
+
+	// Successful responses recover towards the minimum
+	for i := 0; i < 100; i++ {
+		throttle.Observe(pageURL, 200, 100*time.Millisecond, 0)
+	}
+	if got := throttle.Delay(pageURL); got != 0 {
+		t.Errorf("Delay after recovery = %v, expected 0", got)
+	}
+}
+
+func TestHostThrottleCrawlDelay(t *testing.T) {
+	throttle := NewHostThrottle(100 * time.Millisecond)
+	pageURL := "https://example.com/page"
+
+	throttle.SetCrawlDelay(pageURL, 2*time.Second)
+	for i := 0; i < 10; i++ {
+		throttle.Observe(pageURL, 200, 50*time.Millisecond, 0)
+	}
+	if got := throttle.Delay(pageURL); got != 2*time.Second {
+		t.Errorf("Delay with Crawl-delay = %v, expected 2s", got)
+	}
+
+	throttle.SetCrawlDelay(pageURL, time.Hour)
+	if got := throttle.Delay(pageURL); got != throttleMaxDelay {
+		t.Errorf("Delay with huge Crawl-delay = %v, expected %v", got, throttleMaxDelay)
+	}
+}
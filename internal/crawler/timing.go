@@ -0,0 +1,80 @@
+package crawler
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/dillonlara115/barracudaseo/pkg/models"
+)
+
+// requestTrace records when each phase of a single request started and finished.
+// Dial callbacks can run on other goroutines (one per address tried), hence the mutex.
+type requestTrace struct {
+	mu           sync.Mutex
+	start        time.Time
+	dnsStart     time.Time
+	dnsDone      time.Time
+	connectStart time.Time
+	connectDone  time.Time
+	tlsStart     time.Time
+	tlsDone      time.Time
+	firstByte    time.Time
+	reused       bool
+}
+
+// newRequestTrace starts timing a request and returns a context that reports its phases
+func newRequestTrace(ctx context.Context) (*requestTrace, context.Context) {
+	t := &requestTrace{start: time.Now()}
+	return t, httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { t.mark(&t.dnsStart, false) },
+		DNSDone:  func(httptrace.DNSDoneInfo) { t.mark(&t.dnsDone, true) },
+		ConnectStart: func(string, string) {
+			t.mark(&t.connectStart, false)
+		},
+		ConnectDone: func(string, string, error) {
+			t.mark(&t.connectDone, true)
+		},
+		TLSHandshakeStart: func() { t.mark(&t.tlsStart, false) },
+		TLSHandshakeDone:  func(tls.ConnectionState, error) { t.mark(&t.tlsDone, true) },
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mu.Lock()
+			t.reused = info.Reused
+			t.mu.Unlock()
+		},
+		GotFirstResponseByte: func() { t.mark(&t.firstByte, false) },
+	})
+}
+
+// mark records the current time in field. Start times keep the first call and end times the last,
+// so a dial that tries several addresses is timed from the first attempt to the last.
+func (t *requestTrace) mark(field *time.Time, last bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if field.IsZero() || last {
+		*field = time.Now()
+	}
+}
+
+// timing returns the phase durations recorded so far. DownloadMs is filled in once the body is read.
+func (t *requestTrace) timing() *models.Timing {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return &models.Timing{
+		DNSMs:     phaseMs(t.dnsStart, t.dnsDone),
+		ConnectMs: phaseMs(t.connectStart, t.connectDone),
+		TLSMs:     phaseMs(t.tlsStart, t.tlsDone),
+		TTFBMs:    phaseMs(t.start, t.firstByte),
+		Reused:    t.reused,
+	}
+}
+
+// phaseMs returns the milliseconds between two recorded times, or 0 if the phase did not happen
+func phaseMs(start, end time.Time) int64 {
+	if start.IsZero() || end.IsZero() || end.Before(start) {
+		return 0
+	}
+	return end.Sub(start).Milliseconds()
+}
@@ -0,0 +1,44 @@
+package crawler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestFetchRecordsTiming(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(60 * time.Millisecond) // Server think time, before the first byte
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html><head><title>Slow</title></head>"))
+		w.(http.Flusher).Flush()
+		time.Sleep(60 * time.Millisecond) // Slow transfer of the rest of the body
+		w.Write([]byte("<body></body></html>"))
+	}))
+	defer server.Close()
+
+	fetcher := NewFetcher(5*time.Second, "test-agent")
+	result := fetcher.Fetch(server.URL + "/")
+	if result.Error != nil {
+		t.Fatalf("Fetch() error = %v", result.Error)
+	}
+	timing := result.PageResult.Timing
+	if timing == nil {
+		t.Fatal("expected timing to be recorded")
+	}
+	if timing.TTFBMs < 50 {
+		t.Errorf("TTFBMs = %d, want at least the 60ms server delay", timing.TTFBMs)
+	}
+	if timing.DownloadMs < 50 {
+		t.Errorf("DownloadMs = %d, want at least the 60ms transfer delay", timing.DownloadMs)
+	}
+	if timing.Reused {
+		t.Error("first request cannot reuse a connection")
+	}
+
+	result = fetcher.Fetch(server.URL + "/again")
+	if timing := result.PageResult.Timing; timing == nil || !timing.Reused || timing.ConnectMs != 0 {
+		t.Errorf("expected the second request to reuse the connection, got %+v", timing)
+	}
+}
@@ -0,0 +1,111 @@
+package crawler
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/dillonlara115/barracudaseo/pkg/models"
+)
+
+// tlsRecorder keeps the TLS details of each host the first time it is seen. It is shared by
+// fetcher copies (see WithUserAgent) and safe for concurrent use.
+type tlsRecorder struct {
+	mu    sync.Mutex
+	hosts map[string]*models.TLSInfo
+}
+
+func newTLSRecorder() *tlsRecorder {
+	return &tlsRecorder{hosts: make(map[string]*models.TLSInfo)}
+}
+
+// record stores the details of an established connection, returning what is stored for the host
+func (r *tlsRecorder) record(host string, state *tls.ConnectionState) *models.TLSInfo {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if info, ok := r.hosts[host]; ok {
+		return info
+	}
+	info := tlsInfo(host, state)
+	r.hosts[host] = info
+	return info
+}
+
+// probe connects to a host whose certificate was rejected, without verification, so the
+// certificate can still be described. verifyErr is the error the crawler's request failed with.
+func (r *tlsRecorder) probe(ctx context.Context, hostport string, verifyErr error) *models.TLSInfo {
+	host, _, err := net.SplitHostPort(hostport)
+	if err != nil {
+		host = hostport
+		hostport = net.JoinHostPort(hostport, "443")
+	}
+
+	r.mu.Lock()
+	if info, ok := r.hosts[host]; ok {
+		r.mu.Unlock()
+		return info
+	}
+	r.mu.Unlock()
+
+	info := &models.TLSInfo{Host: host}
+	dialer := &tls.Dialer{Config: &tls.Config{
+		ServerName:         host,
+		InsecureSkipVerify: true, // Inspect only; the page request itself failed verification
+		MinVersion:         tls.VersionTLS10,
+	}}
+	if conn, err := dialer.DialContext(ctx, "tcp", hostport); err == nil {
+		state := conn.(*tls.Conn).ConnectionState()
+		info = tlsInfo(host, &state)
+		conn.Close()
+	}
+	info.Error = verifyErr.Error()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hosts[host] = info
+	return info
+}
+
+// tlsInfo describes a connection state and its leaf certificate
+func tlsInfo(host string, state *tls.ConnectionState) *models.TLSInfo {
+	info := &models.TLSInfo{
+		Host:    host,
+		Version: tls.VersionName(state.Version),
+	}
+	if len(state.PeerCertificates) == 0 {
+		return info
+	}
+	leaf := state.PeerCertificates[0]
+	info.Issuer = certificateName(leaf.Issuer.CommonName, leaf.Issuer.Organization)
+	info.Subject = certificateName(leaf.Subject.CommonName, leaf.Subject.Organization)
+	info.DNSNames = leaf.DNSNames
+	info.NotBefore = leaf.NotBefore
+	info.NotAfter = leaf.NotAfter
+	info.CoversHost = leaf.VerifyHostname(host) == nil
+	return info
+}
+
+// certificateName formats a certificate name as "CN (Organization)"
+func certificateName(commonName string, organization []string) string {
+	if len(organization) == 0 {
+		return commonName
+	}
+	if commonName == "" {
+		return strings.Join(organization, ", ")
+	}
+	return commonName + " (" + strings.Join(organization, ", ") + ")"
+}
+
+// isCertificateError reports whether a request failed because the server's certificate was rejected
+func isCertificateError(err error) bool {
+	var verifyErr *tls.CertificateVerificationError
+	var hostnameErr x509.HostnameError
+	var authorityErr x509.UnknownAuthorityError
+	var invalidErr x509.CertificateInvalidError
+	return errors.As(err, &verifyErr) || errors.As(err, &hostnameErr) ||
+		errors.As(err, &authorityErr) || errors.As(err, &invalidErr)
+}
@@ -0,0 +1,78 @@
+package crawler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const mixedContentPage = `<html><head>
+<link rel="stylesheet" href="http://cdn.example.com/site.css">
+<link rel="canonical" href="http://example.com/">
+<script src="//cdn.example.com/app.js"></script>
+</head><body>
+<img src="http://img.example.com/logo.png"><img src="/local.png">
+<iframe src="http://widgets.example.com/embed"></iframe>
+</body></html>`
+
+func TestFetchRecordsTLSDetails(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(mixedContentPage))
+	}))
+	defer server.Close()
+
+	// The test certificate is not trusted, so the request fails but the certificate is still described
+	result := NewFetcher(5*time.Second, "test-agent").Fetch(server.URL + "/")
+	if result.Error == nil {
+		t.Fatal("expected an untrusted certificate to fail the request")
+	}
+	if info := result.PageResult.TLS; info == nil || info.Error == "" || info.Version == "" || info.NotAfter.IsZero() {
+		t.Fatalf("expected rejected certificate details, got %+v", info)
+	}
+
+	fetcher := NewFetcher(5*time.Second, "test-agent")
+	fetcher.client.Transport = server.Client().Transport
+	result = fetcher.Fetch(server.URL + "/")
+	if result.Error != nil {
+		t.Fatalf("Fetch() error = %v", result.Error)
+	}
+	info := result.PageResult.TLS
+	if info == nil || info.Error != "" || !info.CoversHost || info.Host != "127.0.0.1" {
+		t.Fatalf("unexpected TLS details %+v", info)
+	}
+	if info.Version != "TLS 1.3" {
+		t.Errorf("Version = %q, want TLS 1.3", info.Version)
+	}
+}
+
+func TestParserFindsMixedContent(t *testing.T) {
+	parser, err := NewParser("https://example.com/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := parser.Parse([]byte(mixedContentPage))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"http://cdn.example.com/site.css":  "link",
+		"http://img.example.com/logo.png":  "img",
+		"http://widgets.example.com/embed": "iframe",
+	}
+	if len(result.MixedContent) != len(want) {
+		t.Fatalf("expected %d mixed content resources, got %+v", len(want), result.MixedContent)
+	}
+	for _, resource := range result.MixedContent {
+		if want[resource.URL] != resource.Tag {
+			t.Errorf("unexpected mixed content %+v", resource)
+		}
+	}
+
+	parser, _ = NewParser("http://example.com/")
+	if result, _ := parser.Parse([]byte(mixedContentPage)); len(result.MixedContent) != 0 {
+		t.Errorf("HTTP pages cannot have mixed content, got %+v", result.MixedContent)
+	}
+}
@@ -39,7 +39,12 @@ func ExportCSV(results []*models.PageResult, filePath string) error {
 		"Internal Links",
 		"External Links",
 		"Redirect Chain",
+		"Word Count",
+		"Text Ratio (%)",
+		"Text Hash",
+		"SimHash",
 		"Error",
+		"Unchanged",
 		"Crawled At",
 	}
 	if err := writer.Write(header); err != nil {
@@ -64,7 +69,12 @@ func ExportCSV(results []*models.PageResult, filePath string) error {
 			strings.Join(result.InternalLinks, " | "),
 			strings.Join(result.ExternalLinks, " | "),
 			strings.Join(result.RedirectChain, " -> "),
+			strconv.Itoa(result.WordCount),
+			strconv.FormatFloat(result.TextRatio, 'f', -1, 64),
+			result.TextHash,
+			result.SimHash,
 			result.Error,
+			strconv.FormatBool(result.Unchanged),
 			result.CrawledAt.Format(time.RFC3339),
 		}
 		if err := writer.Write(row); err != nil {
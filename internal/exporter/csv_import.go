@@ -91,6 +91,21 @@ func ImportCSV(filePath string) ([]*models.PageResult, error) {
 		result.MetaDesc = getField("meta description")
 		result.Canonical = getField("canonical")
 		result.Error = getField("error")
+		result.Unchanged = getField("unchanged") == "true"
+		result.TextHash = getField("text hash")
+		result.SimHash = getField("simhash")
+
+		// Content metrics
+		if wordsStr := getField("word count"); wordsStr != "" {
+			if words, err := strconv.Atoi(wordsStr); err == nil {
+				result.WordCount = words
+			}
+		}
+		if ratioStr := getField("text ratio (%)"); ratioStr != "" {
+			if ratio, err := strconv.ParseFloat(ratioStr, 64); err == nil {
+				result.TextRatio = ratio
+			}
+		}
 
 		// Parse array fields (pipe-separated)
 		if h1Str := getField("h1"); h1Str != "" {
@@ -0,0 +1,78 @@
+package exporter
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/dillonlara115/barracudaseo/pkg/models"
+)
+
+// ExportLinksCSV exports links, one row per link, to a CSV file
+func ExportLinksCSV(links []models.Link, filePath string) error {
+	file, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to create CSV file: %w", err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	defer writer.Flush()
+
+	header := []string{
+		"Source",
+		"URL",
+		"Anchor Text",
+		"Alt Text",
+		"Rel",
+		"Target",
+		"Position",
+		"Internal",
+		"Nofollow",
+	}
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	for _, link := range links {
+		row := []string{
+			link.Source,
+			link.URL,
+			link.Anchor,
+			link.Alt,
+			strings.Join(link.Rel, " "),
+			link.Target,
+			link.Position,
+			strconv.FormatBool(link.Internal),
+			strconv.FormatBool(link.Nofollow()),
+		}
+		if err := writer.Write(row); err != nil {
+			return fmt.Errorf("failed to write CSV row: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ExportLinksJSON exports links to a JSON file
+func ExportLinksJSON(links []models.Link, filePath string, pretty bool) error {
+	file, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to create JSON file: %w", err)
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	if pretty {
+		encoder.SetIndent("", "  ")
+	}
+
+	if err := encoder.Encode(links); err != nil {
+		return fmt.Errorf("failed to encode JSON: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,241 @@
+package exporter
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dillonlara115/barracudaseo/internal/analyzer"
+	"github.com/dillonlara115/barracudaseo/pkg/models"
+)
+
+const (
+	// SitemapMaxURLs is the protocol's limit on URLs per sitemap file
+	SitemapMaxURLs = 50000
+	// SitemapMaxBytes is the protocol's limit on the uncompressed size of a sitemap file
+	SitemapMaxBytes = 50 * 1024 * 1024
+)
+
+// Sources for the lastmod of generated sitemap URLs
+const (
+	LastModCrawl    = "crawl"    // Time the page was crawled
+	LastModPrevious = "previous" // lastmod from a previous sitemap, falling back to the crawl time
+	LastModNone     = "none"     // No lastmod
+)
+
+const sitemapNamespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+// SitemapURL is a URL selected for a generated sitemap
+type SitemapURL struct {
+	Loc     string
+	LastMod string
+}
+
+// SitemapOptions configures sitemap generation
+type SitemapOptions struct {
+	BaseURL  string // URL the files will be served from, used for sitemap index entries (default: origin of the first URL)
+	Gzip     bool   // Write .xml.gz files
+	MaxURLs  int    // URLs per file (default and maximum: SitemapMaxURLs)
+	MaxBytes int    // Uncompressed bytes per file (default and maximum: SitemapMaxBytes)
+}
+
+// SitemapFile is a generated sitemap or sitemap index
+type SitemapFile struct {
+	Name string
+	Data []byte // Gzipped when SitemapOptions.Gzip is set
+	URLs int    // URLs listed (sitemaps listed, for an index)
+}
+
+// SitemapURLs selects the crawled pages that belong in a sitemap - 200, indexable, self-canonical
+// pages reached without redirects - sorted by URL. lastmod is one of the LastMod sources; previous
+// holds lastmod values by URL from an earlier sitemap (a page's own sitemap metadata is used otherwise).
+func SitemapURLs(results []*models.PageResult, lastmod string, previous map[string]string) []SitemapURL {
+	seen := make(map[string]bool)
+	urls := make([]SitemapURL, 0, len(results))
+	for _, result := range results {
+		if !analyzer.IsIndexablePage(result) || seen[result.URL] {
+			continue
+		}
+		seen[result.URL] = true
+
+		entry := SitemapURL{Loc: result.URL}
+		switch lastmod {
+		case LastModNone:
+		case LastModPrevious:
+			if value, ok := previous[result.URL]; ok && value != "" {
+				entry.LastMod = value
+			} else if result.Sitemap != nil && result.Sitemap.LastMod != "" {
+				entry.LastMod = result.Sitemap.LastMod
+			} else {
+				entry.LastMod = crawlLastMod(result)
+			}
+		default:
+			entry.LastMod = crawlLastMod(result)
+		}
+		urls = append(urls, entry)
+	}
+
+	sort.Slice(urls, func(i, j int) bool { return urls[i].Loc < urls[j].Loc })
+	return urls
+}
+
+// crawlLastMod formats the crawl time of a page as a W3C date
+func crawlLastMod(result *models.PageResult) string {
+	if result.CrawledAt.IsZero() {
+		return ""
+	}
+	return result.CrawledAt.UTC().Format("2006-01-02")
+}
+
+// BuildSitemaps renders URLs into sitemap files, splitting at the URL and size limits.
+// A single file is named sitemap.xml; otherwise the parts are sitemap-1.xml, sitemap-2.xml, ...
+// and sitemap.xml is a sitemap index listing them. The index is always the first file.
+func BuildSitemaps(urls []SitemapURL, opts SitemapOptions) ([]SitemapFile, error) {
+	maxURLs := opts.MaxURLs
+	if maxURLs <= 0 || maxURLs > SitemapMaxURLs {
+		maxURLs = SitemapMaxURLs
+	}
+	maxBytes := opts.MaxBytes
+	if maxBytes <= 0 || maxBytes > SitemapMaxBytes {
+		maxBytes = SitemapMaxBytes
+	}
+	ext := ".xml"
+	if opts.Gzip {
+		ext = ".xml.gz"
+	}
+
+	header := xml.Header + `<urlset xmlns="` + sitemapNamespace + `">` + "\n"
+	footer := "</urlset>\n"
+
+	var parts []SitemapFile
+	var current bytes.Buffer
+	count := 0
+	flush := func() error {
+		current.WriteString(footer)
+		data, err := compressSitemap(current.Bytes(), opts.Gzip)
+		if err != nil {
+			return err
+		}
+		parts = append(parts, SitemapFile{Data: data, URLs: count})
+		current.Reset()
+		count = 0
+		return nil
+	}
+
+	for _, u := range urls {
+		entry := sitemapURLElement(u)
+		if count > 0 && (count >= maxURLs || current.Len()+len(entry)+len(footer) > maxBytes) {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		}
+		if count == 0 {
+			current.WriteString(header)
+			if current.Len()+len(entry)+len(footer) > maxBytes {
+				return nil, fmt.Errorf("sitemap entry for %s exceeds the %d byte limit", u.Loc, maxBytes)
+			}
+		}
+		current.WriteString(entry)
+		count++
+	}
+	if count > 0 || len(parts) == 0 {
+		if count == 0 {
+			current.WriteString(header)
+		}
+		if err := flush(); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(parts) == 1 {
+		parts[0].Name = "sitemap" + ext
+		return parts, nil
+	}
+
+	baseURL, err := sitemapBaseURL(opts.BaseURL, urls)
+	if err != nil {
+		return nil, err
+	}
+	var index bytes.Buffer
+	index.WriteString(xml.Header + `<sitemapindex xmlns="` + sitemapNamespace + `">` + "\n")
+	for i := range parts {
+		parts[i].Name = fmt.Sprintf("sitemap-%d%s", i+1, ext)
+		index.WriteString("  <sitemap><loc>")
+		xml.EscapeText(&index, []byte(baseURL+parts[i].Name))
+		index.WriteString("</loc></sitemap>\n")
+	}
+	index.WriteString("</sitemapindex>\n")
+	data, err := compressSitemap(index.Bytes(), opts.Gzip)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]SitemapFile{{Name: "sitemap" + ext, Data: data, URLs: len(parts)}}, parts...), nil
+}
+
+// WriteSitemaps writes generated sitemap files into a directory, creating it if needed
+func WriteSitemaps(files []SitemapFile, dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create sitemap directory: %w", err)
+	}
+	for _, file := range files {
+		if err := os.WriteFile(filepath.Join(dir, file.Name), file.Data, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file.Name, err)
+		}
+	}
+	return nil
+}
+
+// sitemapURLElement renders one <url> element
+func sitemapURLElement(u SitemapURL) string {
+	var b bytes.Buffer
+	b.WriteString("  <url><loc>")
+	xml.EscapeText(&b, []byte(u.Loc))
+	b.WriteString("</loc>")
+	if u.LastMod != "" {
+		b.WriteString("<lastmod>")
+		xml.EscapeText(&b, []byte(u.LastMod))
+		b.WriteString("</lastmod>")
+	}
+	b.WriteString("</url>\n")
+	return b.String()
+}
+
+// sitemapBaseURL returns the directory URL sitemap index entries point to, ending in a slash
+func sitemapBaseURL(baseURL string, urls []SitemapURL) (string, error) {
+	if baseURL == "" && len(urls) > 0 {
+		if u, err := url.Parse(urls[0].Loc); err == nil {
+			baseURL = u.Scheme + "://" + u.Host
+		}
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil || !u.IsAbs() {
+		return "", fmt.Errorf("invalid sitemap base URL %q", baseURL)
+	}
+	if !strings.HasSuffix(baseURL, "/") {
+		baseURL += "/"
+	}
+	return baseURL, nil
+}
+
+// compressSitemap gzips sitemap data when requested
+func compressSitemap(data []byte, gzipped bool) ([]byte, error) {
+	if !gzipped {
+		return append([]byte(nil), data...), nil
+	}
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(data); err != nil {
+		return nil, fmt.Errorf("failed to compress sitemap: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress sitemap: %w", err)
+	}
+	return buf.Bytes(), nil
+}
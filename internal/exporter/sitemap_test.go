@@ -0,0 +1,100 @@
+package exporter
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dillonlara115/barracudaseo/pkg/models"
+)
+
+func TestSitemapURLs(t *testing.T) {
+	crawled := time.Date(2026, 3, 4, 15, 0, 0, 0, time.UTC)
+	results := []*models.PageResult{
+		{URL: "https://example.com/b", StatusCode: 200, IndexabilityStatus: models.IndexabilityIndexable, CrawledAt: crawled},
+		{URL: "https://example.com/a", StatusCode: 200, Canonical: "https://example.com/a", CrawledAt: crawled,
+			Sitemap: &models.SitemapEntry{Loc: "https://example.com/a", LastMod: "2025-12-01"}},
+		{URL: "https://example.com/missing", StatusCode: 404},
+		{URL: "https://example.com/moved", StatusCode: 200, RedirectChain: []string{"https://example.com/old"}},
+		{URL: "https://example.com/noindex", StatusCode: 200, IndexabilityStatus: models.IndexabilityNoindex},
+		{URL: "https://example.com/copy", StatusCode: 200, Canonical: "https://example.com/a"},
+		{URL: "https://example.com/b", StatusCode: 200, CrawledAt: crawled},
+	}
+
+	urls := SitemapURLs(results, LastModCrawl, nil)
+	if len(urls) != 2 || urls[0].Loc != "https://example.com/a" || urls[1].Loc != "https://example.com/b" {
+		t.Fatalf("unexpected URLs: %+v", urls)
+	}
+	if urls[0].LastMod != "2026-03-04" {
+		t.Errorf("expected crawl date lastmod, got %q", urls[0].LastMod)
+	}
+
+	urls = SitemapURLs(results, LastModPrevious, map[string]string{"https://example.com/b": "2024-01-01"})
+	if urls[0].LastMod != "2025-12-01" || urls[1].LastMod != "2024-01-01" {
+		t.Errorf("expected previous lastmod values, got %+v", urls)
+	}
+
+	urls = SitemapURLs(results, LastModNone, nil)
+	if urls[0].LastMod != "" {
+		t.Errorf("expected no lastmod, got %q", urls[0].LastMod)
+	}
+}
+
+func TestBuildSitemaps(t *testing.T) {
+	urls := make([]SitemapURL, 5)
+	for i := range urls {
+		urls[i] = SitemapURL{Loc: fmt.Sprintf("https://example.com/page?id=%d&x=1", i), LastMod: "2026-01-01"}
+	}
+
+	files, err := BuildSitemaps(urls, SitemapOptions{})
+	if err != nil {
+		t.Fatalf("BuildSitemaps failed: %v", err)
+	}
+	if len(files) != 1 || files[0].Name != "sitemap.xml" || files[0].URLs != 5 {
+		t.Fatalf("expected a single sitemap.xml, got %+v", files)
+	}
+	if !strings.Contains(string(files[0].Data), "<loc>https://example.com/page?id=0&amp;x=1</loc><lastmod>2026-01-01</lastmod>") {
+		t.Errorf("unexpected sitemap content:\n%s", files[0].Data)
+	}
+
+	files, err = BuildSitemaps(urls, SitemapOptions{MaxURLs: 2, Gzip: true, BaseURL: "https://cdn.example.com/maps"})
+	if err != nil {
+		t.Fatalf("BuildSitemaps failed: %v", err)
+	}
+	names := make([]string, len(files))
+	for i, file := range files {
+		names[i] = file.Name
+	}
+	if strings.Join(names, ",") != "sitemap.xml.gz,sitemap-1.xml.gz,sitemap-2.xml.gz,sitemap-3.xml.gz" {
+		t.Fatalf("unexpected files: %v", names)
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(files[0].Data))
+	if err != nil {
+		t.Fatalf("index is not gzipped: %v", err)
+	}
+	index, _ := io.ReadAll(reader)
+	if !strings.Contains(string(index), "<sitemapindex") || !strings.Contains(string(index), "<loc>https://cdn.example.com/maps/sitemap-3.xml.gz</loc>") {
+		t.Errorf("unexpected index content:\n%s", index)
+	}
+	if files[3].URLs != 1 {
+		t.Errorf("expected last part to hold 1 URL, got %d", files[3].URLs)
+	}
+
+	// The byte limit splits files before the URL limit does
+	files, err = BuildSitemaps(urls, SitemapOptions{MaxBytes: 300})
+	if err != nil {
+		t.Fatalf("BuildSitemaps failed: %v", err)
+	}
+	if len(files) < 3 {
+		t.Fatalf("expected the size limit to split the sitemap, got %d files", len(files))
+	}
+	for _, file := range files[1:] {
+		if len(file.Data) > 300 {
+			t.Errorf("%s is %d bytes, over the limit", file.Name, len(file.Data))
+		}
+	}
+}
@@ -1,12 +1,17 @@
 package graph
 
 import (
+	"sort"
+	"strings"
 	"sync"
+
+	"github.com/dillonlara115/barracudaseo/pkg/models"
 )
 
 // Graph represents a link graph with source -> target edges
 type Graph struct {
 	edges map[string][]string
+	links map[string][]models.Link // Anchor text, rel and position of the links behind the edges
 	mu    sync.RWMutex
 }
 
@@ -14,6 +19,7 @@ type Graph struct {
 func NewGraph() *Graph {
 	return &Graph{
 		edges: make(map[string][]string),
+		links: make(map[string][]models.Link),
 	}
 }
 
@@ -51,6 +57,64 @@ func (g *Graph) AddEdges(source string, targets []string) {
 	}
 }
 
+// AddLinks adds an edge from source to each link's target and keeps the links' details.
+// Links already recorded for the source are not added again.
+func (g *Graph) AddLinks(source string, links []models.Link) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	existing := make(map[string]bool)
+	for _, t := range g.edges[source] {
+		existing[t] = true
+	}
+	known := make(map[string]bool)
+	for _, link := range g.links[source] {
+		known[linkKey(link)] = true
+	}
+
+	for _, link := range links {
+		if !existing[link.URL] {
+			g.edges[source] = append(g.edges[source], link.URL)
+			existing[link.URL] = true
+		}
+		link.Source = source
+		if key := linkKey(link); !known[key] {
+			g.links[source] = append(g.links[source], link)
+			known[key] = true
+		}
+	}
+}
+
+// linkKey identifies a link by all of its attributes
+func linkKey(link models.Link) string {
+	return strings.Join([]string{link.URL, link.Anchor, link.Alt, strings.Join(link.Rel, " "), link.Target, link.Position}, "\x00")
+}
+
+// GetLinks returns the links found on a source node
+func (g *Graph) GetLinks(source string) []models.Link {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.links[source]
+}
+
+// GetLinkList returns every link in the graph with its Source set, ordered by source
+func (g *Graph) GetLinkList() []models.Link {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	sources := make([]string, 0, len(g.links))
+	for source := range g.links {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	linkList := make([]models.Link, 0)
+	for _, source := range sources {
+		linkList = append(linkList, g.links[source]...)
+	}
+	return linkList
+}
+
 // GetEdges returns all edges from a source node
 func (g *Graph) GetEdges(source string) []string {
 	g.mu.RLock()
@@ -104,3 +168,18 @@ func (g *Graph) EdgeCount() int {
 	return count
 }
 
+// InlinkCounts returns, for every target, the number of other nodes linking to it
+func (g *Graph) InlinkCounts() map[string]int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	counts := make(map[string]int)
+	for source, targets := range g.edges {
+		for _, target := range targets {
+			if target != source {
+				counts[target]++
+			}
+		}
+	}
+	return counts
+}
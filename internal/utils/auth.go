@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AuthConfig holds credentials for crawling sites behind authentication (e.g. staging sites).
+// Headers and basic auth are only sent to the crawled site, never to other hosts.
+type AuthConfig struct {
+	Headers       map[string]string `json:"headers,omitempty"`        // Extra request headers
+	CookieFile    string            `json:"cookie_file,omitempty"`    // Netscape cookies.txt file used to seed the cookie jar
+	Cookies       string            `json:"cookies,omitempty"`        // Cookies in Netscape cookies.txt format (used for stored project credentials)
+	BasicUsername string            `json:"basic_username,omitempty"` // HTTP basic auth username
+	BasicPassword string            `json:"basic_password,omitempty"` // HTTP basic auth password
+	LoginURL      string            `json:"login_url,omitempty"`      // Page with a login form, submitted before the crawl starts
+	LoginFields   map[string]string `json:"login_fields,omitempty"`   // Form fields to fill in (e.g. username and password)
+}
+
+// IsZero reports whether no credentials are configured
+func (a AuthConfig) IsZero() bool {
+	return len(a.Headers) == 0 && a.CookieFile == "" && a.Cookies == "" &&
+		a.BasicUsername == "" && a.BasicPassword == "" && a.LoginURL == "" && len(a.LoginFields) == 0
+}
+
+// ParseHeader parses a header written as "Name: value"
+func ParseHeader(raw string) (string, string, error) {
+	name, value, ok := strings.Cut(raw, ":")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" {
+		return "", "", fmt.Errorf("invalid header %q: expected \"Name: value\"", raw)
+	}
+	return name, strings.TrimSpace(value), nil
+}
+
+// ParseKeyValue parses a pair written as "key=value"
+func ParseKeyValue(raw string) (string, string, error) {
+	key, value, ok := strings.Cut(raw, "=")
+	if !ok || key == "" {
+		return "", "", fmt.Errorf("invalid value %q: expected key=value", raw)
+	}
+	return key, value, nil
+}
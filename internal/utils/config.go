@@ -4,37 +4,63 @@ import (
 	"time"
 )
 
+const (
+	// DefaultMobileUserAgent is the smartphone agent used for the mobile side of a parity crawl
+	DefaultMobileUserAgent = "Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Mobile Safari/537.36 (compatible; barracuda/1.0.0)"
+	// DefaultDesktopUserAgent is the desktop agent used for the desktop side of a parity crawl
+	DefaultDesktopUserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36 (compatible; barracuda/1.0.0)"
+)
+
 // Config holds all crawl configuration settings
 type Config struct {
-	StartURL         string
-	MaxDepth         int
-	MaxPages         int
-	DomainFilter     string // "same" or "all"
-	Workers          int
-	Delay            time.Duration
-	Timeout          time.Duration
-	UserAgent        string
-	RespectRobots    bool
-	ParseSitemap     bool
-	CrawlSitemapOnly bool   // When true and ParseSitemap enabled: crawl only sitemap URLs, no link discovery (like indexed pages)
-	ExportFormat     string // "csv" or "json"
-	ExportPath       string
+	StartURL            string
+	URLList             []string // List mode: crawl exactly these URLs at depth 0, without sitemap or link discovery
+	MaxDepth            int
+	MaxPages            int
+	DomainFilter        string    // "same" or "all"
+	SubdomainScope      string    // "none" (start host only) or "all" (every subdomain of the start domain)
+	URLRules            []URLRule // Ordered include/exclude rules on URL path and query; first match wins
+	StripQueryParams    []string  // Query parameters removed before URLs are queued (globs, "*" for all)
+	Workers             int
+	Delay               time.Duration
+	Timeout             time.Duration
+	UserAgent           string
+	Parity              bool   // Fetch every URL as both a smartphone and a desktop agent and compare the versions
+	MobileUserAgent     string // Smartphone agent for parity crawls (default: DefaultMobileUserAgent)
+	DesktopUserAgent    string // Desktop agent for parity crawls (default: DefaultDesktopUserAgent)
+	RespectRobots       bool
+	ParseSitemap        bool
+	CrawlSitemapOnly    bool       // When true and ParseSitemap enabled: crawl only sitemap URLs, no link discovery (like indexed pages)
+	RenderMode          string     // "static" (raw HTML) or "js" (headless browser)
+	Auth                AuthConfig // Headers, cookies, basic auth and form login for protected sites
+	CrawlOrder          string     // "bfs", "dfs" or "sitemap-first"
+	FrontierMemoryLimit int        // Queued URLs kept in memory before spilling to disk (default: 50000)
+	Incremental         bool       // Re-crawl against a previous crawl: conditional requests, unchanged pages reused
+	PageWeightBudgetKB  int        // Pages heavier than this (HTML plus subresources) are reported; 0 uses the analyzer default
+	DuplicateSimilarity float64    // Share of matching SimHash bits at which pages are near duplicates; 0 uses the analyzer default
+	ExportFormat        string     // "csv" or "json"
+	ExportPath          string
+	StateDir            string        // Directory for crawl checkpoints; empty disables checkpointing
+	CheckpointInterval  time.Duration // How often checkpoints are written (default: 30s)
 }
 
 // DefaultConfig returns a Config with sensible defaults
 func DefaultConfig() *Config {
 	return &Config{
-		MaxDepth:      3,
-		MaxPages:      1000,
-		DomainFilter:  "same",
-		Workers:       10,
-		Delay:         0,
-		Timeout:       30 * time.Second,
-		UserAgent:     "barracuda/1.0.0",
-		RespectRobots: true,
-		ParseSitemap:  false,
-		ExportFormat:  "csv",
-		ExportPath:    "",
+		MaxDepth:       3,
+		MaxPages:       1000,
+		DomainFilter:   "same",
+		SubdomainScope: "none",
+		Workers:        10,
+		Delay:          0,
+		Timeout:        30 * time.Second,
+		UserAgent:      "barracuda/1.0.0",
+		RespectRobots:  true,
+		ParseSitemap:   false,
+		RenderMode:     "static",
+		CrawlOrder:     "bfs",
+		ExportFormat:   "csv",
+		ExportPath:     "",
 	}
 }
 
@@ -55,5 +81,19 @@ func (c *Config) Validate() error {
 	if c.ExportFormat != "csv" && c.ExportFormat != "json" {
 		return ErrInvalidExportFormat
 	}
+	if c.RenderMode != "" && c.RenderMode != "static" && c.RenderMode != "js" {
+		return ErrInvalidRenderMode
+	}
+	if c.SubdomainScope != "" && c.SubdomainScope != SubdomainScopeNone && c.SubdomainScope != SubdomainScopeAll {
+		return ErrInvalidSubdomainScope
+	}
+	for _, rule := range c.URLRules {
+		if _, err := rule.compile(); err != nil {
+			return err
+		}
+	}
+	if c.CrawlOrder != "" && c.CrawlOrder != "bfs" && c.CrawlOrder != "dfs" && c.CrawlOrder != "sitemap-first" {
+		return ErrInvalidCrawlOrder
+	}
 	return nil
 }
@@ -366,6 +366,45 @@ func PromptInteractive() (*Config, string, string, bool, error) {
 	// When using sitemap, default to crawl-sitemap-only (like indexed pages)
 	crawlSitemapOnly := true
 
+	// Optional crawl scope: include/exclude patterns, subdomains and query stripping
+	subdomainScope := SubdomainScopeNone
+	var urlRules []URLRule
+	var stripParams []string
+
+	limitScope, err := PromptBool("Limit which URLs are crawled (paths, subdomains, query parameters)?", false)
+	if err != nil {
+		return nil, "", "", false, err
+	}
+
+	if limitScope {
+		includes, err := PromptString("Only crawl paths matching (comma-separated, e.g. /blog/*)", "", false)
+		if err != nil {
+			return nil, "", "", false, err
+		}
+		excludes, err := PromptString("Skip paths matching (comma-separated, e.g. /cart*,*sort=*)", "", false)
+		if err != nil {
+			return nil, "", "", false, err
+		}
+		urlRules, err = BuildURLRules(nil, splitList(includes), splitList(excludes))
+		if err != nil {
+			return nil, "", "", false, err
+		}
+
+		includeSubdomains, err := PromptBool("Include subdomains?", false)
+		if err != nil {
+			return nil, "", "", false, err
+		}
+		if includeSubdomains {
+			subdomainScope = SubdomainScopeAll
+		}
+
+		strip, err := PromptString("Query parameters to ignore (comma-separated, e.g. utm_*,sort, or * for all)", "", false)
+		if err != nil {
+			return nil, "", "", false, err
+		}
+		stripParams = splitList(strip)
+	}
+
 	// Always export link graph (no prompt)
 	graphExport := filepath.Join(crawlDir, "graph.json")
 
@@ -392,7 +431,21 @@ func PromptInteractive() (*Config, string, string, bool, error) {
 		ExportFormat:     format,
 		ExportPath:       exportPath,
 		DomainFilter:     "same",
+		SubdomainScope:   subdomainScope,
+		URLRules:         urlRules,
+		StripQueryParams: stripParams,
 	}
 
 	return config, graphExport, crawlDir, openBrowser, nil
 }
+
+// splitList splits a comma-separated answer into trimmed, non-empty values
+func splitList(input string) []string {
+	var values []string
+	for _, value := range strings.Split(input, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
@@ -0,0 +1,251 @@
+package utils
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const (
+	// RuleInclude keeps URLs matched by a rule in the crawl
+	RuleInclude = "include"
+	// RuleExclude drops URLs matched by a rule from the crawl
+	RuleExclude = "exclude"
+
+	// SubdomainScopeNone crawls only the start host (www and non-www are treated as the same host)
+	SubdomainScopeNone = "none"
+	// SubdomainScopeAll also crawls every subdomain of the start domain
+	SubdomainScopeAll = "all"
+
+	// regexRulePrefix marks a rule pattern as a regular expression instead of a glob
+	regexRulePrefix = "re:"
+)
+
+// URLRule is an include or exclude rule matched against a URL's path and query (e.g. "/blog/*?page=2").
+// Patterns are globs where * matches any run of characters, or regular expressions when prefixed with "re:".
+type URLRule struct {
+	Action  string `json:"action"`  // "include" or "exclude"
+	Pattern string `json:"pattern"` // Glob, or "re:" followed by a regular expression
+}
+
+// ParseURLRule parses a rule written as "include:<pattern>" or "exclude:<pattern>"
+func ParseURLRule(raw string) (URLRule, error) {
+	action, pattern, ok := strings.Cut(raw, ":")
+	if !ok || pattern == "" {
+		return URLRule{}, fmt.Errorf("invalid URL rule %q: expected include:<pattern> or exclude:<pattern>", raw)
+	}
+
+	rule := URLRule{Action: strings.ToLower(strings.TrimSpace(action)), Pattern: pattern}
+	if _, err := rule.compile(); err != nil {
+		return URLRule{}, err
+	}
+	return rule, nil
+}
+
+// BuildURLRules combines ordered "include:/exclude:" rules with plain include and exclude patterns.
+// Ordered rules come first, then excludes, then includes, so an exclude always beats a broader include.
+func BuildURLRules(ordered, includes, excludes []string) ([]URLRule, error) {
+	rules := make([]URLRule, 0, len(ordered)+len(includes)+len(excludes))
+	for _, raw := range ordered {
+		rule, err := ParseURLRule(raw)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	for _, pattern := range excludes {
+		rule, err := ParseURLRule(RuleExclude + ":" + pattern)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	for _, pattern := range includes {
+		rule, err := ParseURLRule(RuleInclude + ":" + pattern)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// compile turns the rule pattern into a regular expression
+func (r URLRule) compile() (*regexp.Regexp, error) {
+	if r.Action != RuleInclude && r.Action != RuleExclude {
+		return nil, fmt.Errorf("invalid URL rule action %q: must be 'include' or 'exclude'", r.Action)
+	}
+
+	if expr, ok := strings.CutPrefix(r.Pattern, regexRulePrefix); ok {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid URL rule regex %q: %w", expr, err)
+		}
+		return re, nil
+	}
+
+	return globToRegexp(r.Pattern), nil
+}
+
+// globToRegexp converts a glob where * matches anything into an anchored regular expression
+func globToRegexp(glob string) *regexp.Regexp {
+	parts := strings.Split(glob, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	return regexp.MustCompile("^" + strings.Join(parts, ".*") + "$")
+}
+
+// URLScope decides which discovered URLs belong to a crawl and cleans them before they are queued
+type URLScope struct {
+	startHost    string
+	domainFilter string
+	subdomains   bool
+	rules        []compiledRule
+	hasInclude   bool
+	stripParams  []*regexp.Regexp
+	stripAll     bool
+}
+
+// compiledRule is a URLRule with its compiled pattern
+type compiledRule struct {
+	include bool
+	pattern *regexp.Regexp
+}
+
+// NewURLScope builds the URL scope for a crawl configuration
+func NewURLScope(config *Config) (*URLScope, error) {
+	startURL, err := url.Parse(config.StartURL)
+	if err != nil {
+		return nil, ErrInvalidURL
+	}
+
+	scope := &URLScope{
+		startHost:    strings.ToLower(startURL.Hostname()),
+		domainFilter: config.DomainFilter,
+		subdomains:   config.SubdomainScope == SubdomainScopeAll,
+	}
+
+	for _, rule := range config.URLRules {
+		re, err := rule.compile()
+		if err != nil {
+			return nil, err
+		}
+		scope.rules = append(scope.rules, compiledRule{include: rule.Action == RuleInclude, pattern: re})
+		if rule.Action == RuleInclude {
+			scope.hasInclude = true
+		}
+	}
+
+	for _, param := range config.StripQueryParams {
+		if param == "*" {
+			scope.stripAll = true
+			continue
+		}
+		scope.stripParams = append(scope.stripParams, globToRegexp(param))
+	}
+
+	return scope, nil
+}
+
+// IncludesSubdomains reports whether links to other subdomains of the start domain are crawled
+func (s *URLScope) IncludesSubdomains() bool {
+	return s.subdomains && s.domainFilter == "same"
+}
+
+// InDomain reports whether a URL's host is the start host or, with subdomain scope "all", one of its subdomains
+func (s *URLScope) InDomain(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	return s.hostAllowed(strings.ToLower(u.Hostname()))
+}
+
+// Allows reports whether a URL is in scope. When it is not, reason explains why ("domain" or "rule").
+func (s *URLScope) Allows(rawURL string) (allowed bool, reason string) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false, "invalid"
+	}
+
+	if s.domainFilter == "same" && !s.hostAllowed(strings.ToLower(u.Hostname())) {
+		return false, "domain"
+	}
+
+	if !s.rulesAllow(u) {
+		return false, "rule"
+	}
+	return true, ""
+}
+
+// hostAllowed checks a host against the start host and subdomain scope
+func (s *URLScope) hostAllowed(host string) bool {
+	base := strings.TrimPrefix(s.startHost, "www.")
+	if strings.TrimPrefix(host, "www.") == base {
+		return true
+	}
+	return s.subdomains && strings.HasSuffix(host, "."+base)
+}
+
+// rulesAllow applies the ordered rules: the first matching rule decides. URLs that match no rule
+// are kept unless at least one include rule exists.
+func (s *URLScope) rulesAllow(u *url.URL) bool {
+	if len(s.rules) == 0 {
+		return true
+	}
+
+	target := u.EscapedPath()
+	if target == "" {
+		target = "/"
+	}
+	if u.RawQuery != "" {
+		target += "?" + u.RawQuery
+	}
+
+	for _, rule := range s.rules {
+		if rule.pattern.MatchString(target) {
+			return rule.include
+		}
+	}
+	return !s.hasInclude
+}
+
+// Clean removes stripped query parameters from a URL and normalizes it
+func (s *URLScope) Clean(rawURL string) string {
+	if !s.stripAll && len(s.stripParams) == 0 {
+		return rawURL
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil || u.RawQuery == "" {
+		return rawURL
+	}
+
+	if s.stripAll {
+		u.RawQuery = ""
+	} else {
+		query := u.Query()
+		stripped := false
+		for name := range query {
+			for _, param := range s.stripParams {
+				if param.MatchString(name) {
+					query.Del(name)
+					stripped = true
+					break
+				}
+			}
+		}
+		if !stripped {
+			return rawURL
+		}
+		u.RawQuery = query.Encode()
+	}
+
+	cleaned, err := NormalizeURL(u.String())
+	if err != nil {
+		return rawURL
+	}
+	return cleaned
+}
@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"testing"
+)
+
+func TestURLScopeAllows(t *testing.T) {
+	config := &Config{
+		StartURL:       "https://www.example.com",
+		DomainFilter:   "same",
+		SubdomainScope: SubdomainScopeNone,
+	}
+
+	rules, err := BuildURLRules(
+		[]string{"include:/blog/archive/keep*"},
+		[]string{"/blog/*", "re:^/docs/v[0-9]+/"},
+		[]string{"/blog/archive/*", "*sort=*"},
+	)
+	if err != nil {
+		t.Fatalf("BuildURLRules() error = %v", err)
+	}
+	config.URLRules = rules
+
+	scope, err := NewURLScope(config)
+	if err != nil {
+		t.Fatalf("NewURLScope() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		url      string
+		expected bool
+	}{
+		{name: "Included by glob", url: "https://example.com/blog/post", expected: true},
+		{name: "Included by regex", url: "https://example.com/docs/v2/intro", expected: true},
+		{name: "Not included", url: "https://example.com/about", expected: false},
+		{name: "Excluded section", url: "https://example.com/blog/archive/2019", expected: false},
+		{name: "Ordered rule beats exclude", url: "https://example.com/blog/archive/keep-this", expected: true},
+		{name: "Excluded query facet", url: "https://example.com/blog/post?sort=asc", expected: false},
+		{name: "Other subdomain", url: "https://shop.example.com/blog/post", expected: false},
+		{name: "Other domain", url: "https://other.com/blog/post", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _ := scope.Allows(tt.url); got != tt.expected {
+				t.Errorf("Allows(%q) = %v, expected %v", tt.url, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestURLScopeSubdomains(t *testing.T) {
+	scope, err := NewURLScope(&Config{
+		StartURL:       "https://www.example.com",
+		DomainFilter:   "same",
+		SubdomainScope: SubdomainScopeAll,
+	})
+	if err != nil {
+		t.Fatalf("NewURLScope() error = %v", err)
+	}
+
+	if allowed, _ := scope.Allows("https://blog.example.com/post"); !allowed {
+		t.Error("expected subdomain to be in scope")
+	}
+	if allowed, _ := scope.Allows("https://notexample.com/post"); allowed {
+		t.Error("expected lookalike domain to be out of scope")
+	}
+}
+
+func TestURLScopeClean(t *testing.T) {
+	scope, err := NewURLScope(&Config{
+		StartURL:         "https://example.com",
+		StripQueryParams: []string{"utm_*", "sort"},
+	})
+	if err != nil {
+		t.Fatalf("NewURLScope() error = %v", err)
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: "https://example.com/page?utm_source=x&utm_medium=y", expected: "https://example.com/page"},
+		{input: "https://example.com/page?id=1&sort=asc", expected: "https://example.com/page?id=1"},
+		{input: "https://example.com/page?id=1", expected: "https://example.com/page?id=1"},
+	}
+
+	for _, tt := range tests {
+		if got := scope.Clean(tt.input); got != tt.expected {
+			t.Errorf("Clean(%q) = %q, expected %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestParseURLRule(t *testing.T) {
+	invalid := []string{"/blog/*", "allow:/blog/*", "exclude:", "exclude:re:("}
+	for _, raw := range invalid {
+		if _, err := ParseURLRule(raw); err == nil {
+			t.Errorf("ParseURLRule(%q) expected error", raw)
+		}
+	}
+}
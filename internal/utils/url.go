@@ -13,6 +13,9 @@ var (
 	ErrInvalidMaxPages = errors.New("max pages must be at least 1")
 	ErrInvalidWorkers  = errors.New("workers must be at least 1")
 	ErrInvalidExportFormat = errors.New("export format must be 'csv' or 'json'")
+	ErrInvalidRenderMode   = errors.New("render mode must be 'static' or 'js'")
+	ErrInvalidCrawlOrder   = errors.New("crawl order must be 'bfs', 'dfs' or 'sitemap-first'")
+	ErrInvalidSubdomainScope = errors.New("subdomain scope must be 'none' or 'all'")
 )
 
 // NormalizeURL normalizes a URL by removing fragments and trailing slashes
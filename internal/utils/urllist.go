@@ -0,0 +1,162 @@
+package utils
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// urlColumnNames are CSV headers recognised as the URL column when none is selected
+// (plain exports, Screaming Frog "Address", Search Console "Top pages", analytics landing pages)
+var urlColumnNames = []string{"url", "urls", "address", "page", "pages", "top pages", "landing page", "loc"}
+
+// LoadURLList reads the URLs for a list-mode crawl from a file, or from stdin when path is "-".
+// Files ending in .csv, or any input when column is set, are read as CSV; otherwise each line is a URL.
+// It returns the unique, normalized URLs and the number of entries skipped because they are not http(s) URLs.
+func LoadURLList(path, column string) ([]string, int, error) {
+	var r io.Reader
+	if path == "-" {
+		r = os.Stdin
+	} else {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to open URL list: %w", err)
+		}
+		defer file.Close()
+		r = file
+	}
+
+	if column != "" || strings.EqualFold(filepath.Ext(path), ".csv") {
+		return ParseURLListCSV(r, column)
+	}
+	return ParseURLList(r)
+}
+
+// ParseURLList reads one URL per line. Blank lines and lines starting with # are ignored.
+func ParseURLList(r io.Reader) ([]string, int, error) {
+	list := newURLList()
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		list.add(line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to read URL list: %w", err)
+	}
+	return list.urls, list.skipped, nil
+}
+
+// NormalizeURLList normalizes and de-duplicates URLs given directly, such as in an API request.
+// It returns the number of entries skipped because they are not http(s) URLs.
+func NormalizeURLList(raw []string) ([]string, int) {
+	list := newURLList()
+	for _, entry := range raw {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			list.add(entry)
+		}
+	}
+	return list.urls, list.skipped
+}
+
+// ParseURLListCSV reads URLs from one column of a CSV with a header row. The column is a header name
+// (case-insensitive) or a 1-based index; when empty, a column with a common URL header is used.
+func ParseURLListCSV(r io.Reader, column string) ([]string, int, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	index, err := urlColumnIndex(header, column)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	list := newURLList()
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to read CSV: %w", err)
+		}
+		if index < len(record) && strings.TrimSpace(record[index]) != "" {
+			list.add(strings.TrimSpace(record[index]))
+		}
+	}
+	return list.urls, list.skipped, nil
+}
+
+// urlColumnIndex resolves the CSV column holding URLs
+func urlColumnIndex(header []string, column string) (int, error) {
+	for i := range header {
+		header[i] = strings.TrimSpace(strings.TrimPrefix(header[i], "\ufeff"))
+	}
+
+	if column != "" {
+		if n, err := strconv.Atoi(column); err == nil {
+			if n < 1 || n > len(header) {
+				return 0, fmt.Errorf("CSV column %d out of range (file has %d columns)", n, len(header))
+			}
+			return n - 1, nil
+		}
+		for i, name := range header {
+			if strings.EqualFold(name, column) {
+				return i, nil
+			}
+		}
+		return 0, fmt.Errorf("CSV column %q not found (columns: %s)", column, strings.Join(header, ", "))
+	}
+
+	for _, candidate := range urlColumnNames {
+		for i, name := range header {
+			if strings.EqualFold(name, candidate) {
+				return i, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("no URL column found in CSV header (columns: %s); select one with --list-column", strings.Join(header, ", "))
+}
+
+// urlList collects unique normalized http(s) URLs in input order
+type urlList struct {
+	urls    []string
+	seen    map[string]bool
+	skipped int
+}
+
+// newURLList creates an empty URL list
+func newURLList() *urlList {
+	return &urlList{seen: make(map[string]bool)}
+}
+
+// add normalizes and appends a URL, counting entries that are not http(s) URLs as skipped
+func (l *urlList) add(raw string) {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		l.skipped++
+		return
+	}
+	normalized, err := NormalizeURL(raw)
+	if err != nil {
+		l.skipped++
+		return
+	}
+	if !l.seen[normalized] {
+		l.seen[normalized] = true
+		l.urls = append(l.urls, normalized)
+	}
+}
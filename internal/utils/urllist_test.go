@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseURLList(t *testing.T) {
+	input := "# migration sheet\nhttps://example.com/a\n\nhttps://example.com/a/\nnot a url\nftp://example.com/file\n  https://example.com/b  \n"
+
+	urls, skipped, err := ParseURLList(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseURLList() error = %v", err)
+	}
+	want := []string{"https://example.com/a", "https://example.com/b"}
+	if !reflect.DeepEqual(urls, want) {
+		t.Errorf("ParseURLList() urls = %v, want %v", urls, want)
+	}
+	if skipped != 2 {
+		t.Errorf("ParseURLList() skipped = %d, want 2", skipped)
+	}
+}
+
+func TestParseURLListCSV(t *testing.T) {
+	input := "\ufeffTop pages,Clicks,Impressions\nhttps://example.com/a,10,100\nhttps://example.com/b,5,80\n"
+
+	tests := []struct {
+		name    string
+		column  string
+		want    []string
+		wantErr bool
+	}{
+		{name: "Detected header", column: "", want: []string{"https://example.com/a", "https://example.com/b"}},
+		{name: "Header name", column: "top pages", want: []string{"https://example.com/a", "https://example.com/b"}},
+		{name: "Index", column: "1", want: []string{"https://example.com/a", "https://example.com/b"}},
+		{name: "Wrong column", column: "Clicks", want: nil},
+		{name: "Unknown column", column: "Address", wantErr: true},
+		{name: "Index out of range", column: "4", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			urls, _, err := ParseURLListCSV(strings.NewReader(input), tt.column)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseURLListCSV() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(urls, tt.want) {
+				t.Errorf("ParseURLListCSV() = %v, want %v", urls, tt.want)
+			}
+		})
+	}
+}
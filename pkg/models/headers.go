@@ -0,0 +1,21 @@
+package models
+
+// ResponseHeaders is the normalized set of response headers kept for a page. Repeated headers
+// are joined with ", " and values are trimmed; absent headers are empty.
+type ResponseHeaders struct {
+	ContentType             string `json:"content_type,omitempty"`
+	ContentEncoding         string `json:"content_encoding,omitempty"` // Lowercased, e.g. "gzip" or "br"
+	ContentLength           int64  `json:"content_length,omitempty"`   // Content-Length as sent (compressed size); 0 when not sent
+	CacheControl            string `json:"cache_control,omitempty"`
+	Expires                 string `json:"expires,omitempty"`
+	Vary                    string `json:"vary,omitempty"`
+	Link                    string `json:"link,omitempty"`
+	LinkCanonical           string `json:"link_canonical,omitempty"` // rel="canonical" target of the Link header, resolved against the page URL
+	StrictTransportSecurity string `json:"strict_transport_security,omitempty"`
+	ContentSecurityPolicy   string `json:"content_security_policy,omitempty"`
+	XFrameOptions           string `json:"x_frame_options,omitempty"`
+	XContentTypeOptions     string `json:"x_content_type_options,omitempty"`
+	ReferrerPolicy          string `json:"referrer_policy,omitempty"`
+	PermissionsPolicy       string `json:"permissions_policy,omitempty"`
+	Server                  string `json:"server,omitempty"`
+}
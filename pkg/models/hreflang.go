@@ -0,0 +1,15 @@
+package models
+
+// Hreflang sources
+const (
+	HreflangHTML    = "html"    // <link rel="alternate" hreflang> in the page
+	HreflangHeader  = "header"  // Link: <url>; rel="alternate"; hreflang="..." response header
+	HreflangSitemap = "sitemap" // xhtml:link alternate in the sitemap entry
+)
+
+// Hreflang is an alternate language version a page declares
+type Hreflang struct {
+	Lang   string `json:"lang"` // Language(-region) code as written, e.g. "en-GB", or "x-default"
+	URL    string `json:"url"`
+	Source string `json:"source"` // "html", "header" or "sitemap"
+}
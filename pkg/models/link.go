@@ -0,0 +1,57 @@
+package models
+
+import "strings"
+
+// Link positions: the part of the page a link was found in
+const (
+	LinkPositionNav     = "nav"     // <nav> or role="navigation"
+	LinkPositionHeader  = "header"  // Site header outside the main content
+	LinkPositionFooter  = "footer"  // Site footer outside the main content
+	LinkPositionSidebar = "sidebar" // <aside> or role="complementary"
+	LinkPositionContent = "content" // Anywhere else
+)
+
+// Link is an <a href> found on a page. PageResult.InternalLinks and ExternalLinks keep the
+// distinct target URLs; Links keeps each link with its anchor text, rel and position.
+type Link struct {
+	Source   string   `json:"source,omitempty"` // Page the link was found on; set on graph edges
+	URL      string   `json:"url"`              // Normalized target URL
+	Anchor   string   `json:"anchor,omitempty"` // Anchor text with whitespace collapsed
+	Alt      string   `json:"alt,omitempty"`    // Alt text of images inside the link
+	Rel      []string `json:"rel,omitempty"`    // rel values, lowercased (nofollow, sponsored, ugc, ...)
+	Target   string   `json:"target,omitempty"` // target attribute, e.g. "_blank"
+	Position string   `json:"position"`
+	Internal bool     `json:"internal"`
+}
+
+// HasRel reports whether the link has the given rel value
+func (l Link) HasRel(value string) bool {
+	for _, rel := range l.Rel {
+		if strings.EqualFold(rel, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// Nofollow reports whether the link asks search engines not to follow it: rel="nofollow",
+// or the more specific "sponsored" and "ugc" hints
+func (l Link) Nofollow() bool {
+	return l.HasRel("nofollow") || l.HasRel("sponsored") || l.HasRel("ugc")
+}
+
+// LinksOrTargets returns links, or when there are none, a link without anchor text or position
+// for each internal and external target URL. Pages stored before link details were kept (and
+// pages reused from such a crawl) only have the URL lists.
+func LinksOrTargets(links []Link, internal, external []string) []Link {
+	if len(links) > 0 {
+		return links
+	}
+	for _, target := range internal {
+		links = append(links, Link{URL: target, Internal: true})
+	}
+	for _, target := range external {
+		links = append(links, Link{URL: target})
+	}
+	return links
+}
@@ -19,6 +19,7 @@ type PageResult struct {
 	URL                string             `json:"url"`
 	StatusCode         int                `json:"status_code"`
 	ResponseTime       int64              `json:"response_time_ms"` // Duration in milliseconds
+	Timing             *Timing            `json:"timing,omitempty"` // DNS, connect, TLS, TTFB and download durations of the final request
 	Title              string             `json:"title"`
 	MetaDesc           string             `json:"meta_description"`
 	Canonical          string             `json:"canonical"`
@@ -30,15 +31,55 @@ type PageResult struct {
 	H6                 []string           `json:"h6"`
 	InternalLinks      []string           `json:"internal_links"`
 	ExternalLinks      []string           `json:"external_links"`
+	Links              []Link             `json:"links,omitempty"` // Every distinct link with its anchor text, rel and position
 	Images             []Image            `json:"images,omitempty"`
-	RedirectChain      []string           `json:"redirect_chain,omitempty"`
+	RedirectChain      []string           `json:"redirect_chain,omitempty"`     // Redirect destinations, in order
+	RedirectHops       []RedirectHop      `json:"redirect_hops,omitempty"`      // Status, Location and timing of each redirect
+	RedirectLoop       bool               `json:"redirect_loop,omitempty"`      // Redirects led back to a URL already visited
+	TooManyRedirects   bool               `json:"too_many_redirects,omitempty"` // Gave up before the redirects reached a page
 	Error              string             `json:"error,omitempty"`
 	XRobotsTag         string             `json:"x_robots_tag,omitempty"` // HTTP X-Robots-Tag header value
 	MetaRobots         string             `json:"meta_robots,omitempty"`  // HTML meta robots tag value
 	IndexabilityStatus IndexabilityStatus `json:"indexability_status,omitempty"`
+	RenderMode         string             `json:"render_mode,omitempty"`     // "static" or "js"
+	Raw                *RawSnapshot       `json:"raw,omitempty"`             // Pre-JavaScript values, set when RenderMode is "js"
+	ETag               string             `json:"etag,omitempty"`            // ETag response header, sent as If-None-Match on the next crawl
+	LastModified       string             `json:"last_modified,omitempty"`   // Last-Modified response header, sent as If-Modified-Since on the next crawl
+	ContentHash        string             `json:"content_hash,omitempty"`    // SHA-256 of the response body
+	Unchanged          bool               `json:"unchanged,omitempty"`       // Unchanged since the previous crawl (HTTP 304 or same content hash)
+	Device             string             `json:"device,omitempty"`          // "mobile" or "desktop" in parity mode
+	Desktop            *PageResult        `json:"desktop,omitempty"`         // Desktop version in parity mode; the page itself is the mobile version
+	RobotsRule         string             `json:"robots_rule,omitempty"`     // robots.txt rule that matched the URL, with its line number
+	Sitemap            *SitemapEntry      `json:"sitemap,omitempty"`         // Sitemap listing and metadata, when the URL came from a sitemap
+	Headers            *ResponseHeaders   `json:"headers,omitempty"`         // Normalized response headers of the final response
+	TLS                *TLSInfo           `json:"tls,omitempty"`             // TLS details of the host that served the page (HTTPS only)
+	MixedContent       []MixedContent     `json:"mixed_content,omitempty"`   // http:// subresources on an HTTPS page
+	Resources          []Resource         `json:"resources,omitempty"`       // Stylesheets, scripts, fonts, iframes, images and media the page loads
+	Size               int64              `json:"size,omitempty"`            // Response body size in bytes
+	WordCount          int                `json:"word_count,omitempty"`      // Words in the main content, without navigation and other boilerplate
+	TextRatio          float64            `json:"text_ratio,omitempty"`      // Visible text as a percentage of the HTML size
+	TextHash           string             `json:"text_hash,omitempty"`       // SHA-256 of the normalized main content, equal for exact duplicates
+	SimHash            string             `json:"simhash,omitempty"`         // 64-bit SimHash of the main content in hex, close for near duplicates
+	StructuredData     []StructuredData   `json:"structured_data,omitempty"` // JSON-LD, Microdata and RDFa items
+	Hreflang           []Hreflang         `json:"hreflang,omitempty"`        // Alternate language versions from the HTML and Link header
+	HTMLLang           string             `json:"html_lang,omitempty"`       // lang attribute of <html>
+	Social             *SocialMeta        `json:"social,omitempty"`          // Open Graph and Twitter Card tags
 	CrawledAt          time.Time          `json:"crawled_at"`
 }
 
+// RawSnapshot holds SEO values parsed from the raw server HTML before JavaScript rendering.
+// Comparing it with the rendered values shows content that only exists after JS executes.
+type RawSnapshot struct {
+	Title              string   `json:"title"`
+	MetaDesc           string   `json:"meta_description"`
+	Canonical          string   `json:"canonical"`
+	MetaRobots         string   `json:"meta_robots,omitempty"`
+	H1                 []string `json:"h1"`
+	InternalLinksCount int      `json:"internal_links_count"`
+	ExternalLinksCount int      `json:"external_links_count"`
+	RenderError        string   `json:"render_error,omitempty"`
+}
+
 // Image represents an image found on a page
 type Image struct {
 	URL string `json:"url"`
@@ -0,0 +1,10 @@
+package models
+
+// RedirectHop is a redirect response followed while fetching a page
+type RedirectHop struct {
+	URL        string `json:"url"`         // URL that answered with the redirect
+	StatusCode int    `json:"status_code"` // 301, 302, 303, 307 or 308
+	Location   string `json:"location"`    // Location header as sent
+	Target     string `json:"target"`      // Location resolved against URL
+	DurationMs int64  `json:"duration_ms"` // Time to receive the redirect response
+}
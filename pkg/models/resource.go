@@ -0,0 +1,22 @@
+package models
+
+// Resource types collected from a page
+const (
+	ResourceStylesheet = "stylesheet"
+	ResourceScript     = "script"
+	ResourceFont       = "font"
+	ResourceIframe     = "iframe"
+	ResourceImage      = "image"
+	ResourceMedia      = "media" // video, audio and their sources
+)
+
+// Resource is a subresource a page loads: a stylesheet, script, font, iframe, image or media file.
+// StatusCode and Size are filled in by the resource checker after the crawl.
+type Resource struct {
+	URL            string `json:"url"`
+	Type           string `json:"type"`
+	RenderBlocking bool   `json:"render_blocking,omitempty"` // Script in <head> without async, defer or type="module"
+	StatusCode     int    `json:"status_code,omitempty"`
+	Size           int64  `json:"size,omitempty"` // Bytes, from Content-Length or the body read
+	Error          string `json:"error,omitempty"`
+}
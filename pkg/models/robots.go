@@ -0,0 +1,25 @@
+package models
+
+// RobotsHost is the robots.txt fetched for one host during a crawl
+type RobotsHost struct {
+	URL         string   `json:"url"`
+	StatusCode  int      `json:"status_code"`           // 0 when the request failed
+	Error       string   `json:"error,omitempty"`       // Fetch error, if any
+	Unavailable bool     `json:"unavailable,omitempty"` // Server error; the host is treated as disallowed
+	Warnings    []string `json:"warnings,omitempty"`    // Syntax problems, prefixed with their line number
+	Sitemaps    []string `json:"sitemaps,omitempty"`    // Sitemap: lines
+}
+
+// RobotsBlockedURL is a discovered URL that robots.txt disallows (and the crawler skips when respecting robots.txt)
+type RobotsBlockedURL struct {
+	URL        string   `json:"url"`
+	Rule       string   `json:"rule"`                  // Disallow rule that matched, with its line number
+	LinkedFrom []string `json:"linked_from,omitempty"` // Crawled pages linking to the URL
+	Sitemap    string   `json:"sitemap,omitempty"`     // Sitemap file listing the URL, if any
+}
+
+// RobotsReport summarizes how robots.txt affected a crawl
+type RobotsReport struct {
+	Hosts   []RobotsHost       `json:"hosts"`
+	Blocked []RobotsBlockedURL `json:"blocked,omitempty"`
+}
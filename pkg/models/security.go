@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// TLSInfo describes the TLS connection and certificate of a crawled host
+type TLSInfo struct {
+	Host       string    `json:"host"`
+	Version    string    `json:"version"` // Negotiated protocol, e.g. "TLS 1.3"
+	Issuer     string    `json:"issuer"`
+	Subject    string    `json:"subject"`
+	DNSNames   []string  `json:"dns_names,omitempty"` // Subject alternative names
+	NotBefore  time.Time `json:"not_before"`
+	NotAfter   time.Time `json:"not_after"`
+	CoversHost bool      `json:"covers_host"`     // Certificate is valid for Host
+	Error      string    `json:"error,omitempty"` // Certificate verification error, if the certificate was rejected
+}
+
+// MixedContent is an http:// subresource referenced by an HTTPS page
+type MixedContent struct {
+	URL string `json:"url"`
+	Tag string `json:"tag"` // img, script, link or iframe
+}
@@ -0,0 +1,44 @@
+package models
+
+// SitemapEntry is a URL as listed in an XML sitemap, with its optional metadata and extensions
+type SitemapEntry struct {
+	Loc        string             `json:"loc"`
+	Source     string             `json:"source"` // Sitemap file that lists the URL
+	LastMod    string             `json:"lastmod,omitempty"`
+	ChangeFreq string             `json:"changefreq,omitempty"`
+	Priority   string             `json:"priority,omitempty"`
+	Images     []SitemapImage     `json:"images,omitempty"`
+	Videos     []SitemapVideo     `json:"videos,omitempty"`
+	News       *SitemapNews       `json:"news,omitempty"`
+	Alternates []SitemapAlternate `json:"alternates,omitempty"` // xhtml:link hreflang alternates
+}
+
+// SitemapImage is an image:image entry
+type SitemapImage struct {
+	Loc     string `json:"loc"`
+	Title   string `json:"title,omitempty"`
+	Caption string `json:"caption,omitempty"`
+}
+
+// SitemapVideo is a video:video entry
+type SitemapVideo struct {
+	Title        string `json:"title"`
+	Description  string `json:"description,omitempty"`
+	ThumbnailLoc string `json:"thumbnail_loc,omitempty"`
+	ContentLoc   string `json:"content_loc,omitempty"`
+	PlayerLoc    string `json:"player_loc,omitempty"`
+}
+
+// SitemapNews is a news:news entry
+type SitemapNews struct {
+	PublicationName     string `json:"publication_name"`
+	PublicationLanguage string `json:"publication_language,omitempty"`
+	PublicationDate     string `json:"publication_date,omitempty"`
+	Title               string `json:"title"`
+}
+
+// SitemapAlternate is an xhtml:link alternate language version
+type SitemapAlternate struct {
+	Hreflang string `json:"hreflang"`
+	Href     string `json:"href"`
+}
@@ -0,0 +1,27 @@
+package models
+
+// SocialMeta holds the Open Graph and Twitter Card tags that control how a page looks when shared
+type SocialMeta struct {
+	OGTitle            string `json:"og_title,omitempty"`
+	OGDescription      string `json:"og_description,omitempty"`
+	OGImage            string `json:"og_image,omitempty"` // Resolved against the page URL
+	OGURL              string `json:"og_url,omitempty"`   // Resolved against the page URL
+	OGType             string `json:"og_type,omitempty"`
+	OGSiteName         string `json:"og_site_name,omitempty"`
+	TwitterCard        string `json:"twitter_card,omitempty"`
+	TwitterTitle       string `json:"twitter_title,omitempty"`
+	TwitterDescription string `json:"twitter_description,omitempty"`
+	TwitterImage       string `json:"twitter_image,omitempty"` // Resolved against the page URL
+	TwitterSite        string `json:"twitter_site,omitempty"`
+}
+
+// Title returns the title shown when the page is shared: og:title, falling back to twitter:title
+func (s *SocialMeta) Title() string {
+	if s == nil {
+		return ""
+	}
+	if s.OGTitle != "" {
+		return s.OGTitle
+	}
+	return s.TwitterTitle
+}
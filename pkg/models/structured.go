@@ -0,0 +1,16 @@
+package models
+
+// Structured data formats
+const (
+	StructuredDataJSONLD    = "json-ld"
+	StructuredDataMicrodata = "microdata"
+	StructuredDataRDFa      = "rdfa"
+)
+
+// StructuredData is a top-level structured data item found on a page
+type StructuredData struct {
+	Format     string   `json:"format"`               // "json-ld", "microdata" or "rdfa"
+	Types      []string `json:"types,omitempty"`      // schema.org types without the vocabulary, e.g. "Product"
+	Properties []string `json:"properties,omitempty"` // Properties that have a value, e.g. "name", "offers"
+	Error      string   `json:"error,omitempty"`      // Why a JSON-LD block could not be parsed
+}
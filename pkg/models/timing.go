@@ -0,0 +1,12 @@
+package models
+
+// Timing breaks down the request that returned a page (the last request, after any redirects).
+// DNS, Connect and TLS are zero when a kept-alive connection was reused.
+type Timing struct {
+	DNSMs      int64 `json:"dns_ms"`
+	ConnectMs  int64 `json:"connect_ms"`
+	TLSMs      int64 `json:"tls_ms"`
+	TTFBMs     int64 `json:"ttfb_ms"`     // Request start to first response byte, including DNS, connect and TLS
+	DownloadMs int64 `json:"download_ms"` // Reading the response body
+	Reused     bool  `json:"reused_connection,omitempty"`
+}
@@ -0,0 +1,5 @@
+-- Add crawl_rate column to show the effective (throttled) request rate while a crawl runs
+alter table public.crawls
+  add column if not exists crawl_rate numeric;
+
+comment on column public.crawls.crawl_rate is 'Effective request rate in requests per second after per-host throttling. Updated while the crawl is running.';
@@ -0,0 +1,16 @@
+-- Add cache validators and change tracking for incremental re-crawls.
+-- Pages keep the ETag/Last-Modified headers (content_hash already exists) so the next crawl can send
+-- conditional requests and reuse pages the server reports as not modified.
+
+alter table public.pages
+  add column if not exists etag text,
+  add column if not exists last_modified text,
+  add column if not exists unchanged boolean default false;
+
+alter table public.crawls
+  add column if not exists unchanged_pages integer;
+
+comment on column public.pages.etag is 'ETag response header, sent as If-None-Match on the next incremental crawl';
+comment on column public.pages.last_modified is 'Last-Modified response header, sent as If-Modified-Since on the next incremental crawl';
+comment on column public.pages.unchanged is 'True when the page was unchanged since the previous crawl (HTTP 304 or identical content hash)';
+comment on column public.crawls.unchanged_pages is 'Number of pages unchanged since the previous crawl. Set for incremental crawls only.';
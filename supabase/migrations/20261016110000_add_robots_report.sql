@@ -0,0 +1,7 @@
+-- Keep the robots.txt audit of each crawl: the robots.txt fetched per host (status, fetch error,
+-- syntax warnings, sitemaps) and the disallowed URLs with the crawled pages that link to them.
+
+alter table public.crawls
+  add column if not exists robots_report jsonb;
+
+comment on column public.crawls.robots_report is 'robots.txt fetched per host and URLs it disallows, with linking pages (models.RobotsReport)';
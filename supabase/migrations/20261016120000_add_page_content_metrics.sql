@@ -0,0 +1,14 @@
+-- Add content metrics extracted from each page's main text.
+-- word_count already exists; it is now filled in from the main content instead of always being 0.
+
+alter table public.pages
+  add column if not exists text_ratio real,
+  add column if not exists text_hash text,
+  add column if not exists simhash text;
+
+create index if not exists idx_pages_crawl_text_hash on public.pages (crawl_id, text_hash);
+
+comment on column public.pages.word_count is 'Words in the main content, without navigation, sidebars, forms and the site header and footer';
+comment on column public.pages.text_ratio is 'Visible text as a percentage of the HTML size';
+comment on column public.pages.text_hash is 'SHA-256 of the normalized main content text. Pages with the same hash are exact duplicates.';
+comment on column public.pages.simhash is '64-bit SimHash of the main content, in hex. Pages whose SimHashes differ in few bits are near duplicates.';
@@ -0,0 +1,13 @@
+-- Track which API instance is running a web crawl. The owner renews lease_expires_at while
+-- the crawl runs; other instances only resume (or fail) running crawls whose lease has expired.
+
+alter table public.crawls
+  add column if not exists lease_owner text,
+  add column if not exists lease_expires_at timestamptz;
+
+comment on column public.crawls.lease_owner is 'ID of the API instance running the crawl';
+comment on column public.crawls.lease_expires_at is 'When the running crawl may be taken over by another instance unless renewed';
+
+create index if not exists idx_crawls_running_lease
+  on public.crawls (lease_expires_at)
+  where status = 'running';